@@ -4,10 +4,16 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 
+	"regard/internal/config"
 	"regard/internal/domain"
+	"regard/internal/geo"
+	"regard/internal/iana"
 	"regard/internal/output"
 	"regard/internal/query"
+	"regard/internal/routing"
+	"regard/internal/rpki"
 )
 
 func main() {
@@ -15,9 +21,20 @@ func main() {
 		useWhois   = flag.Bool("whois", false, "Force use of WHOIS protocol")
 		useRdap    = flag.Bool("rdap", false, "Force use of RDAP protocol")
 		rawOutput  = flag.Bool("raw", false, "Output raw response without formatting")
+		rawOrig    = flag.Bool("raw-original", false, "With --raw, output WHOIS responses in their original character set")
 		verbose    = flag.Bool("v", false, "Verbose output (full details)")
 		jsonOutput = flag.Bool("json", false, "Output in JSON format")
 		noColor    = flag.Bool("no-color", false, "Disable syntax highlighting")
+		notices    = flag.Bool("notices", false, "Show registry notices, remarks and redacted fields")
+		configPath = flag.String("config", config.DefaultPath(), "Path to the configuration file")
+		rdapServer = flag.String("rdap-server", "", "Force a specific RDAP base URL")
+		whoisHost  = flag.String("whois-server", "", "Force a specific WHOIS server (host[:port])")
+		dnsCheck   = flag.Bool("dns-check", false, "Compare the registry delegation with live DNS")
+		resolver   = flag.String("resolver", "", "DNS resolver for --dns-check and --ptr (host[:port])")
+		ptr        = flag.Bool("ptr", false, "Resolve reverse DNS for IP queries: PTR records, or a block's reverse zone nameservers")
+		tree       = flag.Bool("tree", false, "Show the netblock allocation chain for an IP address")
+		lookup     = flag.Bool("lookup", false, "Query the registries even for special-purpose addresses and ASNs")
+		expand     = flag.Bool("expand", false, "Resolve as-sets and route objects over IRR WHOIS")
 		showHelp   = flag.Bool("help", false, "Show help")
 	)
 	flag.Parse()
@@ -34,24 +51,127 @@ func main() {
 		os.Exit(1)
 	}
 
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	iana.SetCacheDir(cfg.CacheDir)
+
+	// Offline subcommands
+	switch args[0] {
+	case "registrar":
+		runRegistrar(args[1:], !*noColor, *jsonOutput)
+		return
+	case "tld":
+		runTLD(args[1:], !*noColor, *jsonOutput)
+		return
+	}
+
 	queryStr := args[0]
+	nameserverLookup := args[0] == "nameserver"
+	if nameserverLookup {
+		if len(args) < 2 {
+			fmt.Fprintf(os.Stderr, "Error: No nameserver specified\n")
+			os.Exit(1)
+		}
+		if *useWhois {
+			fmt.Fprintf(os.Stderr, "Error: Nameserver lookups use RDAP only\n")
+			os.Exit(1)
+		}
+		queryStr = args[1]
+	}
+
+	// Private, documentation and other special-purpose resources are answered
+	// from the bundled IANA registries unless a lookup is forced
+	if !nameserverLookup && !*lookup {
+		if special, ok := domain.LookupSpecialPurpose(queryStr); ok {
+			if *jsonOutput || *verbose {
+				output.OutputSpecialPurposeJSON(special, !*noColor)
+			} else {
+				output.OutputSpecialPurpose(special, !*noColor)
+			}
+			return
+		}
+	}
+
+	// As-sets exist only in the IRR, so they are always expanded there
+	if !nameserverLookup && query.DetectQueryType(queryStr) == query.QueryTypeASSet {
+		runExpand(queryStr, cfg.IRROptions(), !*noColor, *jsonOutput || *verbose)
+		return
+	}
+
+	rdapOpts, err := cfg.RDAPOptions()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if *rdapServer != "" {
+		rdapOpts.Server = *rdapServer
+	}
+
+	whoisOpts, err := cfg.WhoisOptions()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if *whoisHost != "" {
+		whoisOpts.Server = *whoisHost
+	}
+
+	dnsOpts := cfg.DNSOptions()
+	if *resolver != "" {
+		dnsOpts.Resolver = *resolver
+	}
+
+	// RDAP autnum objects carry no routing policy, so WHOIS supplements ASN summaries
+	var asnWhoisOpts *query.WhoisOptions
+	if !*useRdap {
+		asnWhoisOpts = &whoisOpts
+	}
+
+	var ptrDNSOpts *query.DNSOptions
+	if *ptr {
+		ptrDNSOpts = &dnsOpts
+	}
+
+	var irrOpts *query.IRROptions
+	if *expand {
+		opts := cfg.IRROptions()
+		irrOpts = &opts
+	}
+
+	if *tree {
+		runTree(queryStr, rdapOpts, whoisOpts, *useWhois, *useRdap, !*noColor, *jsonOutput)
+		return
+	}
 
 	var result query.QueryResult
 
 	// Try RDAP first unless WHOIS is explicitly requested
-	if !*useWhois {
-		result = query.PerformRDAPQuery(queryStr)
+	if nameserverLookup {
+		result = query.PerformRDAPNameserverQuery(queryStr, rdapOpts)
+	} else if !*useWhois {
+		result = query.PerformRDAPQueryWithOptions(queryStr, rdapOpts)
 		if !result.Success && !*useRdap {
 			// Fall back to WHOIS if RDAP fails and not forced to use RDAP only
-			result = query.PerformWhoisQuery(queryStr)
+			result = query.PerformWhoisQueryWithOptions(queryStr, whoisOpts)
 		}
 	} else {
-		result = query.PerformWhoisQuery(queryStr)
+		result = query.PerformWhoisQueryWithOptions(queryStr, whoisOpts)
 	}
 
 	// Output the result
-	if *rawOutput {
-		if result.RawData != "" {
+	if *rawOutput || *rawOrig {
+		for _, warning := range result.Warnings {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+		}
+		if *rawOrig && len(result.OriginalData) > 0 {
+			if _, err := os.Stdout.Write(result.OriginalData); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		} else if result.RawData != "" {
 			fmt.Print(result.RawData)
 		} else {
 			fmt.Printf("Error: %s\n", result.Error)
@@ -61,19 +181,246 @@ func main() {
 		output.OutputJSON(result, !*noColor)
 	} else if *jsonOutput {
 		// Summary in JSON format
-		if result.Success {
-			summary := domain.CreateSummary(result)
+		if result.Success && result.Type == string(query.QueryTypeIP) {
+			output.OutputIPSummaryJSON(createIPSummary(result, cfg, ptrDNSOpts), !*noColor)
+		} else if result.Success {
+			summary := createSummary(result, cfg, *dnsCheck, dnsOpts, asnWhoisOpts, irrOpts)
 			output.OutputSummaryJSON(summary, !*noColor)
 		} else {
-			fmt.Printf("{\"error\": \"%s\"}\n", result.Error)
+			output.OutputErrorJSON(result.Error)
 		}
 	} else {
 		// Default: human-readable summary
-		if result.Success {
-			summary := domain.CreateSummary(result)
-			output.OutputSummary(summary, !*noColor)
+		if result.Success && result.Type == string(query.QueryTypeIP) {
+			output.OutputIPSummary(createIPSummary(result, cfg, ptrDNSOpts), !*noColor)
+		} else if result.Success {
+			summary := createSummary(result, cfg, *dnsCheck, dnsOpts, asnWhoisOpts, irrOpts)
+			output.OutputSummary(summary, !*noColor, *notices)
+		} else {
+			fmt.Printf("Error: %s\n", result.Error)
+		}
+	}
+}
+
+// createSummary builds the summary, adding the live DNS comparison when requested,
+// filling gaps in RDAP ASN summaries from WHOIS when asnWhoisOpts is set, adding
+// IRR route objects and as-set expansions when irrOpts is set, and listing an
+// AS's prefixes, validated against a VRP export, when cfg names a routing table
+func createSummary(result query.QueryResult, cfg *config.Config, dnsCheck bool, dnsOpts query.DNSOptions, asnWhoisOpts *query.WhoisOptions, irrOpts *query.IRROptions) domain.Summary {
+	summary := domain.CreateSummary(result)
+	if dnsCheck && result.Type == string(query.QueryTypeDomain) && summary.Status != "available" {
+		summary.DNSCheck = domain.CheckDNS(summary, query.NewDNSClient(dnsOpts))
+	}
+	if asnWhoisOpts != nil && result.Type == string(query.QueryTypeASN) && result.Protocol == "RDAP" {
+		domain.SupplementASN(&summary, query.PerformWhoisQueryWithOptions(result.Query, *asnWhoisOpts))
+	}
+	if irrOpts != nil && summary.ASN != nil {
+		if irr, err := query.DialIRR(*irrOpts); err != nil {
+			summary.Warnings = append(summary.Warnings, err.Error())
+		} else {
+			domain.AddIRRData(&summary, irr, irr.Server)
+			_ = irr.Close()
+		}
+	}
+	routingTable, vrpExport := cfg.RoutingTable(), cfg.VRPExport()
+	if routingTable != "" && summary.ASN != nil {
+		if table, err := routing.LoadCached(routingTable, cfg.CacheDir); err != nil {
+			summary.Warnings = append(summary.Warnings, err.Error())
+		} else {
+			domain.AddOriginatedPrefixes(&summary, table)
+			if vrpExport != "" {
+				if vrps, err := rpki.Load(vrpExport); err != nil {
+					summary.Warnings = append(summary.Warnings, err.Error())
+				} else {
+					domain.AddOriginatedValidity(&summary, vrps)
+				}
+			}
+		}
+	} else if vrpExport != "" && summary.ASN != nil {
+		summary.Warnings = append(summary.Warnings, "RPKI validation needs a routing table (routing.table) for the originated prefixes")
+	}
+	return summary
+}
+
+// createIPSummary builds the network summary, adding the announcing prefix
+// from the configured routing table with its RPKI validity, geolocation
+// from local databases, and live reverse DNS with --ptr
+func createIPSummary(result query.QueryResult, cfg *config.Config, ptrDNSOpts *query.DNSOptions) domain.IPSummary {
+	summary := domain.CreateIPSummary(result)
+
+	if path := cfg.RoutingTable(); path != "" {
+		if table, err := routing.LoadCached(path, cfg.CacheDir); err != nil {
+			summary.Warnings = append(summary.Warnings, err.Error())
+		} else {
+			domain.AddRoute(&summary, table)
+			if vrpExport := cfg.VRPExport(); vrpExport != "" {
+				if vrps, err := rpki.Load(vrpExport); err != nil {
+					summary.Warnings = append(summary.Warnings, err.Error())
+				} else {
+					domain.AddRouteValidity(&summary, vrps)
+				}
+			}
+		}
+	} else if cfg.VRPExport() != "" {
+		summary.Warnings = append(summary.Warnings, "RPKI validation needs a routing table (routing.table) for the origin AS")
+	}
+
+	if geoDatabases := cfg.GeoDatabases(); len(geoDatabases) > 0 {
+		if databases, err := geo.Open(geoDatabases); err != nil {
+			summary.Warnings = append(summary.Warnings, err.Error())
+		} else {
+			defer databases.Close()
+			domain.AddGeolocation(&summary, databases)
+		}
+	}
+
+	if ptrDNSOpts != nil {
+		domain.AddReverseDNS(&summary, query.NewDNSClient(*ptrDNSOpts))
+	}
+	return summary
+}
+
+// runTree shows the allocation chain from the RIR block down to the network
+// holding an address, over RDAP or with a less-specific WHOIS query
+func runTree(queryStr string, rdapOpts query.RDAPOptions, whoisOpts query.WhoisOptions, useWhois, useRdap, useColor, jsonOutput bool) {
+	if query.DetectQueryType(queryStr) != query.QueryTypeIP {
+		fmt.Fprintf(os.Stderr, "Error: --tree needs an IP address or prefix\n")
+		os.Exit(1)
+	}
+
+	var result query.QueryResult
+	if !useWhois {
+		result = query.PerformRDAPQueryWithOptions(queryStr, rdapOpts)
+	}
+	if useWhois || (!result.Success && !useRdap) {
+		result = query.PerformWhoisLessSpecificQuery(queryStr, whoisOpts)
+	}
+
+	if !result.Success {
+		if jsonOutput {
+			output.OutputErrorJSON(result.Error)
 		} else {
 			fmt.Printf("Error: %s\n", result.Error)
 		}
+		return
+	}
+
+	networkTree := domain.BuildNetworkTree(result, query.RDAPClient{Options: rdapOpts})
+	if jsonOutput {
+		output.OutputNetworkTreeJSON(networkTree, useColor)
+	} else {
+		output.OutputNetworkTree(networkTree, useColor)
+	}
+}
+
+// runExpand resolves an as-set to its member AS numbers over IRR WHOIS
+func runExpand(name string, irrOpts query.IRROptions, useColor, jsonOutput bool) {
+	var expansion *domain.ASSetExpansion
+	irr, err := query.DialIRR(irrOpts)
+	if err == nil {
+		defer irr.Close()
+		expansion, err = domain.ExpandASSet(name, irr)
+	}
+	if err != nil {
+		if jsonOutput {
+			output.OutputErrorJSON(err.Error())
+		} else {
+			fmt.Printf("Error: %s\n", err)
+		}
+		return
+	}
+
+	expansion.Server = irr.Server
+	if jsonOutput {
+		output.OutputASSetExpansionJSON(expansion, useColor)
+	} else {
+		output.OutputASSetExpansion(expansion, useColor)
+	}
+}
+
+// runRegistrar looks registrars up in the bundled IANA registrar ID registry
+func runRegistrar(args []string, useColor bool, jsonOutput bool) {
+	fs := flag.NewFlagSet("registrar", flag.ExitOnError)
+	refresh := fs.Bool("refresh", false, "Download the current IANA registrar ID registry")
+	_ = fs.Parse(args)
+
+	if *refresh {
+		count, err := iana.RefreshRegistrars()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "Refreshed IANA registrar registry (%d registrars)\n", count)
+		if fs.NArg() == 0 {
+			return
+		}
+	}
+
+	if fs.NArg() == 0 {
+		fmt.Fprintf(os.Stderr, "Error: No registrar ID or name specified\n")
+		output.PrintUsage()
+		os.Exit(1)
+	}
+
+	term := strings.Join(fs.Args(), " ")
+	registrars := domain.LookupRegistrars(term)
+	if len(registrars) == 0 {
+		message := fmt.Sprintf("no registrar found matching %q", term)
+		if !iana.RegistrarsRefreshed() {
+			message += " in the partial bundled registry; run regard registrar --refresh"
+		}
+		if jsonOutput {
+			output.OutputErrorJSON(message)
+		} else {
+			fmt.Printf("Error: %s\n", message)
+		}
+		os.Exit(1)
+	}
+
+	if jsonOutput {
+		output.OutputRegistrarsJSON(registrars, useColor)
+	} else {
+		output.OutputRegistrars(registrars, useColor)
+	}
+}
+
+// runTLD summarizes a TLD from the bundled IANA root zone data
+func runTLD(args []string, useColor bool, jsonOutput bool) {
+	fs := flag.NewFlagSet("tld", flag.ExitOnError)
+	refresh := fs.Bool("refresh", false, "Download the current IANA root zone database and RDAP bootstrap file")
+	_ = fs.Parse(args)
+
+	if *refresh {
+		count, err := iana.RefreshRootZone()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "Refreshed IANA root zone database (%d TLDs)\n", count)
+		if fs.NArg() == 0 {
+			return
+		}
+	}
+
+	if fs.NArg() == 0 {
+		fmt.Fprintf(os.Stderr, "Error: No TLD specified\n")
+		output.PrintUsage()
+		os.Exit(1)
+	}
+
+	summary, err := domain.CreateTLDSummary(fs.Arg(0))
+	if err != nil {
+		if jsonOutput {
+			output.OutputErrorJSON(err.Error())
+		} else {
+			fmt.Printf("Error: %s\n", err)
+		}
+		os.Exit(1)
+	}
+
+	if jsonOutput {
+		output.OutputSummaryJSON(summary, useColor)
+	} else {
+		output.OutputSummary(summary, useColor, false)
 	}
 }
@@ -4,9 +4,11 @@ go 1.24.5
 
 require (
 	github.com/alecthomas/chroma/v2 v2.20.0
-	github.com/likexian/whois v1.15.6
 	github.com/openrdap/rdap v0.9.1
+	github.com/oschwald/maxminddb-golang v1.13.1
+	golang.org/x/net v0.35.0
 	golang.org/x/term v0.34.0
+	golang.org/x/text v0.22.0
 )
 
 require (
@@ -16,6 +18,5 @@ require (
 	github.com/mitchellh/go-homedir v1.1.0 // indirect
 	github.com/xhit/go-str2duration/v2 v2.1.0 // indirect
 	golang.org/x/crypto v0.33.0 // indirect
-	golang.org/x/net v0.35.0 // indirect
 	golang.org/x/sys v0.35.0 // indirect
 )
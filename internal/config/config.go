@@ -0,0 +1,262 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"regard/internal/query"
+)
+
+// Config holds user settings loaded from the JSON configuration file
+type Config struct {
+	CacheDir string        `json:"cache_dir,omitempty"`
+	RDAP     RDAPConfig    `json:"rdap"`
+	Whois    WhoisConfig   `json:"whois"`
+	DNS      DNSConfig     `json:"dns"`
+	Geo      GeoConfig     `json:"geo"`
+	Routing  RoutingConfig `json:"routing"`
+	RPKI     RPKIConfig    `json:"rpki"`
+	IRR      IRRConfig     `json:"irr"`
+}
+
+// RDAPConfig controls RDAP server discovery
+type RDAPConfig struct {
+	// Server forces a specific RDAP base URL for every query
+	Server string `json:"server,omitempty"`
+	// BootstrapURL is the base URL of the IANA bootstrap registries
+	BootstrapURL string `json:"bootstrap_url,omitempty"`
+	// Refresh sets how long each cached bootstrap registry (dns, ipv4, ipv6, asn) is used
+	Refresh map[string]Duration `json:"refresh,omitempty"`
+	// BootstrapFiles maps a registry name to a local bootstrap-format override file
+	BootstrapFiles map[string]string `json:"bootstrap_files,omitempty"`
+	// Servers maps a TLD or zone, CIDR prefix or ASN range to an RDAP base URL
+	Servers map[string]string `json:"servers,omitempty"`
+}
+
+// WhoisConfig controls which WHOIS servers are queried
+type WhoisConfig struct {
+	// Server forces a specific host[:port] for every WHOIS query
+	Server string `json:"server,omitempty"`
+	// Servers maps a TLD or zone to a WHOIS host[:port]
+	Servers map[string]string `json:"servers,omitempty"`
+	// Templates maps a WHOIS server host to a query template such as "-T dn,ace %s"
+	Templates map[string]string `json:"templates,omitempty"`
+	// Charsets maps a WHOIS server host to the character set of its responses
+	Charsets map[string]string `json:"charsets,omitempty"`
+}
+
+// DNSConfig controls the live DNS checks
+type DNSConfig struct {
+	// Resolver is the recursive resolver host[:port]; the system resolver when unset
+	Resolver string `json:"resolver,omitempty"`
+	// NameserverPort is the port nameservers are queried on directly (default 53)
+	NameserverPort int `json:"nameserver_port,omitempty"`
+	// Timeout bounds each DNS exchange
+	Timeout Duration `json:"timeout,omitempty"`
+}
+
+// GeoConfig lists local geolocation databases for IP summaries
+type GeoConfig struct {
+	// Databases are MaxMind DB (.mmdb) files such as GeoLite2-City, GeoLite2-ASN or
+	// DB-IP; earlier files win where two disagree
+	Databases []string `json:"databases,omitempty"`
+}
+
+// RoutingConfig points at a local routing table dump for origin AS lookups
+type RoutingConfig struct {
+	// Table is a CAIDA pfx2as file or an MRT TABLE_DUMP_V2 RIB dump, optionally
+	// gzip or bzip2 compressed
+	Table string `json:"table,omitempty"`
+}
+
+// RPKIConfig points at a local validated ROA payload export for route origin validation
+type RPKIConfig struct {
+	// VRPs is a JSON export from a relying party such as Routinator
+	// ("routinator vrps --format json") or rpki-client (-j)
+	VRPs string `json:"vrps,omitempty"`
+}
+
+// IRRConfig selects the IRR server --expand resolves as-sets and route objects with
+type IRRConfig struct {
+	// Server is an IRRd-compatible WHOIS server (host[:port]), whois.radb.net by default
+	Server string `json:"server,omitempty"`
+	// Sources restricts queries to these IRR databases, such as RADB or RIPE
+	Sources []string `json:"sources,omitempty"`
+}
+
+// Duration is a time.Duration written as a string such as "24h" in JSON
+type Duration time.Duration
+
+// UnmarshalJSON parses a Go duration string
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"24h\": %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// MarshalJSON writes the duration as a Go duration string
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// DefaultPath returns the default configuration file location
+func DefaultPath() string {
+	if path := os.Getenv("REGARD_CONFIG"); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "regard", "config.json")
+}
+
+// Load reads the configuration file at path. A missing file yields the
+// default configuration.
+func Load(path string) (*Config, error) {
+	cfg := &Config{}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("reading config: %w", err)
+		}
+		if err == nil {
+			if err := json.Unmarshal(data, cfg); err != nil {
+				return nil, fmt.Errorf("parsing config %s: %w", path, err)
+			}
+		}
+	}
+
+	if cfg.CacheDir == "" {
+		if dir, err := os.UserCacheDir(); err == nil {
+			cfg.CacheDir = filepath.Join(dir, "regard")
+		}
+	}
+	cfg.CacheDir = expandHome(cfg.CacheDir)
+
+	return cfg, nil
+}
+
+// RDAPOptions converts the RDAP settings into query options, loading any
+// override files
+func (c *Config) RDAPOptions() (query.RDAPOptions, error) {
+	opts := query.RDAPOptions{
+		Server:           c.RDAP.Server,
+		BootstrapURL:     c.RDAP.BootstrapURL,
+		CacheDir:         c.CacheDir,
+		RefreshIntervals: map[string]time.Duration{},
+	}
+
+	for registry, interval := range c.RDAP.Refresh {
+		opts.RefreshIntervals[strings.ToLower(registry)] = time.Duration(interval)
+	}
+
+	for registry, path := range c.RDAP.BootstrapFiles {
+		overrides, err := query.LoadRDAPOverrideFile(strings.ToLower(registry), expandHome(path))
+		if err != nil {
+			return opts, err
+		}
+		opts.Overrides = append(opts.Overrides, overrides...)
+	}
+
+	// Inline servers take precedence over override files
+	inline := []query.RDAPOverride{}
+	for entry, server := range c.RDAP.Servers {
+		inline = append(inline, query.RDAPOverride{
+			Registry: query.RegistryForEntry(entry),
+			Entry:    entry,
+			URLs:     []string{server},
+		})
+	}
+	opts.Overrides = append(inline, opts.Overrides...)
+
+	return opts, nil
+}
+
+// WhoisOptions converts the WHOIS settings into query options, checking the
+// configured character sets are supported
+func (c *Config) WhoisOptions() (query.WhoisOptions, error) {
+	opts := query.WhoisOptions{
+		Server:    c.Whois.Server,
+		Servers:   map[string]string{},
+		Templates: map[string]string{},
+		Charsets:  map[string]string{},
+	}
+
+	for zone, server := range c.Whois.Servers {
+		opts.Servers[strings.ToLower(strings.Trim(zone, "."))] = server
+	}
+	for server, template := range c.Whois.Templates {
+		opts.Templates[strings.ToLower(server)] = template
+	}
+	for server, charset := range c.Whois.Charsets {
+		if err := query.ValidateWhoisCharset(charset); err != nil {
+			return opts, fmt.Errorf("whois charset for %s: %w", server, err)
+		}
+		opts.Charsets[strings.ToLower(server)] = charset
+	}
+
+	return opts, nil
+}
+
+// DNSOptions converts the DNS settings into query options
+func (c *Config) DNSOptions() query.DNSOptions {
+	opts := query.DNSOptions{
+		Resolver: c.DNS.Resolver,
+		Timeout:  time.Duration(c.DNS.Timeout),
+	}
+	if c.DNS.NameserverPort != 0 {
+		opts.NameserverPort = strconv.Itoa(c.DNS.NameserverPort)
+	}
+	return opts
+}
+
+// GeoDatabases returns the configured geolocation database paths
+func (c *Config) GeoDatabases() []string {
+	paths := make([]string, 0, len(c.Geo.Databases))
+	for _, path := range c.Geo.Databases {
+		paths = append(paths, expandHome(path))
+	}
+	return paths
+}
+
+// RoutingTable returns the configured routing table dump path
+func (c *Config) RoutingTable() string {
+	return expandHome(c.Routing.Table)
+}
+
+// VRPExport returns the configured VRP export path
+func (c *Config) VRPExport() string {
+	return expandHome(c.RPKI.VRPs)
+}
+
+// IRROptions converts the IRR settings into query options
+func (c *Config) IRROptions() query.IRROptions {
+	return query.IRROptions{Server: c.IRR.Server, Sources: c.IRR.Sources}
+}
+
+// expandHome replaces a leading "~/" with the user's home directory
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
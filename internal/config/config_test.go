@@ -0,0 +1,179 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"regard/internal/query"
+)
+
+func TestLoad_MissingFile(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("Load() unexpected error for missing file: %v", err)
+	}
+	if cfg.CacheDir == "" {
+		t.Error("Expected default cache directory to be set")
+	}
+}
+
+func TestLoad_RDAPOptions(t *testing.T) {
+	dir := t.TempDir()
+
+	overridePath := filepath.Join(dir, "dns.json")
+	if err := os.WriteFile(overridePath, []byte(`{"services": [[["example"], ["http://file.example/"]]]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	configPath := filepath.Join(dir, "config.json")
+	content := `{
+		"cache_dir": "` + filepath.Join(dir, "cache") + `",
+		"rdap": {
+			"refresh": {"DNS": "12h", "asn": "168h"},
+			"bootstrap_files": {"dns": "` + overridePath + `"},
+			"servers": {"test": "http://inline.example/", "10.0.0.0/8": "http://ten.example/"}
+		}
+	}`
+	if err := os.WriteFile(configPath, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+
+	opts, err := cfg.RDAPOptions()
+	if err != nil {
+		t.Fatalf("RDAPOptions() unexpected error: %v", err)
+	}
+
+	if opts.CacheDir != filepath.Join(dir, "cache") {
+		t.Errorf("Expected cache dir from config, got %q", opts.CacheDir)
+	}
+	if opts.RefreshIntervals[query.RegistryDNS] != 12*time.Hour || opts.RefreshIntervals[query.RegistryASN] != 168*time.Hour {
+		t.Errorf("Unexpected refresh intervals: %v", opts.RefreshIntervals)
+	}
+	if len(opts.Overrides) != 3 {
+		t.Fatalf("Expected 3 overrides, got %+v", opts.Overrides)
+	}
+
+	registries := map[string]string{}
+	for _, override := range opts.Overrides {
+		registries[override.Entry] = override.Registry
+	}
+	if registries["test"] != query.RegistryDNS || registries["10.0.0.0/8"] != query.RegistryIPv4 || registries["example"] != query.RegistryDNS {
+		t.Errorf("Unexpected override registries: %v", registries)
+	}
+}
+
+func TestLoad_InvalidConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"rdap": {"refresh": {"dns": "daily"}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(path); err == nil {
+		t.Error("Expected error for invalid refresh duration")
+	}
+}
+
+func TestLoad_WhoisOptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	content := `{
+		"whois": {
+			"server": "whois.example.net",
+			"servers": {".DE": "whois.denic.de", "test": "localhost:4343"},
+			"templates": {"WHOIS.DENIC.DE": "-T dn %s"},
+			"charsets": {"whois.example.jp": "Shift_JIS"}
+		}
+	}`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+
+	opts, err := cfg.WhoisOptions()
+	if err != nil {
+		t.Fatalf("WhoisOptions() unexpected error: %v", err)
+	}
+	if opts.Server != "whois.example.net" {
+		t.Errorf("Expected forced server from config, got %q", opts.Server)
+	}
+	if opts.Servers["de"] != "whois.denic.de" || opts.Servers["test"] != "localhost:4343" {
+		t.Errorf("Unexpected servers: %v", opts.Servers)
+	}
+	if opts.Templates["whois.denic.de"] != "-T dn %s" {
+		t.Errorf("Unexpected templates: %v", opts.Templates)
+	}
+	if opts.Charsets["whois.example.jp"] != "Shift_JIS" {
+		t.Errorf("Unexpected charsets: %v", opts.Charsets)
+	}
+}
+
+func TestLoad_InvalidWhoisCharset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"whois": {"charsets": {"whois.example.jp": "klingon"}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if _, err := cfg.WhoisOptions(); err == nil {
+		t.Error("Expected error for unsupported character set")
+	}
+}
+
+func TestLoad_DNSOptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	content := `{"dns": {"resolver": "127.0.0.1:5353", "nameserver_port": 5353, "timeout": "2s"}}`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+
+	opts := cfg.DNSOptions()
+	if opts.Resolver != "127.0.0.1:5353" || opts.NameserverPort != "5353" || opts.Timeout != 2*time.Second {
+		t.Errorf("Unexpected DNS options: %+v", opts)
+	}
+}
+
+func TestLoad_LocalDatabases(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	content := `{"geo": {"databases": ["~/geo/GeoLite2-City.mmdb", "/var/lib/GeoLite2-ASN.mmdb"]}, "routing": {"table": "~/rib.20251014.0000.bz2"}, "rpki": {"vrps": "/var/lib/rpki-client/json"}, "irr": {"server": "127.0.0.1:4343", "sources": ["RADB", "RIPE"]}}`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+
+	paths := cfg.GeoDatabases()
+	if len(paths) != 2 || strings.HasPrefix(paths[0], "~") || paths[1] != "/var/lib/GeoLite2-ASN.mmdb" {
+		t.Errorf("GeoDatabases() = %v", paths)
+	}
+	if table := cfg.RoutingTable(); strings.HasPrefix(table, "~") || !strings.HasSuffix(table, "rib.20251014.0000.bz2") {
+		t.Errorf("RoutingTable() = %q", table)
+	}
+	if vrps := cfg.VRPExport(); vrps != "/var/lib/rpki-client/json" {
+		t.Errorf("VRPExport() = %q", vrps)
+	}
+	if opts := cfg.IRROptions(); opts.Server != "127.0.0.1:4343" || len(opts.Sources) != 2 {
+		t.Errorf("IRROptions() = %+v", opts)
+	}
+}
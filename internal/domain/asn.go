@@ -0,0 +1,189 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"regard/internal/query"
+	"regard/internal/rpsl"
+)
+
+// asNumberToken matches a plain AS number in an RPSL policy expression
+var asNumberToken = regexp.MustCompile(`(?i)^AS(\d+)$`)
+
+// rpslPolicyAttributes carry the routing policy peers are read from
+var rpslPolicyAttributes = map[string]bool{
+	"import":     true,
+	"export":     true,
+	"mp-import":  true,
+	"mp-export":  true,
+	"default":    true,
+	"mp-default": true,
+}
+
+// parseASNInfo extracts ASN information from an RIR WHOIS response
+func parseASNInfo(result query.QueryResult) *ASNInfo {
+	if result.RawData == "" {
+		return nil
+	}
+
+	asn := &ASNInfo{Number: queriedASNumber(result.Query)}
+	response := rpsl.Parse(result.RawData)
+	autnums := response.Find("aut-num", "asnumber")
+	if len(autnums) == 0 {
+		return asn
+	}
+	autnum := autnums[0]
+
+	// ARIN writes "15169" or a block such as "64512 - 65534"
+	first, last, isRange := strings.Cut(autnum.Key(), "-")
+	if start, ok := parseASNumber(first); ok {
+		asn.StartAutnum, asn.EndAutnum = start, start
+		if end, ok := parseASNumber(last); isRange && ok {
+			asn.EndAutnum = end
+		}
+	}
+
+	org, _ := rpslOrganization(response, autnum)
+	asn.Handle = autnum.Get("ashandle")
+	asn.Name = autnum.Get("as-name", "asname")
+	asn.Description = autnum.Get("descr")
+	asn.Organization = firstNonEmpty(org.Get("org-name", "orgname"), autnum.Get("owner"), asn.Description)
+	asn.Country = strings.ToUpper(firstNonEmpty(autnum.Get("country"), org.Get("country")))
+	asn.Status = autnum.Get("status")
+	asn.AbuseContact = rpslAbuseContact(response, autnum)
+
+	// Peers are the AS numbers named in the routing policy; as-sets are kept
+	// apart, and keywords such as ANY dropped
+	for _, attr := range autnum.Attributes {
+		if !rpslPolicyAttributes[attr.Name] {
+			continue
+		}
+		for _, word := range strings.FieldsFunc(attr.Value, func(r rune) bool {
+			return r == ' ' || r == '\t' || r == ',' || r == ';' || r == '(' || r == ')' || r == '{' || r == '}'
+		}) {
+			if m := asNumberToken.FindStringSubmatch(word); m != nil {
+				if peer := "AS" + m[1]; peer != asn.Number {
+					asn.Peers = appendUnique(asn.Peers, peer)
+				}
+			} else if rpsl.IsASSetName(word) {
+				asn.ASSets = appendUnique(asn.ASSets, strings.ToUpper(word))
+			}
+		}
+	}
+
+	// Set status to active if we have substantial data but no explicit status
+	if asn.Status == "" && (asn.Name != "" || asn.Organization != "") {
+		asn.Status = "active"
+	}
+
+	return asn
+}
+
+// queriedASNumber writes the queried AS as "AS15169"; registrations may cover
+// a whole block, so the number is never taken from the response
+func queriedASNumber(query string) string {
+	if number, ok := parseASNumber(query); ok {
+		return fmt.Sprintf("AS%d", number)
+	}
+	return query
+}
+
+// parseASNumber reads "AS15169" or "15169"
+func parseASNumber(value string) (uint32, bool) {
+	value = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(value)), "AS")
+	number, err := strconv.ParseUint(value, 10, 32)
+	return uint32(number), err == nil
+}
+
+// parseRDAPAutnum maps an RDAP autnum object into ASN information. Contacts,
+// status and events are read into the summary by parseRDAPSummary first.
+func parseRDAPAutnum(result query.QueryResult, summary Summary) *ASNInfo {
+	var autnum map[string]interface{}
+	if jsonBytes, err := json.Marshal(result.Data); err == nil {
+		_ = json.Unmarshal(jsonBytes, &autnum)
+	}
+	if autnum == nil {
+		return nil
+	}
+
+	asn := &ASNInfo{Number: queriedASNumber(result.Query)}
+	asn.Handle, _ = autnum["Handle"].(string)
+	asn.Name, _ = autnum["Name"].(string)
+	asn.Type, _ = autnum["Type"].(string)
+	asn.Country, _ = autnum["Country"].(string)
+
+	if _, ok := autnum["StartAutnum"].(float64); ok {
+		asn.StartAutnum = uint32(jsonInt(autnum["StartAutnum"]))
+		asn.EndAutnum = asn.StartAutnum
+		if _, ok := autnum["EndAutnum"].(float64); ok {
+			asn.EndAutnum = uint32(jsonInt(autnum["EndAutnum"]))
+		}
+	}
+	if len(summary.StatusDetails) > 0 {
+		asn.Status = summary.StatusDetails[0]
+	}
+
+	for _, contact := range summary.Contacts {
+		if asn.Organization == "" && contact.Role == "registrant" {
+			asn.Organization = firstNonEmpty(contact.Organization, contact.Name)
+		}
+		if asn.AbuseContact == "" && contact.Role == "abuse" {
+			asn.AbuseContact = contact.Email
+		}
+	}
+	// Remarks usually carry the RPSL descr lines
+	for _, remark := range summary.Remarks {
+		if asn.Description == "" && len(remark.Description) > 0 {
+			asn.Description = remark.Description[0]
+		}
+	}
+	if asn.Organization == "" {
+		asn.Organization = asn.Description
+	}
+
+	return asn
+}
+
+// Range formats the block of AS numbers the registration covers
+func (a ASNInfo) Range() string {
+	if a.StartAutnum == 0 && a.EndAutnum == 0 {
+		return ""
+	}
+	if a.StartAutnum == a.EndAutnum {
+		return fmt.Sprintf("AS%d", a.StartAutnum)
+	}
+	return fmt.Sprintf("AS%d - AS%d", a.StartAutnum, a.EndAutnum)
+}
+
+// SupplementASN fills gaps in an RDAP ASN summary, such as peers from RPSL
+// import and export lines, with a WHOIS response for the same ASN
+func SupplementASN(summary *Summary, whois query.QueryResult) {
+	if summary.ASN == nil || !whois.Success {
+		return
+	}
+	extra := parseASNInfo(whois)
+	if extra == nil {
+		return
+	}
+
+	fill := func(field *string, value string) {
+		if *field == "" {
+			*field = value
+		}
+	}
+	fill(&summary.ASN.Name, extra.Name)
+	fill(&summary.ASN.Description, extra.Description)
+	fill(&summary.ASN.Country, extra.Country)
+	fill(&summary.ASN.Organization, extra.Organization)
+	fill(&summary.ASN.AbuseContact, extra.AbuseContact)
+	if len(summary.ASN.Peers) == 0 {
+		summary.ASN.Peers = extra.Peers
+	}
+	if len(summary.ASN.ASSets) == 0 {
+		summary.ASN.ASSets = extra.ASSets
+	}
+}
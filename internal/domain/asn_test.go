@@ -0,0 +1,227 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"regard/internal/query"
+)
+
+func TestCreateSummary_RDAPAutnum(t *testing.T) {
+	autnum := map[string]interface{}{
+		"Handle":      "AS15169",
+		"StartAutnum": float64(15169),
+		"EndAutnum":   float64(15169),
+		"Name":        "GOOGLE",
+		"Type":        "DIRECT ALLOCATION",
+		"Status":      []interface{}{"active"},
+		"Events": []interface{}{
+			map[string]interface{}{"Action": "registration", "Date": "2000-03-30T00:00:00-05:00"},
+			map[string]interface{}{"Action": "last changed", "Date": "2012-02-24T09:44:34-05:00"},
+		},
+		"Entities": []interface{}{
+			map[string]interface{}{
+				"Handle": "GOGL",
+				"Roles":  []interface{}{"registrant"},
+				"VCard": map[string]interface{}{
+					"Properties": []interface{}{
+						map[string]interface{}{"Name": "fn", "Value": "Google LLC"},
+						map[string]interface{}{"Name": "adr", "Value": []interface{}{"", "", "", "Mountain View", "CA", "94043", "US"}},
+					},
+				},
+				"Entities": []interface{}{
+					map[string]interface{}{
+						"Roles": []interface{}{"abuse"},
+						"VCard": map[string]interface{}{
+							"Properties": []interface{}{
+								map[string]interface{}{"Name": "email", "Value": "network-abuse@google.com"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	summary := CreateSummary(query.QueryResult{
+		Query:     "AS15169",
+		Type:      string(query.QueryTypeASN),
+		Protocol:  "RDAP",
+		Timestamp: time.Now(),
+		Success:   true,
+		Data:      autnum,
+	})
+
+	expected := &ASNInfo{
+		Number:       "AS15169",
+		Handle:       "AS15169",
+		StartAutnum:  15169,
+		EndAutnum:    15169,
+		Name:         "GOOGLE",
+		Type:         "DIRECT ALLOCATION",
+		Organization: "Google LLC",
+		Status:       "active",
+		AbuseContact: "network-abuse@google.com",
+	}
+	if !reflect.DeepEqual(summary.ASN, expected) {
+		t.Errorf("ASN = %+v, want %+v", summary.ASN, expected)
+	}
+	if summary.Timeline.Registration == nil || summary.Timeline.LastUpdated == nil {
+		t.Errorf("Timeline missing registration or last changed: %+v", summary.Timeline)
+	}
+}
+
+func TestCreateSummary_RDAPAutnumBlock(t *testing.T) {
+	// AS19907 is registered as part of the AS19905 - AS19911 block
+	summary := CreateSummary(query.QueryResult{
+		Query:     "AS19907",
+		Type:      string(query.QueryTypeASN),
+		Protocol:  "RDAP",
+		Timestamp: time.Now(),
+		Success:   true,
+		Data: map[string]interface{}{
+			"Handle":      "AS19905",
+			"StartAutnum": float64(19905),
+			"EndAutnum":   float64(19911),
+			"Name":        "NEUSTAR-AS6",
+		},
+	})
+
+	if summary.ASN == nil {
+		t.Fatal("ASN missing")
+	}
+	if summary.ASN.Number != "AS19907" {
+		t.Errorf("Number = %q, want the queried AS19907", summary.ASN.Number)
+	}
+	if got := summary.ASN.Range(); got != "AS19905 - AS19911" {
+		t.Errorf("Range() = %q, want %q", got, "AS19905 - AS19911")
+	}
+}
+
+func TestASNInfo_Range(t *testing.T) {
+	tests := []struct {
+		asn      ASNInfo
+		expected string
+	}{
+		{ASNInfo{}, ""},
+		{ASNInfo{StartAutnum: 15169, EndAutnum: 15169}, "AS15169"},
+		{ASNInfo{StartAutnum: 64512, EndAutnum: 65534}, "AS64512 - AS65534"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if got := tt.asn.Range(); got != tt.expected {
+				t.Errorf("Range() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSupplementASN(t *testing.T) {
+	summary := Summary{ASN: &ASNInfo{Number: "AS64500", Name: "EXAMPLE-AS"}}
+	whois := query.QueryResult{
+		Query:    "AS64500",
+		Protocol: "WHOIS",
+		Success:  true,
+		RawData: `% Abuse contact for 'AS64500' is 'abuse@example.net'
+
+aut-num:        AS64500
+as-name:        OTHER-NAME
+descr:          Example Networks
+import:         from AS64501 accept ANY
+export:         to AS64501 announce AS64500 AS-EXAMPLE
+mp-export:      afi ipv6.unicast to AS64501 announce AS64500:AS-CUSTOMERS
+country:        NL
+`,
+	}
+
+	SupplementASN(&summary, whois)
+
+	if summary.ASN.Name != "EXAMPLE-AS" {
+		t.Errorf("Name = %q, RDAP value should win", summary.ASN.Name)
+	}
+	if summary.ASN.AbuseContact != "abuse@example.net" || summary.ASN.Country != "NL" || summary.ASN.Organization != "Example Networks" {
+		t.Errorf("WHOIS values not supplemented: %+v", summary.ASN)
+	}
+	if !reflect.DeepEqual(summary.ASN.Peers, []string{"AS64501"}) {
+		t.Errorf("Peers = %v, want [AS64501]", summary.ASN.Peers)
+	}
+	if !reflect.DeepEqual(summary.ASN.ASSets, []string{"AS-EXAMPLE", "AS64500:AS-CUSTOMERS"}) {
+		t.Errorf("ASSets = %v, want [AS-EXAMPLE AS64500:AS-CUSTOMERS]", summary.ASN.ASSets)
+	}
+}
+
+func TestParseASNInfo(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		raw      string
+		expected *ASNInfo
+	}{
+		{
+			name:  "RIPE objects resolved by reference",
+			query: "AS3333",
+			raw: `% Abuse contact for 'AS3333' is 'abuse@ripe.net'
+
+aut-num:        AS3333
+as-name:        RIPE-NCC-AS
+descr:          Reseaux IP Europeens Network Coordination Centre
+org:            ORG-RIEN1-RIPE
+import:         from AS2121 accept AS2121
+mp-export:      afi ipv6 to AS2121 announce AS3333
+status:         ASSIGNED
+abuse-c:        OPS4-RIPE
+
+organisation:   ORG-RIEN1-RIPE
+org-name:       RIPE Network Coordination Centre
+country:        NL
+
+role:           RIPE NCC Operations
+descr:          Role object, not the AS description
+status:         OTHER
+nic-hdl:        OPS4-RIPE
+abuse-mailbox:  abuse@ripe.net
+
+mntner:         RIPE-NCC-MNT
+descr:          Maintainer
+`,
+			expected: &ASNInfo{
+				Number: "AS3333", StartAutnum: 3333, EndAutnum: 3333, Name: "RIPE-NCC-AS",
+				Description: "Reseaux IP Europeens Network Coordination Centre", Country: "NL",
+				Organization: "RIPE Network Coordination Centre", Status: "ASSIGNED",
+				AbuseContact: "abuse@ripe.net", Peers: []string{"AS2121"},
+			},
+		},
+		{
+			name:  "ARIN block",
+			query: "65000",
+			raw: `ASNumber:       64512 - 65534
+ASName:         IANA-RSVD
+ASHandle:       AS64512
+Organization:   Internet Assigned Numbers Authority (IANA)
+
+OrgName:        Internet Assigned Numbers Authority
+OrgId:          IANA
+Country:        US
+
+OrgAbuseHandle: IANA-IP-ARIN
+OrgAbuseEmail:  abuse@iana.org
+`,
+			expected: &ASNInfo{
+				Number: "AS65000", Handle: "AS64512", StartAutnum: 64512, EndAutnum: 65534, Name: "IANA-RSVD",
+				Country: "US", Organization: "Internet Assigned Numbers Authority", Status: "active",
+				AbuseContact: "abuse@iana.org",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			asn := parseASNInfo(query.QueryResult{Query: tt.query, Protocol: "WHOIS", RawData: tt.raw})
+			if !reflect.DeepEqual(asn, tt.expected) {
+				t.Errorf("parseASNInfo() = %+v, want %+v", asn, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// redactionMarkers are lowercase substrings that registries use in place of
+// contact data withheld for privacy reasons
+var redactionMarkers = []string{
+	"redacted",
+	"not disclosed",
+	"withheld",
+	"data protected",
+	"statutory masking",
+	"non-public data",
+	"gdpr masked",
+	"hidden upon user request",
+	"query the rdds service",
+	"query the rdap service",
+}
+
+// contactRoleOrder controls the order contacts are listed in
+var contactRoleOrder = map[string]int{
+	"registrant":     0,
+	"administrative": 1,
+	"technical":      2,
+	"billing":        3,
+	"abuse":          4,
+}
+
+// whoisContactPrefixes maps WHOIS field prefixes to contact roles
+var whoisContactPrefixes = []struct {
+	prefix string
+	role   string
+}{
+	{"registrant", "registrant"},
+	{"admin", "administrative"},
+	{"tech", "technical"},
+	{"billing", "billing"},
+	{"registrar_abuse_contact", "abuse"},
+}
+
+// IsRedacted reports whether a contact value is a privacy redaction placeholder
+func IsRedacted(value string) bool {
+	lower := strings.ToLower(value)
+	for _, marker := range redactionMarkers {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// setContactField stores a contact value, recording it as redacted instead when
+// the registry has masked it
+func setContactField(contact *Contact, field, value string) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return
+	}
+
+	if IsRedacted(value) {
+		for _, existing := range contact.Redacted {
+			if existing == field {
+				return
+			}
+		}
+		contact.Redacted = append(contact.Redacted, field)
+		return
+	}
+
+	switch field {
+	case "name":
+		contact.Name = value
+	case "organization":
+		contact.Organization = value
+	case "email":
+		contact.Email = value
+	case "phone":
+		contact.Phone = value
+	case "country":
+		contact.Country = value
+	case "address":
+		contact.Address = append(contact.Address, value)
+	}
+}
+
+// hasContactData reports whether a contact carries any information worth showing
+func hasContactData(contact Contact) bool {
+	return contact.Name != "" || contact.Organization != "" || contact.Email != "" ||
+		contact.Phone != "" || contact.Country != "" || len(contact.Address) > 0 ||
+		len(contact.Redacted) > 0
+}
+
+// sortContacts orders contacts by role, keeping the original order within a role
+func sortContacts(contacts []Contact) {
+	rank := func(role string) int {
+		if r, ok := contactRoleOrder[role]; ok {
+			return r
+		}
+		return len(contactRoleOrder)
+	}
+	sort.SliceStable(contacts, func(i, j int) bool {
+		return rank(contacts[i].Role) < rank(contacts[j].Role)
+	})
+}
+
+// parseRDAPContacts walks the (possibly nested) RDAP entities and builds a
+// contact for every role each entity holds
+func parseRDAPContacts(entities []interface{}) []Contact {
+	var contacts []Contact
+
+	for _, entity := range entities {
+		entityObj, ok := entity.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		base := parseVCardContact(entityObj)
+		if handle, ok := entityObj["Handle"].(string); ok {
+			base.Handle = handle
+		}
+
+		if rolesArray, ok := entityObj["Roles"].([]interface{}); ok {
+			for _, role := range rolesArray {
+				roleStr, ok := role.(string)
+				// The registrar entity is already summarized in the Registrar section
+				if !ok || roleStr == "registrar" {
+					continue
+				}
+				contact := base
+				contact.Role = roleStr
+				contact.Address = slices.Clone(base.Address)
+				contact.Redacted = slices.Clone(base.Redacted)
+				if hasContactData(contact) {
+					contacts = append(contacts, contact)
+				}
+			}
+		}
+
+		// Nested entities, e.g. the abuse contact of a registrar
+		if nested, ok := entityObj["Entities"].([]interface{}); ok {
+			contacts = append(contacts, parseRDAPContacts(nested)...)
+		}
+	}
+
+	return contacts
+}
+
+// parseVCardContact extracts contact details from an entity's jCard properties
+func parseVCardContact(entityObj map[string]interface{}) Contact {
+	contact := Contact{}
+
+	vcard, ok := entityObj["VCard"].(map[string]interface{})
+	if !ok {
+		return contact
+	}
+	props, ok := vcard["Properties"].([]interface{})
+	if !ok {
+		return contact
+	}
+
+	for _, prop := range props {
+		propObj, ok := prop.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := propObj["Name"].(string)
+		values := flattenVCardValue(propObj["Value"])
+
+		switch name {
+		case "fn":
+			setContactField(&contact, "name", strings.Join(values, " "))
+		case "org":
+			setContactField(&contact, "organization", strings.Join(values, ", "))
+		case "email":
+			if len(values) > 0 {
+				setContactField(&contact, "email", strings.TrimPrefix(values[0], "mailto:"))
+			}
+		case "tel":
+			if contact.Phone == "" && len(values) > 0 {
+				setContactField(&contact, "phone", strings.TrimPrefix(values[0], "tel:"))
+			}
+		case "adr":
+			parseVCardAddress(&contact, propObj)
+		}
+	}
+
+	return contact
+}
+
+// parseVCardAddress handles the structured adr property, whose value holds
+// [pobox, extended, street, locality, region, postal code, country], and the
+// label parameter used by registries that send a formatted address instead
+func parseVCardAddress(contact *Contact, propObj map[string]interface{}) {
+	hasLabel := false
+	if params, ok := propObj["Parameters"].(map[string]interface{}); ok {
+		if cc, ok := params["cc"].([]interface{}); ok && len(cc) > 0 {
+			if code, ok := cc[0].(string); ok {
+				setContactField(contact, "country", code)
+			}
+		}
+		if label, ok := params["label"].([]interface{}); ok && len(label) > 0 {
+			if text, ok := label[0].(string); ok {
+				hasLabel = true
+				for _, line := range strings.Split(text, "\n") {
+					setContactField(contact, "address", line)
+				}
+			}
+		}
+	}
+
+	components, ok := propObj["Value"].([]interface{})
+	if !ok {
+		return
+	}
+
+	for i, component := range components {
+		values := flattenVCardValue(component)
+		switch {
+		case i == 6:
+			if contact.Country == "" {
+				setContactField(contact, "country", strings.Join(values, " "))
+			}
+		case !hasLabel:
+			for _, value := range values {
+				setContactField(contact, "address", value)
+			}
+		}
+	}
+}
+
+// flattenVCardValue converts a jCard value, which may be a nested array, into
+// a list of non-empty strings
+func flattenVCardValue(value interface{}) []string {
+	var values []string
+
+	switch v := value.(type) {
+	case string:
+		if strings.TrimSpace(v) != "" {
+			values = append(values, v)
+		}
+	case float64:
+		values = append(values, fmt.Sprintf("%g", v))
+	case []interface{}:
+		for _, item := range v {
+			values = append(values, flattenVCardValue(item)...)
+		}
+	}
+
+	return values
+}
+
+// parseWhoisContacts builds contacts from Registrant/Admin/Tech/Billing fields
+// and the registrar abuse contact fields of a WHOIS response
+func parseWhoisContacts(fields map[string]interface{}) []Contact {
+	var contacts []Contact
+
+	for _, p := range whoisContactPrefixes {
+		contact := Contact{Role: p.role}
+
+		get := func(suffix string) string {
+			if value, ok := fields[p.prefix+"_"+suffix].(string); ok {
+				return value
+			}
+			return ""
+		}
+
+		if id := get("id"); id != "" && !IsRedacted(id) {
+			contact.Handle = id
+		}
+		setContactField(&contact, "name", get("name"))
+		setContactField(&contact, "organization", get("organization"))
+		setContactField(&contact, "email", get("email"))
+		setContactField(&contact, "phone", get("phone"))
+		for _, suffix := range []string{"street", "city", "state/province", "postal_code"} {
+			setContactField(&contact, "address", get(suffix))
+		}
+		setContactField(&contact, "country", get("country"))
+
+		if hasContactData(contact) {
+			contacts = append(contacts, contact)
+		}
+	}
+
+	return contacts
+}
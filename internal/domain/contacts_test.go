@@ -0,0 +1,157 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+
+	"regard/internal/query"
+)
+
+func TestIsRedacted(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected bool
+	}{
+		{"REDACTED FOR PRIVACY", true},
+		{"Redacted for privacy", true},
+		{"Not Disclosed", true},
+		{"Data Protected", true},
+		{"Statutory Masking Enabled", true},
+		{"Please query the RDDS service of the Registrar of Record identified in this output for information on how to contact the Registrant", true},
+		{"Example Org", false},
+		{"Contact Privacy Inc. Customer 123", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if result := IsRedacted(tt.value); result != tt.expected {
+				t.Errorf("IsRedacted(%q) = %v, want %v", tt.value, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseRDAPContacts(t *testing.T) {
+	entities := []interface{}{
+		map[string]interface{}{
+			"Handle": "REG-1",
+			"Roles":  []interface{}{"registrant"},
+			"VCard": map[string]interface{}{
+				"Properties": []interface{}{
+					map[string]interface{}{"Name": "version", "Value": "4.0"},
+					map[string]interface{}{"Name": "fn", "Value": "REDACTED FOR PRIVACY"},
+					map[string]interface{}{"Name": "org", "Value": "Example Org"},
+					map[string]interface{}{"Name": "email", "Value": "Please query the RDDS service of the Registrar of Record"},
+					map[string]interface{}{
+						"Name":  "adr",
+						"Value": []interface{}{"", "", "", "", "London", "", "GB"},
+					},
+				},
+			},
+		},
+		map[string]interface{}{
+			"Handle": "376",
+			"Roles":  []interface{}{"registrar"},
+			"VCard": map[string]interface{}{
+				"Properties": []interface{}{
+					map[string]interface{}{"Name": "fn", "Value": "Example Registrar"},
+				},
+			},
+			"Entities": []interface{}{
+				map[string]interface{}{
+					"Roles": []interface{}{"abuse"},
+					"VCard": map[string]interface{}{
+						"Properties": []interface{}{
+							map[string]interface{}{"Name": "fn", "Value": "Abuse Desk"},
+							map[string]interface{}{"Name": "tel", "Value": "tel:+1.5555551234"},
+							map[string]interface{}{"Name": "email", "Value": "abuse@registrar.example"},
+						},
+					},
+				},
+			},
+		},
+		map[string]interface{}{
+			"Roles": []interface{}{"administrative", "technical"},
+			"VCard": map[string]interface{}{
+				"Properties": []interface{}{
+					map[string]interface{}{"Name": "fn", "Value": "Jane Admin"},
+					map[string]interface{}{
+						"Name":       "adr",
+						"Parameters": map[string]interface{}{"label": []interface{}{"1 High Street\nLondon"}, "cc": []interface{}{"GB"}},
+						"Value":      []interface{}{"", "", "", "", "", "", ""},
+					},
+				},
+			},
+		},
+	}
+
+	contacts := parseRDAPContacts(entities)
+	sortContacts(contacts)
+
+	expected := []Contact{
+		{Role: "registrant", Handle: "REG-1", Organization: "Example Org", Country: "GB", Address: []string{"London"}, Redacted: []string{"name", "email"}},
+		{Role: "administrative", Name: "Jane Admin", Country: "GB", Address: []string{"1 High Street", "London"}},
+		{Role: "technical", Name: "Jane Admin", Country: "GB", Address: []string{"1 High Street", "London"}},
+		{Role: "abuse", Name: "Abuse Desk", Email: "abuse@registrar.example", Phone: "+1.5555551234"},
+	}
+
+	if !reflect.DeepEqual(contacts, expected) {
+		t.Errorf("parseRDAPContacts() =\n%+v\nwant\n%+v", contacts, expected)
+	}
+}
+
+func TestParseWhoisContacts(t *testing.T) {
+	fields := map[string]interface{}{
+		"registrant_name":               "REDACTED FOR PRIVACY",
+		"registrant_organization":       "Example Org",
+		"registrant_state/province":     "England",
+		"registrant_country":            "GB",
+		"registrant_email":              "Please query the RDDS service of the Registrar of Record",
+		"admin_name":                    "Jane Admin",
+		"admin_email":                   "jane@example.com",
+		"admin_phone":                   "+44.2000000000",
+		"registrar_abuse_contact_email": "abuse@registrar.example",
+		"registrar_abuse_contact_phone": "+1.5555551234",
+	}
+
+	contacts := parseWhoisContacts(fields)
+
+	expected := []Contact{
+		{Role: "registrant", Organization: "Example Org", Country: "GB", Address: []string{"England"}, Redacted: []string{"name", "email"}},
+		{Role: "administrative", Name: "Jane Admin", Email: "jane@example.com", Phone: "+44.2000000000"},
+		{Role: "abuse", Email: "abuse@registrar.example", Phone: "+1.5555551234"},
+	}
+
+	if !reflect.DeepEqual(contacts, expected) {
+		t.Errorf("parseWhoisContacts() =\n%+v\nwant\n%+v", contacts, expected)
+	}
+}
+
+func TestCreateSummary_WhoisContacts(t *testing.T) {
+	mockResult := query.QueryResult{
+		Query:    "example.com",
+		Type:     string(query.QueryTypeDomain),
+		Protocol: "WHOIS",
+		Success:  true,
+		Data: map[string]interface{}{
+			"parsed_fields": map[string]interface{}{
+				"domain_name":     "EXAMPLE.COM",
+				"registrant_name": "Redacted for privacy",
+				"tech_email":      "tech@example.com",
+			},
+		},
+	}
+
+	summary := CreateSummary(mockResult)
+
+	if len(summary.Contacts) != 2 {
+		t.Fatalf("Expected 2 contacts, got %d: %+v", len(summary.Contacts), summary.Contacts)
+	}
+	if summary.Contacts[0].Role != "registrant" || len(summary.Contacts[0].Redacted) != 1 {
+		t.Errorf("Expected redacted registrant contact, got %+v", summary.Contacts[0])
+	}
+	if summary.Contacts[1].Role != "technical" || summary.Contacts[1].Email != "tech@example.com" {
+		t.Errorf("Expected technical contact with email, got %+v", summary.Contacts[1])
+	}
+}
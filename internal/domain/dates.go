@@ -0,0 +1,225 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateHint describes how a registry writes dates that don't say for themselves
+type dateHint struct {
+	DayFirst bool               // Read ambiguous numeric dates such as 03/04/2026 as day/month
+	Location *time.Location     // Zone for dates without one (default UTC)
+	Zones    map[string]float64 // Zone abbreviations the registry uses with another meaning than zoneOffsets
+}
+
+// chinaStandardTime is what CNNIC and TWNIC mean by "CST", rather than US Central
+var chinaStandardTime = map[string]float64{"CST": 8}
+
+// whoisDateHints holds the date conventions of registries, keyed by TLD
+var whoisDateHints = map[string]dateHint{
+	"jp": {Location: time.FixedZone("JST", 9*60*60)},
+	"kr": {Location: time.FixedZone("KST", 9*60*60)},
+	"cn": {Location: time.FixedZone("CST", 8*60*60), Zones: chinaStandardTime},
+	"tw": {Location: time.FixedZone("CST", 8*60*60), Zones: chinaStandardTime},
+	"br": {DayFirst: true, Location: time.FixedZone("BRT", -3*60*60)},
+	"ru": {DayFirst: true, Location: time.FixedZone("MSK", 3*60*60)},
+	"su": {DayFirst: true, Location: time.FixedZone("MSK", 3*60*60)},
+	"de": {DayFirst: true},
+	"at": {DayFirst: true},
+	"ch": {DayFirst: true},
+	"fr": {DayFirst: true},
+	"it": {DayFirst: true},
+	"es": {DayFirst: true},
+	"nl": {DayFirst: true},
+	"be": {DayFirst: true},
+	"eu": {DayFirst: true},
+	"pl": {DayFirst: true},
+	"cz": {DayFirst: true},
+	"uk": {DayFirst: true},
+	"au": {DayFirst: true},
+	"nz": {DayFirst: true},
+	"in": {DayFirst: true},
+}
+
+// dateHintForDomain returns the date conventions of the domain's registry
+func dateHintForDomain(domain string) dateHint {
+	labels := strings.Split(strings.ToLower(strings.TrimSuffix(domain, ".")), ".")
+	return whoisDateHints[labels[len(labels)-1]]
+}
+
+// zoneOffsets maps timezone abbreviations seen in WHOIS output to UTC offsets
+// in hours. Ambiguous abbreviations use their most common WHOIS meaning unless
+// the registry's dateHint overrides it.
+var zoneOffsets = map[string]float64{
+	"UTC": 0, "UT": 0, "GMT": 0, "Z": 0, "WET": 0,
+	"BST": 1, "CET": 1, "WEST": 1, "MET": 1,
+	"CEST": 2, "EET": 2, "MEST": 2, "SAST": 2,
+	"EEST": 3, "MSK": 3,
+	"IST": 5.5,
+	"ICT": 7, "WIB": 7,
+	"CST": -6, "HKT": 8, "SGT": 8, "AWST": 8, "PHT": 8,
+	"JST": 9, "KST": 9,
+	"ACST": 9.5,
+	"AEST": 10, "AEDT": 11,
+	"NZST": 12, "NZDT": 13,
+	"BRT": -3, "ART": -3,
+	"AST": -4, "EDT": -4,
+	"EST": -5, "CDT": -5,
+	"MDT": -6,
+	"MST": -7, "PDT": -7,
+	"PST": -8,
+}
+
+var (
+	// koreanDate matches the "2026. 08. 13." style used by KISA
+	koreanDate = regexp.MustCompile(`^(\d{4})\.\s*(\d{1,2})\.\s*(\d{1,2})\.?`)
+	// numericDate matches ambiguous dd/mm/yyyy or mm/dd/yyyy dates
+	numericDate = regexp.MustCompile(`^(\d{1,2})[/-](\d{1,2})[/-](\d{4})(.*)$`)
+	// numericOffset matches a trailing "+0100", "+01:00" or "-03"
+	numericOffset = regexp.MustCompile(`^(?:UTC|GMT)?([+-])(\d{2}):?(\d{2})?$`)
+	// unixDate matches date(1) output, with the zone before the year:
+	// "Thu Aug 13 04:00:00 GMT 2026"
+	unixDate = regexp.MustCompile(`^([A-Za-z]{3} [A-Za-z]{3} \d{1,2} \d{1,2}:\d{2}:\d{2}) (\S+) (\d{4})$`)
+)
+
+// zonedLayouts carry their own zone or offset
+var zonedLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05Z07",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05Z0700",
+	"2006-01-02 15:04:05Z07",
+	"2006-01-02T15:04Z07:00",
+	time.RFC1123Z,
+}
+
+// localLayouts are read in the registry's zone, UTC unless hinted otherwise
+var localLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02",
+	"2006.01.02 15:04:05",
+	"2006.01.02",
+	"02.01.2006 15:04:05",
+	"02.01.2006",
+	"20060102",
+	"02-Jan-2006 15:04:05",
+	"02-Jan-2006",
+	"2-Jan-2006",
+	"02 Jan 2006 15:04:05",
+	"02 Jan 2006",
+	"2 January 2006",
+	"January 2 2006",
+	"Jan 2 2006",
+	"Mon Jan 2 15:04:05 2006",
+	"Mon, 02 Jan 2006 15:04:05",
+	"Mon, 2 Jan 2006 15:04:05",
+	"Jan-2006",
+	"January 2006",
+}
+
+// parseWhoisDate parses a date as written in WHOIS or RDAP data
+func parseWhoisDate(dateStr string) (time.Time, error) {
+	date, _, err := parseWhoisDateHint(dateStr, dateHint{})
+	return date, err
+}
+
+// parseWhoisDateHint parses a date using the registry's conventions for
+// ambiguous forms. The boolean reports a date that is only an upper bound, as
+// in Nominet's "before Aug-1996".
+func parseWhoisDateHint(dateStr string, hint dateHint) (time.Time, bool, error) {
+	loc := hint.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	value := strings.TrimSpace(dateStr)
+	approximate := false
+	if lower := strings.ToLower(value); strings.HasPrefix(lower, "before ") {
+		approximate = true
+		value = strings.TrimSpace(value[len("before "):])
+	}
+	value = strings.TrimSuffix(strings.Join(strings.Fields(value), " "), ".")
+	if value == "" {
+		return time.Time{}, false, fmt.Errorf("unable to parse date: %q", dateStr)
+	}
+
+	// "2026. 08. 13." -> "2026-08-13"
+	if m := koreanDate.FindStringSubmatch(value); m != nil {
+		value = fmt.Sprintf("%s-%s-%s%s", m[1], padTwo(m[2]), padTwo(m[3]), value[len(m[0]):])
+	}
+
+	// Resolve ambiguous numeric dates with the hint, unless a field is over 12
+	if m := numericDate.FindStringSubmatch(value); m != nil {
+		first, _ := strconv.Atoi(m[1])
+		second, _ := strconv.Atoi(m[2])
+		day, month := second, first
+		if (hint.DayFirst && second <= 12) || first > 12 {
+			day, month = first, second
+		}
+		value = fmt.Sprintf("%s-%02d-%02d%s", m[3], month, day, m[4])
+	}
+
+	// Move a date(1) zone to the end, where splitZone looks for it
+	if m := unixDate.FindStringSubmatch(value); m != nil {
+		value = m[1] + " " + m[3] + " " + m[2]
+	}
+
+	value, loc = splitZone(value, loc, hint.Zones)
+
+	for _, layout := range zonedLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, approximate, nil
+		}
+	}
+	for _, layout := range localLayouts {
+		if date, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return date, approximate, nil
+		}
+	}
+
+	return time.Time{}, false, fmt.Errorf("unable to parse date: %q", dateStr)
+}
+
+// splitZone removes a trailing zone name or offset such as "UTC", "(JST)" or
+// "+0200", returning the location it names. Names in zones take precedence.
+func splitZone(value string, loc *time.Location, zones map[string]float64) (string, *time.Location) {
+	i := strings.LastIndex(value, " ")
+	if i < 0 {
+		return value, loc
+	}
+	zone := strings.Trim(value[i+1:], "()")
+
+	offset, ok := zones[strings.ToUpper(zone)]
+	if !ok {
+		offset, ok = zoneOffsets[strings.ToUpper(zone)]
+	}
+	if ok {
+		return value[:i], time.FixedZone(strings.ToUpper(zone), int(offset*60*60))
+	}
+	if m := numericOffset.FindStringSubmatch(strings.ToUpper(zone)); m != nil {
+		hours, _ := strconv.Atoi(m[2])
+		minutes, _ := strconv.Atoi(m[3])
+		seconds := hours*60*60 + minutes*60
+		if m[1] == "-" {
+			seconds = -seconds
+		}
+		return value[:i], time.FixedZone("", seconds)
+	}
+	return value, loc
+}
+
+func padTwo(s string) string {
+	if len(s) == 1 {
+		return "0" + s
+	}
+	return s
+}
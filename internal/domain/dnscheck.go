@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"regard/internal/query"
+)
+
+// DNSResolver answers the live DNS lookups a delegation check needs
+type DNSResolver interface {
+	Server() string
+	LookupNS(name string) ([]string, error)
+	LookupAddrs(host string) ([]string, error)
+	LookupDNSKEY(name string) ([]query.DNSKEYRecord, error)
+	CheckAuthoritative(addr, zone string) error
+}
+
+// CheckDNS compares the registry's nameservers and DS records with what the
+// live DNS serves, reporting each mismatch
+func CheckDNS(summary Summary, resolver DNSResolver) *DNSCheck {
+	zone := strings.ToLower(strings.TrimSuffix(summary.Domain, "."))
+	check := &DNSCheck{Resolver: resolver.Server()}
+
+	registryNS := make(map[string]bool)
+	for _, ns := range summary.Nameservers {
+		registryNS[normalizeHost(ns.Name)] = true
+	}
+
+	liveNS := make(map[string]bool)
+	names, err := resolver.LookupNS(zone)
+	if err != nil {
+		check.mismatch("Could not resolve NS records: %v", err)
+	}
+	for _, ns := range names {
+		liveNS[normalizeHost(ns)] = true
+	}
+
+	// Check every nameserver either side knows about
+	all := make(map[string]bool)
+	for ns := range registryNS {
+		all[ns] = true
+	}
+	for ns := range liveNS {
+		all[ns] = true
+	}
+	hosts := make([]string, 0, len(all))
+	for ns := range all {
+		hosts = append(hosts, ns)
+	}
+	sort.Strings(hosts)
+
+	for _, host := range hosts {
+		ns := LiveNameserver{Name: host, InRegistry: registryNS[host], InDNS: liveNS[host]}
+		switch {
+		case !ns.InDNS && len(liveNS) > 0:
+			check.mismatch("%s is delegated by the registry but missing from the zone's NS records", host)
+		case !ns.InRegistry && len(registryNS) > 0:
+			check.mismatch("%s is in the zone's NS records but not delegated by the registry", host)
+		}
+
+		addrs, err := resolver.LookupAddrs(host)
+		ns.Addresses = addrs
+		if err != nil || len(addrs) == 0 {
+			ns.Lame = true
+			check.mismatch("%s does not resolve to an address", host)
+		}
+		for _, addr := range addrs {
+			err := resolver.CheckAuthoritative(addr, zone)
+			// An address family this host can't use, such as IPv6 glue
+			// without local IPv6, is no evidence against the nameserver
+			if errors.Is(err, query.ErrUnreachable) {
+				ns.Unreachable = append(ns.Unreachable, addr)
+				continue
+			}
+			if err != nil {
+				ns.Lame = true
+				check.mismatch("%s (%s) is not authoritative for %s: %v (lame delegation)", host, addr, zone, err)
+			}
+		}
+		check.Nameservers = append(check.Nameservers, ns)
+	}
+
+	keys, err := resolver.LookupDNSKEY(zone)
+	if err != nil {
+		check.mismatch("Could not resolve DNSKEY records: %v", err)
+	}
+	for _, key := range keys {
+		dnsKey := newDNSKey(int(key.Flags), int(key.Protocol), int(key.Algorithm), base64.StdEncoding.EncodeToString(key.PublicKey))
+		dnsKey.KeyTag = int(key.KeyTag())
+		check.DNSKeys = append(check.DNSKeys, dnsKey)
+	}
+	checkDSRecords(check, zone, summary.DNSSEC, keys)
+
+	return check
+}
+
+// checkDSRecords verifies each registry DS record against the live DNSKEY set
+func checkDSRecords(check *DNSCheck, zone string, dnssec DNSSECInfo, keys []query.DNSKEYRecord) {
+	if len(dnssec.DSRecords) == 0 {
+		if dnssec.Enabled && len(keys) == 0 {
+			check.mismatch("Registry reports a signed delegation but the zone publishes no DNSKEY records")
+		}
+		if !dnssec.Enabled && hasSecureEntryPoint(keys) {
+			check.mismatch("Zone publishes DNSKEY records but the registry has no DS records (insecure delegation)")
+		}
+		return
+	}
+	if len(keys) == 0 {
+		check.mismatch("Registry has DS records but the zone publishes no DNSKEY records, so validation will fail")
+		return
+	}
+
+	matched := 0
+	for _, ds := range dnssec.DSRecords {
+		ok, err := dsMatchesKey(zone, ds, keys)
+		switch {
+		case err != nil:
+			check.mismatch("DS %d: %v", ds.KeyTag, err)
+		case ok:
+			matched++
+		default:
+			check.mismatch("DS %d (algorithm %d, digest type %d) matches no live DNSKEY", ds.KeyTag, ds.Algorithm, ds.DigestType)
+		}
+	}
+	check.DSMatched = matched
+	if matched == 0 {
+		check.mismatch("No registry DS record matches a live DNSKEY, so validation will fail")
+	}
+}
+
+// dsMatchesKey reports whether a DS record is the digest of one of the keys
+func dsMatchesKey(zone string, ds DSRecord, keys []query.DNSKEYRecord) (bool, error) {
+	for _, key := range keys {
+		if int(key.KeyTag()) != ds.KeyTag || int(key.Algorithm) != ds.Algorithm {
+			continue
+		}
+		digest, err := key.DSDigest(zone, uint8(ds.DigestType))
+		if err != nil {
+			return false, err
+		}
+		if strings.EqualFold(hex.EncodeToString(digest), ds.Digest) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// hasSecureEntryPoint reports whether any key has the SEP flag, as KSKs do
+func hasSecureEntryPoint(keys []query.DNSKEYRecord) bool {
+	for _, key := range keys {
+		if key.Flags&1 == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// mismatch records a difference between the registry and the live DNS
+func (c *DNSCheck) mismatch(format string, args ...interface{}) {
+	c.Mismatches = append(c.Mismatches, fmt.Sprintf(format, args...))
+}
+
+// normalizeHost lowercases a host name and strips the trailing dot
+func normalizeHost(host string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(host), "."))
+}
@@ -0,0 +1,170 @@
+package domain
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+
+	"regard/internal/query"
+)
+
+// fakeResolver answers DNS lookups from maps
+type fakeResolver struct {
+	ns       []string
+	addrs    map[string][]string
+	keys     []query.DNSKEYRecord
+	notAuthz map[string]bool
+	noRoute  map[string]bool
+	ptr      map[string][]string
+}
+
+func (f fakeResolver) Server() string { return "fake" }
+
+func (f fakeResolver) LookupNS(name string) ([]string, error) { return f.ns, nil }
+
+func (f fakeResolver) LookupAddrs(host string) ([]string, error) {
+	if addrs, ok := f.addrs[host]; ok {
+		return addrs, nil
+	}
+	return nil, fmt.Errorf("%s: NXDOMAIN", host)
+}
+
+func (f fakeResolver) LookupPTR(name string) ([]string, error) {
+	if hosts, ok := f.ptr[name]; ok {
+		return hosts, nil
+	}
+	return nil, fmt.Errorf("%s PTR: NXDOMAIN", name)
+}
+
+func (f fakeResolver) LookupDNSKEY(name string) ([]query.DNSKEYRecord, error) { return f.keys, nil }
+
+func (f fakeResolver) CheckAuthoritative(addr, zone string) error {
+	if f.notAuthz[addr] {
+		return fmt.Errorf("answered REFUSED")
+	}
+	if f.noRoute[addr] {
+		return fmt.Errorf("%w: connect: network is unreachable", query.ErrUnreachable)
+	}
+	return nil
+}
+
+func TestCheckDNS(t *testing.T) {
+	ksk := query.DNSKEYRecord{Flags: 257, Protocol: 3, Algorithm: 13, PublicKey: []byte("test key material")}
+	digest, err := ksk.DSDigest("example.com", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	goodDS := newDSRecord(int(ksk.KeyTag()), 13, 2, hex.EncodeToString(digest))
+	staleDS := newDSRecord(int(ksk.KeyTag()), 13, 2, strings.Repeat("00", 32))
+
+	addrs := map[string][]string{
+		"ns1.example.com": {"192.0.2.1"},
+		"ns2.example.com": {"192.0.2.2"},
+	}
+
+	tests := []struct {
+		name        string
+		nameservers []string
+		dnssec      DNSSECInfo
+		resolver    fakeResolver
+		expect      []string // Substrings of the expected mismatches, in order
+		expectLame  string
+		expectSkip  string // A nameserver with an address left unchecked, and not lame
+	}{
+		{
+			name:        "Matching delegation",
+			nameservers: []string{"NS1.EXAMPLE.COM", "ns2.example.com."},
+			dnssec:      DNSSECInfo{Enabled: true, DSRecords: []DSRecord{goodDS}},
+			resolver:    fakeResolver{ns: []string{"ns1.example.com", "ns2.example.com"}, addrs: addrs, keys: []query.DNSKEYRecord{ksk}},
+		},
+		{
+			name:        "Nameserver sets differ",
+			nameservers: []string{"ns1.example.com", "ns2.example.com"},
+			resolver:    fakeResolver{ns: []string{"ns1.example.com", "ns3.example.com"}, addrs: map[string][]string{"ns1.example.com": {"192.0.2.1"}, "ns2.example.com": {"192.0.2.2"}, "ns3.example.com": {"192.0.2.3"}}},
+			expect: []string{
+				"ns2.example.com is delegated by the registry but missing",
+				"ns3.example.com is in the zone's NS records but not delegated",
+			},
+		},
+		{
+			name:        "Lame delegation",
+			nameservers: []string{"ns1.example.com", "ns2.example.com", "ns4.example.com"},
+			resolver:    fakeResolver{ns: []string{"ns1.example.com", "ns2.example.com", "ns4.example.com"}, addrs: addrs, notAuthz: map[string]bool{"192.0.2.2": true}},
+			expect: []string{
+				"ns2.example.com (192.0.2.2) is not authoritative",
+				"ns4.example.com does not resolve",
+			},
+			expectLame: "ns2.example.com",
+		},
+		{
+			name:        "IPv6 glue without local IPv6",
+			nameservers: []string{"ns1.example.com"},
+			resolver:    fakeResolver{ns: []string{"ns1.example.com"}, addrs: map[string][]string{"ns1.example.com": {"192.0.2.1", "2001:db8::1"}}, noRoute: map[string]bool{"2001:db8::1": true}},
+			expectSkip:  "ns1.example.com",
+		},
+		{
+			name:        "Stale DS record",
+			nameservers: []string{"ns1.example.com"},
+			dnssec:      DNSSECInfo{Enabled: true, DSRecords: []DSRecord{staleDS}},
+			resolver:    fakeResolver{ns: []string{"ns1.example.com"}, addrs: addrs, keys: []query.DNSKEYRecord{ksk}},
+			expect: []string{
+				"matches no live DNSKEY",
+				"No registry DS record matches",
+			},
+		},
+		{
+			name:        "DS without DNSKEY",
+			nameservers: []string{"ns1.example.com"},
+			dnssec:      DNSSECInfo{Enabled: true, DSRecords: []DSRecord{goodDS}},
+			resolver:    fakeResolver{ns: []string{"ns1.example.com"}, addrs: addrs},
+			expect:      []string{"the zone publishes no DNSKEY records"},
+		},
+		{
+			name:        "Signed zone without DS",
+			nameservers: []string{"ns1.example.com"},
+			resolver:    fakeResolver{ns: []string{"ns1.example.com"}, addrs: addrs, keys: []query.DNSKEYRecord{ksk}},
+			expect:      []string{"insecure delegation"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := Summary{Domain: "example.com", DNSSEC: tt.dnssec}
+			for _, name := range tt.nameservers {
+				summary.Nameservers = append(summary.Nameservers, Nameserver{Name: name})
+			}
+			check := CheckDNS(summary, tt.resolver)
+
+			if len(check.Mismatches) != len(tt.expect) {
+				t.Fatalf("Mismatches = %v, want %d", check.Mismatches, len(tt.expect))
+			}
+			for i, want := range tt.expect {
+				if !strings.Contains(check.Mismatches[i], want) {
+					t.Errorf("Mismatches[%d] = %q, want it to contain %q", i, check.Mismatches[i], want)
+				}
+			}
+			for _, ns := range check.Nameservers {
+				if ns.Name == tt.expectLame && !ns.Lame {
+					t.Errorf("%s not marked lame", ns.Name)
+				}
+				if ns.Name == tt.expectSkip && (ns.Lame || len(ns.Unreachable) != 1) {
+					t.Errorf("%s: Lame = %v, Unreachable = %v, want not lame with one unreachable address", ns.Name, ns.Lame, ns.Unreachable)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckDNS_DNSKeys(t *testing.T) {
+	ksk := query.DNSKEYRecord{Flags: 257, Protocol: 3, Algorithm: 8, PublicKey: []byte{1, 2, 3}}
+	check := CheckDNS(Summary{Domain: "example.com"}, fakeResolver{keys: []query.DNSKEYRecord{ksk}})
+
+	if len(check.DNSKeys) != 1 {
+		t.Fatalf("len(DNSKeys) = %d, want 1", len(check.DNSKeys))
+	}
+	key := check.DNSKeys[0]
+	if key.KeyTag != int(ksk.KeyTag()) || key.AlgorithmName != "RSASHA256" || key.PublicKey != "AQID" {
+		t.Errorf("DNSKeys[0] = %+v", key)
+	}
+}
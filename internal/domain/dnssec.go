@@ -0,0 +1,192 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// dnssecAlgorithm is an entry in the IANA DNS Security Algorithm Numbers registry
+type dnssecAlgorithm struct {
+	name       string
+	deprecated bool // MUST NOT or NOT RECOMMENDED for signing under RFC 8624
+}
+
+var dnssecAlgorithms = map[int]dnssecAlgorithm{
+	1:  {"RSAMD5", true},
+	3:  {"DSA", true},
+	5:  {"RSASHA1", true},
+	6:  {"DSA-NSEC3-SHA1", true},
+	7:  {"RSASHA1-NSEC3-SHA1", true},
+	8:  {"RSASHA256", false},
+	10: {"RSASHA512", false},
+	12: {"ECC-GOST", true},
+	13: {"ECDSAP256SHA256", false},
+	14: {"ECDSAP384SHA384", false},
+	15: {"ED25519", false},
+	16: {"ED448", false},
+}
+
+// dsDigestTypes is the IANA DS RR Type Digest Algorithms registry
+var dsDigestTypes = map[int]dnssecAlgorithm{
+	1: {"SHA-1", true},
+	2: {"SHA-256", false},
+	3: {"GOST R 34.11-94", true},
+	4: {"SHA-384", false},
+}
+
+// whoisDSKeys are the WHOIS keys carrying DS record data, e.g. the ICANN
+// "DNSSEC DS Data" and the RIPE-style "ds-rdata"
+var whoisDSKeys = map[string]bool{
+	"dnssec ds data": true,
+	"ds-rdata":       true,
+	"ds rdata":       true,
+	"ds record":      true,
+	"ds":             true,
+}
+
+// whoisKeyDataKeys are the WHOIS keys carrying DNSKEY data, e.g. DENIC "Dnskey"
+var whoisKeyDataKeys = map[string]bool{
+	"dnskey":               true,
+	"dnssec key data":      true,
+	"dnssec dnskey record": true,
+}
+
+// newDSRecord builds a DS record and names its algorithm and digest type
+func newDSRecord(keyTag, algorithm, digestType int, digest string) DSRecord {
+	record := DSRecord{
+		KeyTag:     keyTag,
+		Algorithm:  algorithm,
+		DigestType: digestType,
+		Digest:     strings.ToUpper(strings.ReplaceAll(digest, " ", "")),
+	}
+	if alg, ok := dnssecAlgorithms[algorithm]; ok {
+		record.AlgorithmName = alg.name
+	}
+	if digestAlg, ok := dsDigestTypes[digestType]; ok {
+		record.DigestTypeName = digestAlg.name
+	}
+	return record
+}
+
+// newDNSKey builds a DNSKEY record and names its algorithm
+func newDNSKey(flags, protocol, algorithm int, publicKey string) DNSKey {
+	key := DNSKey{
+		Flags:     flags,
+		Protocol:  protocol,
+		Algorithm: algorithm,
+		PublicKey: strings.ReplaceAll(publicKey, " ", ""),
+	}
+	if alg, ok := dnssecAlgorithms[algorithm]; ok {
+		key.AlgorithmName = alg.name
+	}
+	return key
+}
+
+// parseRDAPSecureDNS reads the RDAP secureDNS object into the DNSSEC summary
+func parseRDAPSecureDNS(secureDNS map[string]interface{}, info *DNSSECInfo) {
+	if delegationSigned, ok := secureDNS["DelegationSigned"].(bool); ok {
+		info.Enabled = delegationSigned
+		if delegationSigned {
+			info.Details = "Delegation signed"
+		}
+	}
+	if maxSigLife, ok := secureDNS["MaxSigLife"].(float64); ok {
+		info.MaxSigLife = uint64(maxSigLife)
+	}
+
+	if dsArray, ok := secureDNS["DS"].([]interface{}); ok {
+		for _, ds := range dsArray {
+			if dsObj, ok := ds.(map[string]interface{}); ok {
+				digest, _ := dsObj["Digest"].(string)
+				info.DSRecords = append(info.DSRecords, newDSRecord(
+					jsonInt(dsObj["KeyTag"]), jsonInt(dsObj["Algorithm"]), jsonInt(dsObj["DigestType"]), digest))
+			}
+		}
+	}
+
+	if keysArray, ok := secureDNS["Keys"].([]interface{}); ok {
+		for _, key := range keysArray {
+			if keyObj, ok := key.(map[string]interface{}); ok {
+				publicKey, _ := keyObj["PublicKey"].(string)
+				info.Keys = append(info.Keys, newDNSKey(
+					jsonInt(keyObj["Flags"]), jsonInt(keyObj["Protocol"]), jsonInt(keyObj["Algorithm"]), publicKey))
+			}
+		}
+	}
+}
+
+// parseWhoisDNSSECRecords reads DS and DNSKEY lines from a raw WHOIS
+// response, which the key/value parsers collapse to a single value
+func parseWhoisDNSSECRecords(raw string, info *DNSSECInfo) {
+	for _, line := range strings.Split(raw, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		// .se and .nu number their records "ds.1", "ds.2", ...
+		if base, _, found := strings.Cut(key, "."); found && base == "ds" {
+			key = base
+		}
+		fields := strings.Fields(value)
+		if len(fields) < 4 {
+			continue
+		}
+		first, err1 := strconv.Atoi(fields[0])
+		second, err2 := strconv.Atoi(fields[1])
+		third, err3 := strconv.Atoi(fields[2])
+		if err1 != nil || err2 != nil || err3 != nil {
+			continue
+		}
+
+		switch {
+		case whoisDSKeys[key]:
+			info.DSRecords = append(info.DSRecords, newDSRecord(first, second, third, strings.Join(fields[3:], "")))
+		case whoisKeyDataKeys[key]:
+			info.Keys = append(info.Keys, newDNSKey(first, second, third, strings.Join(fields[3:], "")))
+		}
+	}
+
+	if len(info.DSRecords) > 0 || len(info.Keys) > 0 {
+		info.Enabled = true
+	}
+}
+
+// assessDNSSEC flags deprecated algorithms and digest types
+func assessDNSSEC(info *DNSSECInfo) {
+	seen := make(map[string]bool)
+	flag := func(issue string) {
+		if !seen[issue] {
+			seen[issue] = true
+			info.Issues = append(info.Issues, issue)
+		}
+	}
+
+	for i := range info.DSRecords {
+		record := &info.DSRecords[i]
+		if alg, ok := dnssecAlgorithms[record.Algorithm]; ok && alg.deprecated {
+			record.Deprecated = true
+			flag(fmt.Sprintf("DS record %d uses deprecated algorithm %s (%d)", record.KeyTag, alg.name, record.Algorithm))
+		}
+		if digest, ok := dsDigestTypes[record.DigestType]; ok && digest.deprecated {
+			record.Deprecated = true
+			flag(fmt.Sprintf("DS record %d uses deprecated digest type %s (%d)", record.KeyTag, digest.name, record.DigestType))
+		}
+	}
+	for i := range info.Keys {
+		key := &info.Keys[i]
+		if alg, ok := dnssecAlgorithms[key.Algorithm]; ok && alg.deprecated {
+			key.Deprecated = true
+			flag(fmt.Sprintf("DNSKEY uses deprecated algorithm %s (%d)", alg.name, key.Algorithm))
+		}
+	}
+}
+
+// jsonInt reads a number decoded from JSON, which arrives as float64
+func jsonInt(v interface{}) int {
+	if f, ok := v.(float64); ok {
+		return int(f)
+	}
+	return 0
+}
@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"regard/internal/query"
+)
+
+func TestParseRDAPSecureDNS(t *testing.T) {
+	secureDNS := map[string]interface{}{
+		"DelegationSigned": true,
+		"MaxSigLife":       float64(604800),
+		"DS": []interface{}{
+			map[string]interface{}{"KeyTag": float64(370), "Algorithm": float64(13), "DigestType": float64(2), "Digest": "be74359954660069d5c63d200c39f5603827d7dd02b56f120ee9f3a86764247c"},
+		},
+		"Keys": []interface{}{
+			map[string]interface{}{"Flags": float64(257), "Protocol": float64(3), "Algorithm": float64(8), "PublicKey": "AwEAAa..."},
+		},
+	}
+
+	var info DNSSECInfo
+	parseRDAPSecureDNS(secureDNS, &info)
+
+	if !info.Enabled || info.MaxSigLife != 604800 {
+		t.Errorf("Enabled = %v, MaxSigLife = %d, want true, 604800", info.Enabled, info.MaxSigLife)
+	}
+	if len(info.DSRecords) != 1 {
+		t.Fatalf("len(DSRecords) = %d, want 1", len(info.DSRecords))
+	}
+	ds := info.DSRecords[0]
+	if ds.KeyTag != 370 || ds.AlgorithmName != "ECDSAP256SHA256" || ds.DigestTypeName != "SHA-256" || !strings.HasPrefix(ds.Digest, "BE7435") {
+		t.Errorf("DSRecords[0] = %+v", ds)
+	}
+	if len(info.Keys) != 1 || info.Keys[0].Flags != 257 || info.Keys[0].AlgorithmName != "RSASHA256" {
+		t.Errorf("Keys = %+v", info.Keys)
+	}
+}
+
+func TestParseWhoisDNSSECRecords(t *testing.T) {
+	tests := []struct {
+		name      string
+		raw       string
+		expectDS  int
+		expectKey int
+	}{
+		{
+			name:     "ICANN DS data",
+			raw:      "DNSSEC: signedDelegation\nDNSSEC DS Data: 370 13 2 BE74359954660069D5C63D200C39F5603827D7DD02B56F120EE9F3A86764247C\n",
+			expectDS: 1,
+		},
+		{
+			name:     "RIPE ds-rdata",
+			raw:      "domain:  2.0.192.in-addr.arpa\nds-rdata: 52037 8 2 A3D0D9D1C4B5E6F7A8B9C0D1E2F3A4B5C6D7E8F9A0B1C2D3E4F5A6B7C8D9E0F1\nds-rdata: 52037 8 1 3490A6806D47F17A34C29E2CE80E8A999FFBE4BE\n",
+			expectDS: 2,
+		},
+		{
+			name:     "Numbered .se records",
+			raw:      "ds.1: 12345 8 2 ABCDEF\nds.2: 12346 8 2 FEDCBA\n",
+			expectDS: 2,
+		},
+		{
+			name:      "DENIC Dnskey",
+			raw:       "Domain: example.de\nDnskey: 257 3 8 AwEAAb4N53k AwEAAa\n",
+			expectKey: 1,
+		},
+		{
+			name: "No records",
+			raw:  "DNSSEC: unsigned\nDS: none\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var info DNSSECInfo
+			parseWhoisDNSSECRecords(tt.raw, &info)
+			if len(info.DSRecords) != tt.expectDS || len(info.Keys) != tt.expectKey {
+				t.Errorf("got %d DS and %d keys, want %d and %d", len(info.DSRecords), len(info.Keys), tt.expectDS, tt.expectKey)
+			}
+			if info.Enabled != (tt.expectDS+tt.expectKey > 0) {
+				t.Errorf("Enabled = %v", info.Enabled)
+			}
+		})
+	}
+}
+
+func TestAssessDNSSEC(t *testing.T) {
+	info := DNSSECInfo{
+		DSRecords: []DSRecord{
+			newDSRecord(370, 13, 2, "BE74"),
+			newDSRecord(100, 1, 2, "AB"),
+			newDSRecord(200, 8, 1, "CD"),
+		},
+		Keys: []DNSKey{newDNSKey(257, 3, 3, "AwEA")},
+	}
+
+	assessDNSSEC(&info)
+
+	if info.DSRecords[0].Deprecated {
+		t.Error("ECDSAP256SHA256 with SHA-256 flagged as deprecated")
+	}
+	if !info.DSRecords[1].Deprecated || !info.DSRecords[2].Deprecated || !info.Keys[0].Deprecated {
+		t.Errorf("Expected RSAMD5, SHA-1 and DSA to be flagged, got %+v %+v", info.DSRecords, info.Keys)
+	}
+	issues := strings.Join(info.Issues, "\n")
+	for _, want := range []string{"RSAMD5", "SHA-1", "DSA"} {
+		if !strings.Contains(issues, want) {
+			t.Errorf("Issues = %v, want one mentioning %s", info.Issues, want)
+		}
+	}
+}
+
+func TestCreateSummary_WhoisDSRecords(t *testing.T) {
+	raw := "Domain Name: EXAMPLE.COM\nDNSSEC: signedDelegation\nDNSSEC DS Data: 31589 8 1 3490A6806D47F17A34C29E2CE80E8A999FFBE4BE\nDNSSEC DS Data: 43547 8 2 615A64233543F66F44D68933625B17497C89A70E858ED76A2145997EDF96A918\n"
+	summary := CreateSummary(query.QueryResult{
+		Query:     "example.com",
+		Type:      string(query.QueryTypeDomain),
+		Protocol:  "WHOIS",
+		Timestamp: time.Now(),
+		Success:   true,
+		Data: map[string]interface{}{
+			"parsed_fields": map[string]interface{}{
+				"domain_name": "EXAMPLE.COM",
+				"dnssec":      "signedDelegation",
+			},
+			"raw_response": raw,
+		},
+	})
+
+	if len(summary.DNSSEC.DSRecords) != 2 {
+		t.Fatalf("len(DSRecords) = %d, want 2", len(summary.DNSSEC.DSRecords))
+	}
+	if len(summary.DNSSEC.Issues) != 1 || !strings.Contains(summary.DNSSEC.Issues[0], "SHA-1") {
+		t.Errorf("Issues = %v, want the SHA-1 digest flagged", summary.DNSSEC.Issues)
+	}
+}
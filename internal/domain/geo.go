@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"net/netip"
+
+	"regard/internal/geo"
+)
+
+// AddGeolocation looks the queried address up in local geolocation databases.
+// Prefixes are skipped, since they can span many locations.
+func AddGeolocation(summary *IPSummary, databases *geo.Databases) {
+	addr, err := netip.ParseAddr(summary.Query)
+	if err != nil {
+		return
+	}
+
+	record, err := databases.Lookup(addr.WithZone(""))
+	if err != nil {
+		summary.Warnings = append(summary.Warnings, err.Error())
+		return
+	}
+	summary.Geolocation = record
+}
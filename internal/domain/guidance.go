@@ -8,12 +8,13 @@ import (
 
 // GeneratePostExpirationGuidance provides guidance for domain hunters interested in expired domains
 func GeneratePostExpirationGuidance(summary Summary) *ExpirationInfo {
-	if summary.Timeline.Expiration == nil {
+	expiration := EffectiveExpiration(summary.Timeline)
+	if expiration == nil {
 		return nil
 	}
 
 	now := time.Now()
-	expiryDate := summary.Timeline.Expiration.Date
+	expiryDate := expiration.Date
 
 	// Only provide guidance if domain is expired or expiring soon
 	if expiryDate.After(now.AddDate(0, 0, 30)) {
@@ -42,26 +43,30 @@ func GeneratePostExpirationGuidance(summary Summary) *ExpirationInfo {
 	// Extract TLD for specific guidance
 	tld := extractTLD(summary.Domain)
 
-	switch strings.ToLower(tld) {
-	case "com", "net", "org":
-		// Standard gTLD rules for domain hunters
-		if daysExpired <= 30 {
+	policy, hasPolicy := LookupLifecyclePolicy(tld)
+
+	switch {
+	case hasPolicy && policy.DropDays() > 0:
+		// Fixed drop schedule (e.g. gTLD rules) for domain hunters
+		redemptionEnd := policy.RenewalGraceDays + policy.RedemptionDays
+		if daysExpired <= policy.RenewalGraceDays {
 			guidance.GuidanceMessage = "Domain is in renewal grace period. Original owner can still renew. Not yet available for registration."
-		} else if daysExpired <= 75 {
+		} else if daysExpired <= redemptionEnd {
 			guidance.GuidanceMessage = "Domain is in redemption grace period. Original owner can still recover it with fees. Not available for public registration yet."
 		} else {
-			pendingDeleteDays := 80 - daysExpired
+			pendingDeleteDays := policy.DropDays() - daysExpired
 			if pendingDeleteDays > 0 {
 				guidance.GuidanceMessage = fmt.Sprintf("Domain is pending deletion! It will drop and become available for registration in approximately %d days.", pendingDeleteDays)
-				estimatedAvailable := expiryDate.AddDate(0, 0, 80)
+				estimatedAvailable := expiryDate.AddDate(0, 0, policy.DropDays())
 				guidance.AvailableDate = &estimatedAvailable
 			} else {
 				guidance.GuidanceMessage = "Domain has completed the deletion process and should be available for registration at any registrar."
 			}
 		}
-	case "uk", "co.uk":
-		if daysExpired <= 90 {
-			guidance.GuidanceMessage = "Domain is in renewal grace period (.uk domains have 90-day grace period). Original owner can still renew."
+	case hasPolicy:
+		// Registry holds the domain for a grace period, then releases it
+		if daysExpired <= policy.RenewalGraceDays {
+			guidance.GuidanceMessage = fmt.Sprintf("Domain is in renewal grace period (.%s domains have %d-day grace period). Original owner can still renew.", tld, policy.RenewalGraceDays)
 		} else {
 			guidance.GuidanceMessage = "Domain has passed the renewal grace period and should be available for public registration."
 		}
@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"regard/internal/query"
+	"regard/internal/rpsl"
+)
+
+// maxExpandedSets bounds the as-set queries one expansion makes
+const maxExpandedSets = 1000
+
+var errTooManySets = fmt.Errorf("stopped after expanding %d as-sets", maxExpandedSets)
+
+// IRRSource answers as-set and route object queries, as query.IRRClient does
+type IRRSource interface {
+	SetMembers(name string) ([]string, error)
+	RoutePrefixes(asn string) ([]string, error)
+}
+
+// ASSetExpansion is an as-set resolved recursively to its member AS numbers
+type ASSetExpansion struct {
+	Name     string     `json:"name"`
+	Server   string     `json:"server,omitempty"`
+	ASNs     []string   `json:"asns"`
+	Sets     []string   `json:"sets,omitempty"`    // Nested as-sets, in the order they were expanded
+	Cycles   [][]string `json:"cycles,omitempty"`  // Membership loops, e.g. [AS-A AS-B AS-A]
+	Missing  []string   `json:"missing,omitempty"` // Nested as-sets the IRR has no object for
+	Warnings []string   `json:"warnings,omitempty"`
+}
+
+// ExpandASSet resolves an as-set and every set nested in it, one level per
+// query, so loops between sets are detected and reported rather than followed
+func ExpandASSet(name string, irr IRRSource) (*ASSetExpansion, error) {
+	expansion := &ASSetExpansion{Name: strings.ToUpper(name), ASNs: []string{}}
+	asns := make(map[uint32]bool)
+	expanded := make(map[string]bool)
+	var path []string
+
+	var expand func(set string) error
+	expand = func(set string) error {
+		if len(expanded) >= maxExpandedSets {
+			return errTooManySets
+		}
+		expanded[set] = true
+		path = append(path, set)
+		defer func() { path = path[:len(path)-1] }()
+
+		members, err := irr.SetMembers(set)
+		if err != nil {
+			if len(path) > 1 && errors.Is(err, query.ErrIRRNotFound) {
+				expansion.Missing = appendUnique(expansion.Missing, set)
+				return nil
+			}
+			return err
+		}
+		if len(path) > 1 {
+			expansion.Sets = append(expansion.Sets, set)
+		}
+
+		for _, member := range members {
+			member = strings.ToUpper(member)
+			if number, ok := parseASNumber(member); ok {
+				asns[number] = true
+				continue
+			}
+			if !rpsl.IsASSetName(member) {
+				continue
+			}
+			if position := indexOf(path, member); position >= 0 {
+				expansion.Cycles = append(expansion.Cycles, append(append([]string{}, path[position:]...), member))
+				continue
+			}
+			if expanded[member] {
+				continue
+			}
+			if err := expand(member); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := expand(expansion.Name); err != nil {
+		if !errors.Is(err, errTooManySets) {
+			return nil, err
+		}
+		expansion.Warnings = append(expansion.Warnings, err.Error())
+	}
+
+	numbers := make([]uint32, 0, len(asns))
+	for number := range asns {
+		numbers = append(numbers, number)
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+	for _, number := range numbers {
+		expansion.ASNs = append(expansion.ASNs, fmt.Sprintf("AS%d", number))
+	}
+	return expansion, nil
+}
+
+func indexOf(values []string, value string) int {
+	for i, existing := range values {
+		if existing == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// AddIRRData lists the route objects registered for the queried AS and
+// expands the as-sets its routing policy names
+func AddIRRData(summary *Summary, irr IRRSource, server string) {
+	if summary.ASN == nil {
+		return
+	}
+	summary.ASN.IRRServer = server
+
+	routes, err := irr.RoutePrefixes(summary.ASN.Number)
+	if err != nil {
+		summary.Warnings = append(summary.Warnings, err.Error())
+	}
+	summary.ASN.IRRRoutes = routes
+
+	for _, set := range summary.ASN.ASSets {
+		expansion, err := ExpandASSet(set, irr)
+		if err != nil {
+			summary.Warnings = append(summary.Warnings, err.Error())
+			continue
+		}
+		summary.ASN.ASSetExpansions = append(summary.ASN.ASSetExpansions, *expansion)
+	}
+}
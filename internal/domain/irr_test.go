@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"regard/internal/query"
+)
+
+// fakeIRR answers from maps of as-set members and route objects by origin
+type fakeIRR struct {
+	sets   map[string][]string
+	routes map[string][]string
+	err    error
+}
+
+func (f fakeIRR) SetMembers(name string) ([]string, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	members, ok := f.sets[name]
+	if !ok {
+		return nil, fmt.Errorf("as-set %s: %w", name, query.ErrIRRNotFound)
+	}
+	return members, nil
+}
+
+func (f fakeIRR) RoutePrefixes(asn string) ([]string, error) {
+	return f.routes[asn], f.err
+}
+
+func TestExpandASSet(t *testing.T) {
+	irr := fakeIRR{sets: map[string][]string{
+		"AS-TOP":       {"AS64500", "AS-A", "AS-B", "AS-GONE"},
+		"AS-A":         {"AS64502", "AS-SHARED", "as64501"},
+		"AS-B":         {"AS-SHARED", "AS-TOP"},
+		"AS-SHARED":    {"AS64501", "AS-A"},
+		"AS-EMPTY":     {},
+		"AS1:AS-CHILD": {"AS1"},
+	}}
+
+	expansion, err := ExpandASSet("as-top", irr)
+	if err != nil {
+		t.Fatalf("ExpandASSet() unexpected error: %v", err)
+	}
+	if expected := []string{"AS64500", "AS64501", "AS64502"}; !reflect.DeepEqual(expansion.ASNs, expected) {
+		t.Errorf("ASNs = %v, want %v", expansion.ASNs, expected)
+	}
+	if expected := []string{"AS-A", "AS-SHARED", "AS-B"}; !reflect.DeepEqual(expansion.Sets, expected) {
+		t.Errorf("Sets = %v, want %v", expansion.Sets, expected)
+	}
+	if expected := [][]string{{"AS-A", "AS-SHARED", "AS-A"}, {"AS-TOP", "AS-B", "AS-TOP"}}; !reflect.DeepEqual(expansion.Cycles, expected) {
+		t.Errorf("Cycles = %v, want %v", expansion.Cycles, expected)
+	}
+	if !reflect.DeepEqual(expansion.Missing, []string{"AS-GONE"}) {
+		t.Errorf("Missing = %v, want [AS-GONE]", expansion.Missing)
+	}
+
+	if expansion, err := ExpandASSet("AS-EMPTY", irr); err != nil || len(expansion.ASNs) != 0 {
+		t.Errorf("ExpandASSet(AS-EMPTY) = %+v, %v", expansion, err)
+	}
+	if _, err := ExpandASSet("AS-UNKNOWN", irr); !errors.Is(err, query.ErrIRRNotFound) {
+		t.Errorf("ExpandASSet(AS-UNKNOWN) error = %v, want ErrIRRNotFound", err)
+	}
+}
+
+func TestAddIRRData(t *testing.T) {
+	irr := fakeIRR{
+		sets:   map[string][]string{"AS-EXAMPLE": {"AS64500", "AS64501"}},
+		routes: map[string][]string{"AS64500": {"192.0.2.0/24", "2001:db8::/32"}},
+	}
+	summary := Summary{ASN: &ASNInfo{Number: "AS64500", ASSets: []string{"AS-EXAMPLE", "AS-MISSING"}}}
+	AddIRRData(&summary, irr, "irr.example:43")
+
+	if !reflect.DeepEqual(summary.ASN.IRRRoutes, []string{"192.0.2.0/24", "2001:db8::/32"}) {
+		t.Errorf("IRRRoutes = %v", summary.ASN.IRRRoutes)
+	}
+	if len(summary.ASN.ASSetExpansions) != 1 || summary.ASN.ASSetExpansions[0].Name != "AS-EXAMPLE" {
+		t.Errorf("ASSetExpansions = %+v, want AS-EXAMPLE only", summary.ASN.ASSetExpansions)
+	}
+	if len(summary.Warnings) != 1 || summary.ASN.IRRServer != "irr.example:43" {
+		t.Errorf("Warnings = %v, IRRServer = %q; want a warning for AS-MISSING", summary.Warnings, summary.ASN.IRRServer)
+	}
+}
@@ -0,0 +1,52 @@
+package domain
+
+import "strings"
+
+// LifecyclePolicy describes what happens to a domain after it expires under a
+// TLD's registry policy. Periods are consecutive and counted in days.
+type LifecyclePolicy struct {
+	RenewalGraceDays  int    `json:"renewal_grace_days"`
+	RedemptionDays    int    `json:"redemption_days,omitempty"`
+	PendingDeleteDays int    `json:"pending_delete_days,omitempty"`
+	Notes             string `json:"notes,omitempty"`
+}
+
+// DropDays returns the number of days after expiry at which the domain is
+// released for registration, or 0 if the policy has no fixed drop schedule
+func (p LifecyclePolicy) DropDays() int {
+	if p.RedemptionDays == 0 && p.PendingDeleteDays == 0 {
+		return 0
+	}
+	return p.RenewalGraceDays + p.RedemptionDays + p.PendingDeleteDays
+}
+
+// gTLDLifecycle is the ICANN expiration lifecycle shared by gTLDs
+var gTLDLifecycle = LifecyclePolicy{
+	RenewalGraceDays:  30,
+	RedemptionDays:    45,
+	PendingDeleteDays: 5,
+	Notes:             "ICANN gTLD lifecycle: renewal grace, redemption grace, then pending delete",
+}
+
+// lifecyclePolicies holds the known per-TLD expiration policies
+var lifecyclePolicies = map[string]LifecyclePolicy{
+	"com":  gTLDLifecycle,
+	"net":  gTLDLifecycle,
+	"org":  gTLDLifecycle,
+	"info": gTLDLifecycle,
+	"biz":  gTLDLifecycle,
+	"uk": {
+		RenewalGraceDays: 90,
+		Notes:            "Nominet suspends expired domains and cancels them after 90 days",
+	},
+	"co.uk": {
+		RenewalGraceDays: 90,
+		Notes:            "Nominet suspends expired domains and cancels them after 90 days",
+	},
+}
+
+// LookupLifecyclePolicy returns the expiration policy for a TLD, if known
+func LookupLifecyclePolicy(tld string) (LifecyclePolicy, bool) {
+	policy, ok := lifecyclePolicies[strings.ToLower(tld)]
+	return policy, ok
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"net"
+	"strings"
+)
+
+// newNameserver creates a nameserver entry for a domain's delegation
+func newNameserver(name, domainName string) Nameserver {
+	host := normalizeHost(name)
+	zone := normalizeHost(domainName)
+	return Nameserver{
+		Name:        host,
+		InBailiwick: zone != "" && (host == zone || strings.HasSuffix(host, "."+zone)),
+	}
+}
+
+// addGlue records a glue address under the nameserver's IPv4 or IPv6 list
+func (ns *Nameserver) addGlue(addr string) {
+	ip := net.ParseIP(strings.TrimSpace(addr))
+	if ip == nil {
+		return
+	}
+	if ip.To4() != nil {
+		ns.IPv4 = appendUnique(ns.IPv4, ip.String())
+	} else {
+		ns.IPv6 = appendUnique(ns.IPv6, ip.String())
+	}
+}
+
+// parseRDAPNameserver reads an RDAP nameserver object
+func parseRDAPNameserver(nsObj map[string]interface{}, domainName string) (Nameserver, bool) {
+	name, _ := nsObj["LDHName"].(string)
+	if name == "" {
+		name, _ = nsObj["UnicodeName"].(string)
+	}
+	if name == "" {
+		return Nameserver{}, false
+	}
+
+	ns := newNameserver(name, domainName)
+	ns.Handle, _ = nsObj["Handle"].(string)
+	if addresses, ok := nsObj["IPAddresses"].(map[string]interface{}); ok {
+		for _, family := range []string{"V4", "V6"} {
+			if addrs, ok := addresses[family].([]interface{}); ok {
+				for _, addr := range addrs {
+					if addrStr, ok := addr.(string); ok {
+						ns.addGlue(addrStr)
+					}
+				}
+			}
+		}
+	}
+	if statuses, ok := nsObj["Status"].([]interface{}); ok {
+		for _, status := range statuses {
+			if statusStr, ok := status.(string); ok {
+				ns.Status = append(ns.Status, statusStr)
+			}
+		}
+	}
+	return ns, true
+}
+
+// findNameserver returns the summary's entry for a host, adding one if needed
+func findNameserver(summary *Summary, name string) *Nameserver {
+	host := normalizeHost(name)
+	for i := range summary.Nameservers {
+		if summary.Nameservers[i].Name == host {
+			return &summary.Nameservers[i]
+		}
+	}
+	summary.Nameservers = append(summary.Nameservers, newNameserver(host, summary.Domain))
+	return &summary.Nameservers[len(summary.Nameservers)-1]
+}
+
+// NameserverNames returns the host names of the nameservers
+func NameserverNames(nameservers []Nameserver) []string {
+	names := make([]string, 0, len(nameservers))
+	for _, ns := range nameservers {
+		names = append(names, ns.Name)
+	}
+	return names
+}
+
+func appendUnique(list []string, value string) []string {
+	for _, existing := range list {
+		if existing == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
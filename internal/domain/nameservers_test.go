@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"regard/internal/query"
+)
+
+func TestNewNameserver(t *testing.T) {
+	tests := []struct {
+		name        string
+		domain      string
+		expected    string
+		inBailiwick bool
+	}{
+		{"NS1.EXAMPLE.COM.", "example.com", "ns1.example.com", true},
+		{"ns1.example.net", "example.com", "ns1.example.net", false},
+		{"ns1.notexample.com", "example.com", "ns1.notexample.com", false},
+		{"ns1.example.com", "", "ns1.example.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ns := newNameserver(tt.name, tt.domain)
+			if ns.Name != tt.expected || ns.InBailiwick != tt.inBailiwick {
+				t.Errorf("newNameserver(%q, %q) = %+v, want name %q, in-bailiwick %v", tt.name, tt.domain, ns, tt.expected, tt.inBailiwick)
+			}
+		})
+	}
+}
+
+func TestCreateSummary_RDAPNameservers(t *testing.T) {
+	mockRDAPData := map[string]interface{}{
+		"objectClassName": "domain",
+		"ldhName":         "example.com",
+		"Nameservers": []interface{}{
+			map[string]interface{}{
+				"Handle":      "NS1-EXAMPLE",
+				"LDHName":     "NS1.EXAMPLE.COM",
+				"IPAddresses": map[string]interface{}{"V4": []interface{}{"192.0.2.1"}, "V6": []interface{}{"2001:DB8::1"}},
+				"Status":      []interface{}{"active"},
+			},
+			map[string]interface{}{"LDHName": "ns2.example.net"},
+		},
+	}
+
+	summary := CreateSummary(query.QueryResult{
+		Query:     "example.com",
+		Type:      string(query.QueryTypeDomain),
+		Protocol:  "RDAP",
+		Timestamp: time.Now(),
+		Success:   true,
+		Data:      mockRDAPData,
+	})
+
+	expected := []Nameserver{
+		{Name: "ns1.example.com", Handle: "NS1-EXAMPLE", IPv4: []string{"192.0.2.1"}, IPv6: []string{"2001:db8::1"}, InBailiwick: true, Status: []string{"active"}},
+		{Name: "ns2.example.net"},
+	}
+	if !reflect.DeepEqual(summary.Nameservers, expected) {
+		t.Errorf("Nameservers = %+v, want %+v", summary.Nameservers, expected)
+	}
+}
+
+func TestCreateSummary_RDAPNameserverLookup(t *testing.T) {
+	summary := CreateSummary(query.QueryResult{
+		Query:     "ns1.example.com",
+		Type:      string(query.QueryTypeNameserver),
+		Protocol:  "RDAP",
+		Timestamp: time.Now(),
+		Success:   true,
+		Data: map[string]interface{}{
+			"objectClassName": "nameserver",
+			"LDHName":         "ns1.example.com",
+			"IPAddresses":     map[string]interface{}{"V4": []interface{}{"192.0.2.1"}},
+			"Status":          []interface{}{"active"},
+		},
+	})
+
+	if len(summary.Nameservers) != 1 || !reflect.DeepEqual(summary.Nameservers[0].IPv4, []string{"192.0.2.1"}) {
+		t.Errorf("Nameservers = %+v, want the looked-up host with its address", summary.Nameservers)
+	}
+	if summary.Status != "active" {
+		t.Errorf("Status = %q, want active", summary.Status)
+	}
+}
+
+func TestCreateSummary_WhoisGlue(t *testing.T) {
+	summary := CreateSummary(query.QueryResult{
+		Query:     "example.fr",
+		Type:      string(query.QueryTypeDomain),
+		Protocol:  "WHOIS",
+		Timestamp: time.Now(),
+		Success:   true,
+		Data: map[string]interface{}{
+			"parsed_fields": map[string]interface{}{
+				"domain_name":      "example.fr",
+				"name_server":      []string{"ns1.example.fr", "ns2.example.net"},
+				"name_server_glue": []string{"ns1.example.fr 192.0.2.1", "ns1.example.fr 2001:db8::1"},
+			},
+			"raw_response": "domain: example.fr",
+		},
+	})
+
+	if names := NameserverNames(summary.Nameservers); !reflect.DeepEqual(names, []string{"ns1.example.fr", "ns2.example.net"}) {
+		t.Fatalf("Nameservers = %v", names)
+	}
+	ns := summary.Nameservers[0]
+	if !ns.InBailiwick || !reflect.DeepEqual(ns.IPv4, []string{"192.0.2.1"}) || !reflect.DeepEqual(ns.IPv6, []string{"2001:db8::1"}) {
+		t.Errorf("Nameservers[0] = %+v, want in-bailiwick with glue", ns)
+	}
+}
+
+func TestCreateSummary_GenericWhoisGlue(t *testing.T) {
+	summary := CreateSummary(query.QueryResult{
+		Query:     "example.com",
+		Type:      string(query.QueryTypeDomain),
+		Protocol:  "WHOIS",
+		Timestamp: time.Now(),
+		Success:   true,
+		Data: map[string]interface{}{
+			"parsed_fields": map[string]interface{}{
+				"domain_name": "EXAMPLE.COM",
+				"name_server": "NS1.EXAMPLE.COM 192.0.2.1",
+			},
+			"raw_response": "Domain Name: EXAMPLE.COM",
+		},
+	})
+
+	if len(summary.Nameservers) != 1 || summary.Nameservers[0].Name != "ns1.example.com" || !reflect.DeepEqual(summary.Nameservers[0].IPv4, []string{"192.0.2.1"}) {
+		t.Errorf("Nameservers = %+v", summary.Nameservers)
+	}
+}
@@ -0,0 +1,295 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"regard/internal/geo"
+	"regard/internal/query"
+	"regard/internal/routing"
+	"regard/internal/rpsl"
+)
+
+// IPSummary describes the registered network an IP address belongs to
+type IPSummary struct {
+	Query        string         `json:"query"`
+	Protocol     string         `json:"protocol"`
+	Server       string         `json:"server,omitempty"`
+	Handle       string         `json:"handle,omitempty"`
+	StartAddress string         `json:"start_address,omitempty"`
+	EndAddress   string         `json:"end_address,omitempty"`
+	CIDRs        []string       `json:"cidrs,omitempty"`
+	IPVersion    string         `json:"ip_version,omitempty"`
+	Name         string         `json:"name,omitempty"` // Netname
+	Type         string         `json:"type,omitempty"` // Allocation type, e.g. "DIRECT ALLOCATION" or "ASSIGNED PA"
+	Country      string         `json:"country,omitempty"`
+	ParentHandle string         `json:"parent_handle,omitempty"`
+	ParentLink   string         `json:"parent_link,omitempty"` // RDAP "up" link to the parent network
+	Status       []string       `json:"status,omitempty"`
+	Organization string         `json:"organization,omitempty"`
+	AbuseContact string         `json:"abuse_contact,omitempty"`
+	Contacts     []Contact      `json:"contacts,omitempty"`
+	Registration *TimelineEvent `json:"registration,omitempty"`
+	LastUpdated  *TimelineEvent `json:"last_updated,omitempty"`
+	Routing      *routing.Route `json:"routing,omitempty"`              // Announcing prefix from the local routing table
+	RPKI         *RouteValidity `json:"rpki,omitempty"`                 // Origin validation of the announcing prefix
+	Geolocation  *geo.Record    `json:"geolocation_database,omitempty"` // Local database data, not from the registry
+	ReverseDNS   *ReverseDNS    `json:"reverse_dns,omitempty"`          // Live PTR or reverse zone lookup, with --ptr
+	Warnings     []string       `json:"warnings,omitempty"`
+}
+
+// CreateIPSummary builds a network summary from an RDAP IP network object or
+// an RIR WHOIS response
+func CreateIPSummary(result query.QueryResult) IPSummary {
+	summary := IPSummary{
+		Query:    result.Query,
+		Protocol: result.Protocol,
+		Server:   result.Server,
+		Warnings: result.Warnings,
+	}
+
+	if result.Protocol == "RDAP" {
+		parseRDAPNetwork(result, &summary)
+	} else if data, ok := result.Data.(map[string]interface{}); ok {
+		if raw, ok := data["raw_response"].(string); ok {
+			parseRIRWhois(raw, &summary)
+		}
+	}
+
+	completeIPSummary(&summary)
+	return summary
+}
+
+// completeIPSummary derives the CIDRs, IP version, holder and abuse contact
+// when the registry didn't state them directly
+func completeIPSummary(summary *IPSummary) {
+	if len(summary.CIDRs) == 0 && summary.StartAddress != "" && summary.EndAddress != "" {
+		cidrs, err := rangeToCIDRs(summary.StartAddress, summary.EndAddress)
+		if err != nil {
+			summary.Warnings = append(summary.Warnings, fmt.Sprintf("Could not convert range to CIDRs: %v", err))
+		}
+		summary.CIDRs = cidrs
+	}
+	if summary.IPVersion == "" && summary.StartAddress != "" {
+		if addr, err := netip.ParseAddr(summary.StartAddress); err == nil {
+			summary.IPVersion = "v4"
+			if addr.Is6() && !addr.Is4In6() {
+				summary.IPVersion = "v6"
+			}
+		}
+	}
+
+	for _, contact := range summary.Contacts {
+		if summary.Organization == "" && contact.Role == "registrant" {
+			summary.Organization = firstNonEmpty(contact.Organization, contact.Name)
+		}
+		if summary.AbuseContact == "" && contact.Role == "abuse" {
+			summary.AbuseContact = contact.Email
+		}
+	}
+}
+
+// parseRDAPNetwork reads an RDAP ip network object
+func parseRDAPNetwork(result query.QueryResult, summary *IPSummary) {
+	var network map[string]interface{}
+	if jsonBytes, err := json.Marshal(result.Data); err == nil {
+		_ = json.Unmarshal(jsonBytes, &network)
+	}
+	if network == nil {
+		return
+	}
+
+	summary.Handle, _ = network["Handle"].(string)
+	summary.StartAddress, _ = network["StartAddress"].(string)
+	summary.EndAddress, _ = network["EndAddress"].(string)
+	summary.IPVersion, _ = network["IPVersion"].(string)
+	summary.Name, _ = network["Name"].(string)
+	summary.Type, _ = network["Type"].(string)
+	summary.Country, _ = network["Country"].(string)
+	summary.ParentHandle, _ = network["ParentHandle"].(string)
+	if statuses, ok := network["Status"].([]interface{}); ok {
+		for _, status := range statuses {
+			if statusStr, ok := status.(string); ok {
+				summary.Status = append(summary.Status, statusStr)
+			}
+		}
+	}
+	if links, ok := network["Links"].([]interface{}); ok {
+		for _, link := range links {
+			if linkObj, ok := link.(map[string]interface{}); ok && linkObj["Rel"] == "up" {
+				summary.ParentLink, _ = linkObj["Href"].(string)
+			}
+		}
+	}
+	if entities, ok := network["Entities"].([]interface{}); ok {
+		summary.Contacts = parseRDAPContacts(entities)
+		sortContacts(summary.Contacts)
+	}
+	if events, ok := network["Events"].([]interface{}); ok {
+		for _, event := range events {
+			eventObj, ok := event.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			action, _ := eventObj["Action"].(string)
+			dateStr, _ := eventObj["Date"].(string)
+			date, err := parseWhoisDate(dateStr)
+			if err != nil {
+				continue
+			}
+			timelineEvent := &TimelineEvent{Date: date, HumanReadable: HumanReadableTime(date)}
+			switch action {
+			case "registration":
+				summary.Registration = timelineEvent
+			case "last changed":
+				summary.LastUpdated = timelineEvent
+			}
+		}
+	}
+
+	// The cidr0 extension lists the network's prefixes; the library doesn't model it
+	var body struct {
+		CIDRs []struct {
+			V4Prefix string `json:"v4prefix"`
+			V6Prefix string `json:"v6prefix"`
+			Length   int    `json:"length"`
+		} `json:"cidr0_cidrs"`
+	}
+	if json.Unmarshal([]byte(result.RawData), &body) == nil {
+		for _, cidr := range body.CIDRs {
+			prefix := firstNonEmpty(cidr.V4Prefix, cidr.V6Prefix)
+			if prefix != "" {
+				summary.CIDRs = append(summary.CIDRs, fmt.Sprintf("%s/%d", prefix, cidr.Length))
+			}
+		}
+	}
+}
+
+// parseRIRWhois reads the network from an ARIN, RIPE, APNIC, AFRINIC or
+// LACNIC WHOIS response. ARIN lists less specific networks first, so the
+// last network object is the one containing the address.
+func parseRIRWhois(raw string, summary *IPSummary) {
+	response := rpsl.Parse(raw)
+	networks := response.Find("netrange", "inetnum", "inet6num")
+	if len(networks) == 0 {
+		return
+	}
+	parseRIRNetwork(response, networks[len(networks)-1], summary)
+}
+
+// parseRIRNetwork reads one inetnum, inet6num or ARIN NetRange object,
+// resolving the objects it references
+func parseRIRNetwork(response *rpsl.Response, network rpsl.Object, summary *IPSummary) {
+	if netRange := network.Key(); netRange != "" {
+		if start, end, ok := strings.Cut(netRange, "-"); ok {
+			summary.StartAddress = strings.TrimSpace(start)
+			summary.EndAddress = strings.TrimSpace(end)
+		} else if prefix, err := netip.ParsePrefix(expandShortPrefix(netRange)); err == nil {
+			summary.CIDRs = []string{prefix.Masked().String()}
+			summary.StartAddress = prefix.Masked().Addr().String()
+			summary.EndAddress = lastAddr(prefix.Masked()).String()
+		}
+	}
+	for _, cidr := range network.GetAll("cidr") {
+		for _, c := range strings.Split(cidr, ",") {
+			summary.CIDRs = append(summary.CIDRs, strings.TrimSpace(c))
+		}
+	}
+
+	org, _ := rpslOrganization(response, network)
+	summary.Name = network.Get("netname", "ownerid")
+	summary.Handle = network.Get("nethandle", "inetnum", "inet6num")
+	summary.Type = network.Get("nettype", "status")
+	summary.Country = strings.ToUpper(firstNonEmpty(network.Get("country"), org.Get("country")))
+	// ARIN writes the parent as "NET8 (NET-8-0-0-0-0)"
+	if parent := network.Get("parent"); parent != "" {
+		if i := strings.Index(parent, "("); i >= 0 {
+			parent = strings.TrimSuffix(parent[i+1:], ")")
+		}
+		summary.ParentHandle = strings.TrimSpace(parent)
+	}
+	summary.Organization = firstNonEmpty(org.Get("orgname", "org-name"), network.Get("owner", "descr"))
+	summary.AbuseContact = rpslAbuseContact(response, network)
+	summary.Contacts = rpslContacts(response, network)
+
+	hint := dateHint{}
+	if created := network.Get("regdate", "created"); created != "" {
+		if date, approximate, err := parseWhoisDateHint(created, hint); err == nil {
+			summary.Registration = &TimelineEvent{Date: date, HumanReadable: HumanReadableTime(date), Approximate: approximate}
+		}
+	}
+	if updated := network.Get("updated", "last-modified", "changed"); updated != "" {
+		if date, approximate, err := parseWhoisDateHint(updated, hint); err == nil {
+			summary.LastUpdated = &TimelineEvent{Date: date, HumanReadable: HumanReadableTime(date), Approximate: approximate}
+		}
+	}
+}
+
+// expandShortPrefix completes LACNIC's abbreviated prefixes such as "200.160/12"
+func expandShortPrefix(prefix string) string {
+	addr, length, ok := strings.Cut(strings.TrimSpace(prefix), "/")
+	if !ok || strings.Contains(addr, ":") {
+		return prefix
+	}
+	for strings.Count(addr, ".") < 3 {
+		addr += ".0"
+	}
+	return addr + "/" + length
+}
+
+// rangeToCIDRs splits an address range into the smallest covering set of prefixes
+func rangeToCIDRs(start, end string) ([]string, error) {
+	first, err := netip.ParseAddr(start)
+	if err != nil {
+		return nil, err
+	}
+	last, err := netip.ParseAddr(end)
+	if err != nil {
+		return nil, err
+	}
+	if first.BitLen() != last.BitLen() || last.Less(first) {
+		return nil, fmt.Errorf("invalid range %s - %s", start, end)
+	}
+
+	var cidrs []string
+	for {
+		// Grow the prefix while it stays aligned and inside the range
+		bits := first.BitLen()
+		for bits > 0 {
+			candidate := netip.PrefixFrom(first, bits-1).Masked()
+			if candidate.Addr() != first || lastAddr(candidate).Compare(last) > 0 {
+				break
+			}
+			bits--
+		}
+		prefix := netip.PrefixFrom(first, bits)
+		cidrs = append(cidrs, prefix.String())
+
+		prefixEnd := lastAddr(prefix)
+		if prefixEnd.Compare(last) >= 0 {
+			return cidrs, nil
+		}
+		first = prefixEnd.Next()
+	}
+}
+
+// lastAddr returns the highest address in a prefix
+func lastAddr(prefix netip.Prefix) netip.Addr {
+	bytes := prefix.Masked().Addr().AsSlice()
+	for i := prefix.Bits(); i < len(bytes)*8; i++ {
+		bytes[i/8] |= 1 << (7 - i%8)
+	}
+	addr, _ := netip.AddrFromSlice(bytes)
+	return addr
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
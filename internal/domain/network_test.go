@@ -0,0 +1,261 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"regard/internal/query"
+)
+
+const arinWhoisSample = `
+# ARIN WHOIS data and services are subject to the Terms of Use
+
+NetRange:       8.0.0.0 - 8.255.255.255
+CIDR:           8.0.0.0/8
+NetName:        LVLT-ORG-8-8
+NetHandle:      NET-8-0-0-0-1
+Parent:          ()
+NetType:        Direct Allocation
+Organization:   Level 3 Parent, LLC (LPL-141)
+RegDate:        1992-12-01
+Updated:        2018-04-23
+
+OrgName:        Level 3 Parent, LLC
+OrgId:          LPL-141
+Country:        US
+
+OrgAbuseHandle: LAC56-ARIN
+OrgAbuseEmail:  abuse@level3.com
+
+NetRange:       8.8.8.0 - 8.8.8.255
+CIDR:           8.8.8.0/24
+NetName:        GOGL
+NetHandle:      NET-8-8-8-0-2
+Parent:         LVLT-ORG-8-8 (NET-8-0-0-0-1)
+NetType:        Direct Allocation
+Organization:   Google LLC (GOGL)
+RegDate:        2023-12-28
+Updated:        2023-12-28
+
+OrgName:        Google LLC
+OrgId:          GOGL
+City:           Mountain View
+Country:        US
+
+OrgAbuseHandle: ABUSE5250-ARIN
+OrgAbuseName:   Abuse
+OrgAbuseEmail:  network-abuse@google.com
+`
+
+const ripeWhoisSample = `
+% This is the RIPE Database query service.
+
+% Information related to '193.0.0.0 - 193.0.7.255'
+
+inetnum:        193.0.0.0 - 193.0.7.255
+netname:        RIPE-NCC
+descr:          RIPE Network Coordination Centre
+descr:          Amsterdam, Netherlands
+org:            ORG-RIEN1-RIPE
+country:        NL
+admin-c:        BRD-RIPE
+tech-c:         OPS4-RIPE
+abuse-c:        OPS4-RIPE
+status:         ASSIGNED PA
+mnt-by:         RIPE-NCC-MNT
+created:        2003-03-17T12:15:57Z
+last-modified:  2017-12-04T14:52:44Z
+
+organisation:   ORG-RIEN1-RIPE
+org-name:       Reseaux IP Europeens Network Coordination Centre (RIPE NCC)
+country:        NL
+address:        P.O. Box 10096
+address:        1001 EB
+                Amsterdam
+
+role:           RIPE NCC Operations
+address:        Stationsplein 11
+nic-hdl:        OPS4-RIPE
+abuse-mailbox:  abuse@ripe.net
+mnt-by:         RIPE-NCC-MNT
+
+person:         Brian Riddle
+e-mail:         brian@example.net
+nic-hdl:        BRD-RIPE
+`
+
+const lacnicWhoisSample = `
+% LACNIC resource: whois.lacnic.net
+
+inetnum:     200.160/12
+status:      allocated
+owner:       Example Provedor
+ownerid:     BR-EXPR-LACNIC
+country:     BR
+created:     19980101
+`
+
+func TestCreateIPSummary_Whois(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		expected IPSummary
+		contacts []string // role/handle of the resolved contacts
+	}{
+		{
+			name: "ARIN",
+			raw:  arinWhoisSample,
+			expected: IPSummary{
+				Handle: "NET-8-8-8-0-2", StartAddress: "8.8.8.0", EndAddress: "8.8.8.255",
+				CIDRs: []string{"8.8.8.0/24"}, IPVersion: "v4", Name: "GOGL", Type: "Direct Allocation",
+				Country: "US", ParentHandle: "NET-8-0-0-0-1", Organization: "Google LLC",
+				AbuseContact: "network-abuse@google.com",
+			},
+			contacts: []string{"registrant/GOGL"},
+		},
+		{
+			name: "RIPE",
+			raw:  ripeWhoisSample,
+			expected: IPSummary{
+				Handle: "193.0.0.0 - 193.0.7.255", StartAddress: "193.0.0.0", EndAddress: "193.0.7.255",
+				CIDRs: []string{"193.0.0.0/21"}, IPVersion: "v4", Name: "RIPE-NCC", Type: "ASSIGNED PA",
+				Country: "NL", Organization: "Reseaux IP Europeens Network Coordination Centre (RIPE NCC)",
+				AbuseContact: "abuse@ripe.net",
+			},
+			contacts: []string{"registrant/ORG-RIEN1-RIPE", "administrative/BRD-RIPE", "technical/OPS4-RIPE", "abuse/OPS4-RIPE"},
+		},
+		{
+			name: "LACNIC",
+			raw:  lacnicWhoisSample,
+			expected: IPSummary{
+				Handle: "200.160/12", StartAddress: "200.160.0.0", EndAddress: "200.175.255.255",
+				CIDRs: []string{"200.160.0.0/12"}, IPVersion: "v4", Name: "BR-EXPR-LACNIC", Type: "allocated",
+				Country: "BR", Organization: "Example Provedor",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := CreateIPSummary(query.QueryResult{
+				Query:    "192.0.2.1",
+				Type:     string(query.QueryTypeIP),
+				Protocol: "WHOIS",
+				Success:  true,
+				Data:     map[string]interface{}{"raw_response": tt.raw},
+			})
+			if summary.Registration == nil {
+				t.Error("Registration not parsed")
+			}
+			var contacts []string
+			for _, contact := range summary.Contacts {
+				contacts = append(contacts, contact.Role+"/"+contact.Handle)
+			}
+			if !reflect.DeepEqual(contacts, tt.contacts) {
+				t.Errorf("Contacts = %v, want %v", contacts, tt.contacts)
+			}
+			tt.expected.Query, tt.expected.Protocol = "192.0.2.1", "WHOIS"
+			summary.Registration, summary.LastUpdated, summary.Contacts = nil, nil, nil
+			if !reflect.DeepEqual(summary, tt.expected) {
+				t.Errorf("CreateIPSummary() = %+v, want %+v", summary, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCreateIPSummary_RDAP(t *testing.T) {
+	network := map[string]interface{}{
+		"Handle":       "NET-8-8-8-0-2",
+		"StartAddress": "8.8.8.0",
+		"EndAddress":   "8.8.8.255",
+		"IPVersion":    "v4",
+		"Name":         "GOGL",
+		"Type":         "DIRECT ALLOCATION",
+		"ParentHandle": "NET-8-0-0-0-1",
+		"Status":       []interface{}{"active"},
+		"Events": []interface{}{
+			map[string]interface{}{"Action": "registration", "Date": "2023-12-28T17:24:33-05:00"},
+			map[string]interface{}{"Action": "last changed", "Date": "2023-12-28T17:24:56-05:00"},
+		},
+		"Entities": []interface{}{
+			map[string]interface{}{
+				"Handle": "GOGL",
+				"Roles":  []interface{}{"registrant"},
+				"VCard": map[string]interface{}{
+					"Properties": []interface{}{
+						map[string]interface{}{"Name": "fn", "Value": "Google LLC"},
+					},
+				},
+				"Entities": []interface{}{
+					map[string]interface{}{
+						"Handle": "ABUSE5250-ARIN",
+						"Roles":  []interface{}{"abuse"},
+						"VCard": map[string]interface{}{
+							"Properties": []interface{}{
+								map[string]interface{}{"Name": "fn", "Value": "Abuse"},
+								map[string]interface{}{"Name": "email", "Value": "network-abuse@google.com"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	summary := CreateIPSummary(query.QueryResult{
+		Query:     "8.8.8.8",
+		Type:      string(query.QueryTypeIP),
+		Protocol:  "RDAP",
+		Timestamp: time.Now(),
+		Success:   true,
+		Data:      network,
+		RawData:   `{"cidr0_cidrs": [{"v4prefix": "8.8.8.0", "length": 24}]}`,
+	})
+
+	if summary.Handle != "NET-8-8-8-0-2" || summary.Name != "GOGL" || summary.ParentHandle != "NET-8-0-0-0-1" {
+		t.Errorf("Unexpected network fields: %+v", summary)
+	}
+	if !reflect.DeepEqual(summary.CIDRs, []string{"8.8.8.0/24"}) {
+		t.Errorf("CIDRs = %v, want [8.8.8.0/24]", summary.CIDRs)
+	}
+	if summary.Organization != "Google LLC" {
+		t.Errorf("Organization = %q, want Google LLC", summary.Organization)
+	}
+	if summary.AbuseContact != "network-abuse@google.com" {
+		t.Errorf("AbuseContact = %q, want network-abuse@google.com", summary.AbuseContact)
+	}
+	if summary.Registration == nil || summary.Registration.Date.Year() != 2023 {
+		t.Errorf("Registration = %+v", summary.Registration)
+	}
+}
+
+func TestRangeToCIDRs(t *testing.T) {
+	tests := []struct {
+		start    string
+		end      string
+		expected []string
+	}{
+		{"8.8.8.0", "8.8.8.255", []string{"8.8.8.0/24"}},
+		{"0.0.0.0", "255.255.255.255", []string{"0.0.0.0/0"}},
+		{"192.0.2.1", "192.0.2.1", []string{"192.0.2.1/32"}},
+		{"192.0.2.0", "192.0.2.10", []string{"192.0.2.0/29", "192.0.2.8/31", "192.0.2.10/32"}},
+		{"2001:db8::", "2001:db8:ffff:ffff:ffff:ffff:ffff:ffff", []string{"2001:db8::/32"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.start+"-"+tt.end, func(t *testing.T) {
+			cidrs, err := rangeToCIDRs(tt.start, tt.end)
+			if err != nil {
+				t.Fatalf("rangeToCIDRs(%q, %q) error: %v", tt.start, tt.end, err)
+			}
+			if !reflect.DeepEqual(cidrs, tt.expected) {
+				t.Errorf("rangeToCIDRs(%q, %q) = %v, want %v", tt.start, tt.end, cidrs, tt.expected)
+			}
+		})
+	}
+
+	if _, err := rangeToCIDRs("192.0.2.10", "192.0.2.1"); err == nil {
+		t.Error("rangeToCIDRs() with a reversed range: expected error")
+	}
+}
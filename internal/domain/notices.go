@@ -0,0 +1,169 @@
+package domain
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// redactedFieldSuffixes maps the field part of RFC 9537 redaction names such as
+// "Registrant Email" onto Contact fields
+var redactedFieldSuffixes = map[string]string{
+	"name":           "name",
+	"organization":   "organization",
+	"email":          "email",
+	"phone":          "phone",
+	"phone ext":      "phone",
+	"street":         "address",
+	"city":           "address",
+	"state/province": "address",
+	"postal code":    "address",
+	"country":        "country",
+}
+
+// redactedRolePrefixes maps the role part of RFC 9537 redaction names onto contact roles
+var redactedRolePrefixes = map[string]string{
+	"registrant": "registrant",
+	"admin":      "administrative",
+	"tech":       "technical",
+	"billing":    "billing",
+}
+
+// parseRDAPNotices converts RDAP notices or remarks into summary notices
+func parseRDAPNotices(items []interface{}) []Notice {
+	var notices []Notice
+
+	for _, item := range items {
+		itemObj, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		notice := Notice{}
+		notice.Title, _ = itemObj["Title"].(string)
+		notice.Type, _ = itemObj["Type"].(string)
+		if descArray, ok := itemObj["Description"].([]interface{}); ok {
+			for _, desc := range descArray {
+				if line, ok := desc.(string); ok && strings.TrimSpace(line) != "" {
+					notice.Description = append(notice.Description, line)
+				}
+			}
+		}
+		if linksArray, ok := itemObj["Links"].([]interface{}); ok {
+			for _, link := range linksArray {
+				if linkObj, ok := link.(map[string]interface{}); ok {
+					if href, ok := linkObj["Href"].(string); ok && href != "" {
+						notice.Links = append(notice.Links, href)
+					}
+				}
+			}
+		}
+
+		if notice.Title != "" || len(notice.Description) > 0 || len(notice.Links) > 0 {
+			notices = append(notices, notice)
+		}
+	}
+
+	return notices
+}
+
+// parseRDAPRedactions extracts the RFC 9537 "redacted" member from a raw RDAP response
+func parseRDAPRedactions(rawData string) []Redaction {
+	var raw map[string]interface{}
+	if err := json.Unmarshal([]byte(rawData), &raw); err != nil {
+		return nil
+	}
+
+	redactedArray, ok := raw["redacted"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var redactions []Redaction
+	for _, item := range redactedArray {
+		itemObj, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		redaction := Redaction{
+			Field:  typeOrDescription(itemObj["name"]),
+			Reason: typeOrDescription(itemObj["reason"]),
+		}
+		redaction.Method, _ = itemObj["method"].(string)
+		if redaction.Method == "" {
+			// RFC 9537 makes removal the default method
+			redaction.Method = "removal"
+		}
+		for _, pathKey := range []string{"prePath", "postPath", "replacementPath"} {
+			if path, ok := itemObj[pathKey].(string); ok && path != "" {
+				redaction.Path = path
+				break
+			}
+		}
+
+		if redaction.Field != "" {
+			redactions = append(redactions, redaction)
+		}
+	}
+
+	return redactions
+}
+
+// typeOrDescription reads RFC 9537 name/reason objects, which carry either a
+// registered "type" or a free-form "description"
+func typeOrDescription(value interface{}) string {
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	if t, ok := obj["type"].(string); ok && t != "" {
+		return t
+	}
+	desc, _ := obj["description"].(string)
+	return desc
+}
+
+// applyRedactions marks contact fields named in the redaction list as redacted,
+// adding contacts for roles the registry removed entirely
+func applyRedactions(contacts []Contact, redactions []Redaction) []Contact {
+	for _, redaction := range redactions {
+		lower := strings.ToLower(redaction.Field)
+		parts := strings.SplitN(lower, " ", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		role, ok := redactedRolePrefixes[parts[0]]
+		if !ok {
+			continue
+		}
+		field, ok := redactedFieldSuffixes[parts[1]]
+		if !ok {
+			continue
+		}
+
+		index := -1
+		for i := range contacts {
+			if contacts[i].Role == role {
+				index = i
+				break
+			}
+		}
+		if index == -1 {
+			contacts = append(contacts, Contact{Role: role})
+			index = len(contacts) - 1
+		}
+
+		alreadyRedacted := false
+		for _, existing := range contacts[index].Redacted {
+			if existing == field {
+				alreadyRedacted = true
+				break
+			}
+		}
+		if !alreadyRedacted {
+			contacts[index].Redacted = append(contacts[index].Redacted, field)
+		}
+	}
+
+	return contacts
+}
@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+
+	"regard/internal/query"
+)
+
+func TestParseRDAPRedactions(t *testing.T) {
+	rawData := `{
+		"objectClassName": "domain",
+		"redacted": [
+			{
+				"name": {"type": "Registrant Name"},
+				"prePath": "$.entities[?(@.roles[0]=='registrant')].vcardArray[1][?(@[0]=='fn')][3]",
+				"method": "emptyValue",
+				"reason": {"type": "Server policy"}
+			},
+			{
+				"name": {"description": "Registrant Email"},
+				"method": "replacementValue",
+				"replacementPath": "$.entities[?(@.roles[0]=='registrant')].vcardArray[1][?(@[0]=='contact-uri')][3]"
+			},
+			{
+				"name": {"type": "Tech Phone"}
+			},
+			{
+				"reason": {"type": "missing name"}
+			}
+		]
+	}`
+
+	redactions := parseRDAPRedactions(rawData)
+
+	expected := []Redaction{
+		{Field: "Registrant Name", Method: "emptyValue", Reason: "Server policy", Path: "$.entities[?(@.roles[0]=='registrant')].vcardArray[1][?(@[0]=='fn')][3]"},
+		{Field: "Registrant Email", Method: "replacementValue", Path: "$.entities[?(@.roles[0]=='registrant')].vcardArray[1][?(@[0]=='contact-uri')][3]"},
+		{Field: "Tech Phone", Method: "removal"},
+	}
+
+	if !reflect.DeepEqual(redactions, expected) {
+		t.Errorf("parseRDAPRedactions() =\n%+v\nwant\n%+v", redactions, expected)
+	}
+
+	if result := parseRDAPRedactions("not json"); result != nil {
+		t.Errorf("Expected nil redactions for invalid JSON, got %+v", result)
+	}
+}
+
+func TestApplyRedactions(t *testing.T) {
+	contacts := []Contact{
+		{Role: "registrant", Organization: "Example Org", Redacted: []string{"name"}},
+	}
+	redactions := []Redaction{
+		{Field: "Registrant Name"},
+		{Field: "Registrant Email"},
+		{Field: "Tech Phone"},
+		{Field: "Registry Domain ID"},
+	}
+
+	result := applyRedactions(contacts, redactions)
+
+	expected := []Contact{
+		{Role: "registrant", Organization: "Example Org", Redacted: []string{"name", "email"}},
+		{Role: "technical", Redacted: []string{"phone"}},
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("applyRedactions() =\n%+v\nwant\n%+v", result, expected)
+	}
+}
+
+func TestCreateSummary_RDAPNotices(t *testing.T) {
+	mockRDAPData := map[string]interface{}{
+		"Status": []interface{}{"active"},
+		"Notices": []interface{}{
+			map[string]interface{}{
+				"Title":       "Terms of Use",
+				"Description": []interface{}{"Service subject to Terms of Use."},
+				"Links": []interface{}{
+					map[string]interface{}{"Href": "https://www.example.com/terms"},
+				},
+			},
+			map[string]interface{}{
+				"Title":       "Status Codes",
+				"Description": []interface{}{"For more information on domain status codes, please visit https://icann.org/epp"},
+			},
+		},
+		"Remarks": []interface{}{
+			map[string]interface{}{"Description": []interface{}{"", "Registry remark"}},
+		},
+	}
+
+	mockResult := query.QueryResult{
+		Query:    "example.com",
+		Type:     string(query.QueryTypeDomain),
+		Protocol: "RDAP",
+		Success:  true,
+		Data:     mockRDAPData,
+		RawData:  `{"redacted":[{"name":{"type":"Registrant Name"},"method":"removal"}]}`,
+	}
+
+	summary := CreateSummary(mockResult)
+
+	if len(summary.Notices) != 2 {
+		t.Fatalf("Expected 2 notices, got %d", len(summary.Notices))
+	}
+	if summary.Notices[0].Title != "Terms of Use" || len(summary.Notices[0].Links) != 1 {
+		t.Errorf("Unexpected first notice: %+v", summary.Notices[0])
+	}
+	if len(summary.Remarks) != 1 || !reflect.DeepEqual(summary.Remarks[0].Description, []string{"Registry remark"}) {
+		t.Errorf("Unexpected remarks: %+v", summary.Remarks)
+	}
+	if len(summary.Redactions) != 1 {
+		t.Fatalf("Expected 1 redaction, got %d", len(summary.Redactions))
+	}
+	if len(summary.Contacts) != 1 || summary.Contacts[0].Role != "registrant" {
+		t.Errorf("Expected redacted registrant contact, got %+v", summary.Contacts)
+	}
+}
@@ -3,18 +3,38 @@ package domain
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"regard/internal/query"
 )
 
+// timelineFieldActions are the RDAP event actions with their own Timeline field
+var timelineFieldActions = map[string]bool{
+	"registration":                 true,
+	"last changed":                 true,
+	"expiration":                   true,
+	"registrar expiration":         true,
+	"transfer":                     true,
+	"reregistration":               true,
+	"locked":                       true,
+	"unlocked":                     true,
+	"last update of RDAP database": true,
+}
+
+// TimelineFieldAction reports whether an RDAP event action has its own Timeline field
+func TimelineFieldAction(action string) bool {
+	return timelineFieldActions[action]
+}
+
 // CreateSummary converts a QueryResult into a structured domain summary
 func CreateSummary(result query.QueryResult) Summary {
 	summary := Summary{
 		Domain:    result.Query,
 		Protocol:  result.Protocol,
 		QueryType: result.Type,
+		Warnings:  result.Warnings,
 	}
 
 	if result.Protocol == "RDAP" {
@@ -23,17 +43,36 @@ func CreateSummary(result query.QueryResult) Summary {
 		summary = parseWhoisSummary(result, summary)
 	}
 
+	if summary.Registrar.Name != "" || summary.Registrar.ID != "" {
+		enrichRegistrar(&summary)
+	}
+
 	// Parse ASN information if this is an ASN query
 	if result.Type == string(query.QueryTypeASN) {
-		summary.ASN = parseASNInfo(result)
+		if result.Protocol == "RDAP" {
+			summary.ASN = parseRDAPAutnum(result, summary)
+		} else {
+			summary.ASN = parseASNInfo(result)
+		}
 		// For ASNs, use the ASN status as the summary status
 		if summary.ASN != nil && summary.ASN.Status != "" {
 			summary.Status = summary.ASN.Status
 		}
 	}
 
+	// Reverse zones cover an address block rather than a registered name
+	if result.Type == string(query.QueryTypeReverse) {
+		summary.ReverseZone = parseReverseZone(result, &summary)
+	}
+
+	assessDNSSEC(&summary.DNSSEC)
+	detectRenewal(&summary)
+	if result.Type == string(query.QueryTypeDomain) {
+		summary.Transfer = AnalyzeTransfer(summary)
+	}
+
 	// Add post-expiration guidance if needed
-	if summary.Timeline.Expiration != nil {
+	if EffectiveExpiration(summary.Timeline) != nil {
 		summary.PostExpiration = GeneratePostExpirationGuidance(summary)
 	}
 
@@ -69,21 +108,22 @@ func parseRDAPSummary(result query.QueryResult, summary Summary) Summary {
 		if nsArray, ok := domainData["Nameservers"].([]interface{}); ok {
 			for _, ns := range nsArray {
 				if nsObj, ok := ns.(map[string]interface{}); ok {
-					if name, ok := nsObj["LDHName"].(string); ok {
-						summary.Nameservers = append(summary.Nameservers, name)
+					if nameserver, ok := parseRDAPNameserver(nsObj, summary.Domain); ok {
+						summary.Nameservers = append(summary.Nameservers, nameserver)
 					}
 				}
 			}
 		}
+		// A /nameserver/ lookup returns the nameserver object itself
+		if result.Type == string(query.QueryTypeNameserver) {
+			if nameserver, ok := parseRDAPNameserver(domainData, ""); ok {
+				summary.Nameservers = append(summary.Nameservers, nameserver)
+			}
+		}
 
 		// DNSSEC
 		if secureDNS, ok := domainData["SecureDNS"].(map[string]interface{}); ok {
-			if delegationSigned, ok := secureDNS["DelegationSigned"].(bool); ok {
-				summary.DNSSEC.Enabled = delegationSigned
-				if delegationSigned {
-					summary.DNSSEC.Details = "Delegation signed"
-				}
-			}
+			parseRDAPSecureDNS(secureDNS, &summary.DNSSEC)
 		}
 
 		// Events (timeline)
@@ -93,23 +133,60 @@ func parseRDAPSummary(result query.QueryResult, summary Summary) Summary {
 					action, _ := eventObj["Action"].(string)
 					dateStr, _ := eventObj["Date"].(string)
 
-					if date, err := time.Parse(time.RFC3339, dateStr); err == nil {
-						timelineEvent := &TimelineEvent{
-							Date:          date,
-							HumanReadable: HumanReadableTime(date),
-						}
+					date, err := time.Parse(time.RFC3339, dateStr)
+					if err != nil {
+						// Some servers omit the zone or use other layouts
+						date, err = parseWhoisDate(dateStr)
+					}
+					if err != nil {
+						summary.Warnings = append(summary.Warnings, fmt.Sprintf("Could not parse %s date %q", action, dateStr))
+						continue
+					}
 
-						switch action {
-						case "registration":
-							summary.Timeline.Registration = timelineEvent
-						case "last changed", "last update of RDAP database":
-							summary.Timeline.LastUpdated = timelineEvent
-						case "expiration":
-							summary.Timeline.Expiration = timelineEvent
-						}
+					timelineEvent := &TimelineEvent{
+						Date:          date,
+						HumanReadable: HumanReadableTime(date),
+					}
+					actor, _ := eventObj["Actor"].(string)
+					summary.Timeline.Events = append(summary.Timeline.Events, TimelineEvent{
+						Date:          date,
+						HumanReadable: timelineEvent.HumanReadable,
+						Action:        action,
+						Actor:         actor,
+					})
+
+					timelineEvent.Actor = actor
+
+					var field **TimelineEvent
+					switch action {
+					case "registration":
+						field = &summary.Timeline.Registration
+					case "last changed":
+						field = &summary.Timeline.LastUpdated
+					case "expiration":
+						field = &summary.Timeline.Expiration
+					case "registrar expiration":
+						field = &summary.Timeline.RegistrarExpiration
+					case "transfer":
+						field = &summary.Timeline.Transfer
+					case "reregistration":
+						field = &summary.Timeline.Reregistration
+					case "locked":
+						field = &summary.Timeline.Locked
+					case "unlocked":
+						field = &summary.Timeline.Unlocked
+					case "last update of RDAP database":
+						field = &summary.Timeline.DatabaseUpdated
+					}
+					// Keep the most recent when an action repeats, such as several transfers
+					if field != nil && (*field == nil || date.After((*field).Date)) {
+						*field = timelineEvent
 					}
 				}
 			}
+			sort.SliceStable(summary.Timeline.Events, func(i, j int) bool {
+				return summary.Timeline.Events[i].Date.Before(summary.Timeline.Events[j].Date)
+			})
 		}
 
 		// Registrar info
@@ -135,13 +212,36 @@ func parseRDAPSummary(result query.QueryResult, summary Summary) Summary {
 								if handle, ok := entityObj["Handle"].(string); ok {
 									summary.Registrar.ID = handle
 								}
+								if publicIDs, ok := entityObj["PublicIDs"].([]interface{}); ok {
+									for _, publicID := range publicIDs {
+										if idObj, ok := publicID.(map[string]interface{}); ok {
+											if idType, _ := idObj["Type"].(string); idType == "IANA Registrar ID" {
+												summary.Registrar.IANAID, _ = idObj["Identifier"].(string)
+											}
+										}
+									}
+								}
 								break
 							}
 						}
 					}
 				}
 			}
+
+			// Contacts
+			summary.Contacts = parseRDAPContacts(entitiesArray)
 		}
+
+		// Notices, remarks and redactions
+		if noticesArray, ok := domainData["Notices"].([]interface{}); ok {
+			summary.Notices = parseRDAPNotices(noticesArray)
+		}
+		if remarksArray, ok := domainData["Remarks"].([]interface{}); ok {
+			summary.Remarks = parseRDAPNotices(remarksArray)
+		}
+		summary.Redactions = parseRDAPRedactions(result.RawData)
+		summary.Contacts = applyRedactions(summary.Contacts, summary.Redactions)
+		sortContacts(summary.Contacts)
 	}
 
 	return summary
@@ -154,6 +254,11 @@ func parseWhoisSummary(result query.QueryResult, summary Summary) Summary {
 			// Domain status from various possible fields
 			statusFields := []string{"domain_status", "status"}
 			for _, field := range statusFields {
+				// Registry-specific parsers report statuses already split
+				if statuses, ok := fields[field].([]string); ok {
+					summary.StatusDetails = append(summary.StatusDetails, statuses...)
+					continue
+				}
 				if status, ok := fields[field].(string); ok {
 					// Extract status codes from WHOIS format
 					statusParts := strings.Fields(status)
@@ -179,19 +284,30 @@ func parseWhoisSummary(result query.QueryResult, summary Summary) Summary {
 
 			// Nameservers - collect all nameserver entries
 			for key, value := range fields {
-				if strings.Contains(strings.ToLower(key), "name_server") || strings.Contains(strings.ToLower(key), "nameserver") {
-					if ns, ok := value.(string); ok {
-						// Avoid duplicates
-						found := false
-						for _, existing := range summary.Nameservers {
-							if existing == ns {
-								found = true
-								break
-							}
-						}
-						if !found {
-							summary.Nameservers = append(summary.Nameservers, ns)
-						}
+				lowerKey := strings.ToLower(key)
+				if lowerKey == "name_server_glue" || !(strings.Contains(lowerKey, "name_server") || strings.Contains(lowerKey, "nameserver")) {
+					continue
+				}
+				nameservers, _ := value.([]string)
+				if ns, ok := value.(string); ok {
+					nameservers = []string{ns}
+				}
+				for _, line := range nameservers {
+					// Generic WHOIS may list glue after the host
+					parts := strings.Fields(line)
+					if len(parts) == 0 {
+						continue
+					}
+					ns := findNameserver(&summary, parts[0])
+					for _, addr := range parts[1:] {
+						ns.addGlue(strings.Trim(addr, "[](),"))
+					}
+				}
+			}
+			if glue, ok := fields["name_server_glue"].([]string); ok {
+				for _, entry := range glue {
+					if host, addr, ok := strings.Cut(entry, " "); ok {
+						findNameserver(&summary, host).addGlue(addr)
 					}
 				}
 			}
@@ -201,33 +317,26 @@ func parseWhoisSummary(result query.QueryResult, summary Summary) Summary {
 				summary.DNSSEC.Enabled = dnssec == "signedDelegation"
 				summary.DNSSEC.Details = dnssec
 			}
+			if raw, ok := data["raw_response"].(string); ok {
+				parseWhoisDNSSECRecords(raw, &summary.DNSSEC)
+			}
 
 			// Timeline
+			hint := dateHintForDomain(summary.Domain)
 			if created, ok := fields["creation_date"].(string); ok {
-				if date, err := parseWhoisDate(created); err == nil {
-					summary.Timeline.Registration = &TimelineEvent{
-						Date:          date,
-						HumanReadable: HumanReadableTime(date),
-					}
-				}
+				summary.Timeline.Registration = whoisTimelineEvent(&summary, "registration", created, hint)
 			}
 
 			if updated, ok := fields["updated_date"].(string); ok {
-				if date, err := parseWhoisDate(updated); err == nil {
-					summary.Timeline.LastUpdated = &TimelineEvent{
-						Date:          date,
-						HumanReadable: HumanReadableTime(date),
-					}
-				}
+				summary.Timeline.LastUpdated = whoisTimelineEvent(&summary, "last updated", updated, hint)
 			}
 
 			if expiry, ok := fields["registry_expiry_date"].(string); ok {
-				if date, err := parseWhoisDate(expiry); err == nil {
-					summary.Timeline.Expiration = &TimelineEvent{
-						Date:          date,
-						HumanReadable: HumanReadableTime(date),
-					}
-				}
+				summary.Timeline.Expiration = whoisTimelineEvent(&summary, "expiration", expiry, hint)
+			}
+
+			if expiry, ok := fields["registrar_registration_expiration_date"].(string); ok {
+				summary.Timeline.RegistrarExpiration = whoisTimelineEvent(&summary, "registrar expiration", expiry, hint)
 			}
 
 			// Registrar
@@ -237,30 +346,28 @@ func parseWhoisSummary(result query.QueryResult, summary Summary) Summary {
 			if registrarID, ok := fields["registrar_iana_id"].(string); ok {
 				summary.Registrar.ID = registrarID
 			}
+
+			// Contacts
+			summary.Contacts = parseWhoisContacts(fields)
 		}
 	}
 
 	return summary
 }
 
-func parseWhoisDate(dateStr string) (time.Time, error) {
-	// Try different date formats
-	formats := []string{
-		time.RFC3339,
-		"2006-01-02T15:04:05Z",
-		"2006-01-02",
-		"2006-01-02 15:04:05",
-		"02-Jan-2006",
-		"2006/01/02",
+// whoisTimelineEvent parses a WHOIS date for the timeline, recording a warning
+// in the summary when it can't be read
+func whoisTimelineEvent(summary *Summary, label, value string, hint dateHint) *TimelineEvent {
+	date, approximate, err := parseWhoisDateHint(value, hint)
+	if err != nil {
+		summary.Warnings = append(summary.Warnings, fmt.Sprintf("Could not parse %s date %q", label, value))
+		return nil
 	}
-
-	for _, format := range formats {
-		if date, err := time.Parse(format, dateStr); err == nil {
-			return date, nil
-		}
+	return &TimelineEvent{
+		Date:          date,
+		HumanReadable: HumanReadableTime(date),
+		Approximate:   approximate,
 	}
-
-	return time.Time{}, fmt.Errorf("unable to parse date: %s", dateStr)
 }
 
 // HumanReadableTime converts a time to a human-readable relative format
@@ -303,122 +410,3 @@ func HumanReadableTime(t time.Time) string {
 		}
 	}
 }
-
-// parseASNInfo extracts ASN information from WHOIS data
-func parseASNInfo(result query.QueryResult) *ASNInfo {
-	if result.RawData == "" {
-		return nil
-	}
-
-	asn := &ASNInfo{
-		Number: result.Query,
-	}
-
-	lines := strings.Split(result.RawData, "\n")
-	var currentSection string
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
-
-		// Handle abuse contact extraction from comment lines
-		if strings.HasPrefix(line, "%") {
-			if strings.Contains(strings.ToLower(line), "abuse contact for") && strings.Contains(line, "@") {
-				// Extract email from "% Abuse contact for 'AS9009' is 'abuse@m247.ro'"
-				start := strings.Index(line, "'")
-				if start != -1 {
-					end := strings.LastIndex(line, "'")
-					if end > start {
-						email := line[start+1 : end]
-						if strings.Contains(email, "@") {
-							asn.AbuseContact = email
-						}
-					}
-				}
-			}
-			continue
-		}
-
-		// Parse key-value pairs
-		if strings.Contains(line, ":") {
-			parts := strings.SplitN(line, ":", 2)
-			if len(parts) == 2 {
-				key := strings.TrimSpace(parts[0])
-				value := strings.TrimSpace(parts[1])
-
-				if value == "" {
-					continue
-				}
-
-				switch strings.ToLower(key) {
-				case "aut-num", "asnumber":
-					asn.Number = value
-				case "as-name", "asname":
-					asn.Name = value
-				case "descr", "description":
-					if asn.Description == "" {
-						asn.Description = value
-					}
-				case "country":
-					asn.Country = value
-				case "org-name", "orgname":
-					asn.Organization = value
-				case "status":
-					asn.Status = value
-				case "abuse-mailbox", "orgabuseemail", "orgabuse-email":
-					asn.AbuseContact = value
-				case "organisation", "organization": //nolint:misspell // Both spellings are valid (RIPE uses 's', ARIN uses 'z')
-					currentSection = "org"
-				case "role":
-					currentSection = "role"
-				}
-
-				// Handle organization section
-				if currentSection == "org" && strings.ToLower(key) == "org-name" {
-					asn.Organization = value
-				}
-
-				// Parse import/export statements to extract peers
-				if strings.HasPrefix(strings.ToLower(key), "import") || strings.HasPrefix(strings.ToLower(key), "export") {
-					if strings.Contains(value, "AS") {
-						// Extract AS numbers from import/export lines
-						words := strings.Fields(value)
-						for _, word := range words {
-							if strings.HasPrefix(word, "AS") && len(word) > 2 {
-								// Clean up AS number (remove trailing punctuation)
-								asNum := strings.TrimRight(word, ",;:")
-								if len(asNum) > 2 {
-									// Avoid duplicates
-									found := false
-									for _, existing := range asn.Peers {
-										if existing == asNum {
-											found = true
-											break
-										}
-									}
-									if !found && asNum != asn.Number {
-										asn.Peers = append(asn.Peers, asNum)
-									}
-								}
-							}
-						}
-					}
-				}
-			}
-		}
-	}
-
-	// If no organization name was found, use the first description
-	if asn.Organization == "" && asn.Description != "" {
-		asn.Organization = asn.Description
-	}
-
-	// Set status to active if we have substantial data but no explicit status
-	if asn.Status == "" && (asn.Name != "" || asn.Organization != "") {
-		asn.Status = "active"
-	}
-
-	return asn
-}
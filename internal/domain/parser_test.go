@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -115,6 +117,96 @@ func TestParseWhoisDate(t *testing.T) {
 			wantErr:  false,
 			expected: "2023-08-14T00:00:00Z",
 		},
+		{
+			name:     "Fractional seconds",
+			input:    "2026-08-13T04:00:00.0Z",
+			expected: "2026-08-13T04:00:00Z",
+		},
+		{
+			name:     "Fractional seconds without T",
+			input:    "2026-08-13 04:00:00.123456",
+			expected: "2026-08-13T04:00:00Z",
+		},
+		{
+			name:     "Numeric offset without colon",
+			input:    "2026-08-13T06:00:00+0200",
+			expected: "2026-08-13T04:00:00Z",
+		},
+		{
+			name:     "Separate numeric offset",
+			input:    "2026-08-13 01:00:00 -0300",
+			expected: "2026-08-13T04:00:00Z",
+		},
+		{
+			name:     "Named zone",
+			input:    "2026-08-13 04:00:00 UTC",
+			expected: "2026-08-13T04:00:00Z",
+		},
+		{
+			name:     "Parenthesized named zone",
+			input:    "2026/08/13 13:00:00 (JST)",
+			expected: "2026-08-13T04:00:00Z",
+		},
+		{
+			name:     "Named zone with offset",
+			input:    "2026-08-13 06:00:00 CEST",
+			expected: "2026-08-13T04:00:00Z",
+		},
+		{
+			name:     "RFC 1123",
+			input:    "Thu, 13 Aug 2026 04:00:00 GMT",
+			expected: "2026-08-13T04:00:00Z",
+		},
+		{
+			name:     "date(1) output",
+			input:    "Thu Aug 13 04:00:00 GMT 2026",
+			expected: "2026-08-13T04:00:00Z",
+		},
+		{
+			name:     "Hour-only offset after a space",
+			input:    "2026-08-13 13:00:00+09",
+			expected: "2026-08-13T04:00:00Z",
+		},
+		{
+			name:     "CST as US Central by default",
+			input:    "2026-08-12 22:00:00 CST",
+			expected: "2026-08-13T04:00:00Z",
+		},
+		{
+			name:     "Day-first dotted",
+			input:    "13.08.2026",
+			expected: "2026-08-13T00:00:00Z",
+		},
+		{
+			name:     "Korean style",
+			input:    "2026. 08. 13.",
+			expected: "2026-08-13T00:00:00Z",
+		},
+		{
+			name:     "Lower-case month",
+			input:    "13-aug-2026",
+			expected: "2026-08-13T00:00:00Z",
+		},
+		{
+			name:     "Compact",
+			input:    "20260813",
+			expected: "2026-08-13T00:00:00Z",
+		},
+		{
+			name:     "Month-first slashes by default",
+			input:    "08/03/2026",
+			expected: "2026-08-03T00:00:00Z",
+		},
+		{
+			name:     "Unambiguous day-first slashes",
+			input:    "13/08/2026",
+			expected: "2026-08-13T00:00:00Z",
+		},
+		{
+			name:     "Before month",
+			input:    "before Aug-1996",
+			expected: "1996-08-01T00:00:00Z",
+		},
 		{
 			name:    "Invalid format",
 			input:   "not a date",
@@ -151,6 +243,71 @@ func TestParseWhoisDate(t *testing.T) {
 	}
 }
 
+func TestParseWhoisDateHint(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		domain      string
+		expected    string
+		approximate bool
+	}{
+		{"day-first registry", "08/03/2026", "example.de", "2026-03-08T00:00:00Z", false},
+		{"month-first default", "08/03/2026", "example.com", "2026-08-03T00:00:00Z", false},
+		{"registry zone", "2026/08/13 13:00:00", "example.jp", "2026-08-13T04:00:00Z", false},
+		{"explicit zone beats registry zone", "2026-08-13T04:00:00Z", "example.jp", "2026-08-13T04:00:00Z", false},
+		{"approximate", "before Aug-1996", "example.co.uk", "1996-08-01T00:00:00Z", true},
+		{"CNNIC China Standard Time", "2026-08-13 12:00:00 CST", "example.cn", "2026-08-13T04:00:00Z", false},
+		{"CNNIC without a zone", "2026-08-13 12:00:00", "example.com.cn", "2026-08-13T04:00:00Z", false},
+		{"date(1) output with registry zone name", "Thu Aug 13 12:00:00 CST 2026", "example.tw", "2026-08-13T04:00:00Z", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			date, approximate, err := parseWhoisDateHint(tt.input, dateHintForDomain(tt.domain))
+			if err != nil {
+				t.Fatalf("parseWhoisDateHint(%q) unexpected error: %v", tt.input, err)
+			}
+			if got := date.UTC().Format(time.RFC3339); got != tt.expected {
+				t.Errorf("parseWhoisDateHint(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+			if approximate != tt.approximate {
+				t.Errorf("parseWhoisDateHint(%q) approximate = %v, want %v", tt.input, approximate, tt.approximate)
+			}
+		})
+	}
+}
+
+func TestCreateSummary_DateWarnings(t *testing.T) {
+	mockResult := query.QueryResult{
+		Query:     "example.com",
+		Type:      string(query.QueryTypeDomain),
+		Protocol:  "WHOIS",
+		Timestamp: time.Now(),
+		Success:   true,
+		Data: map[string]interface{}{
+			"parsed_fields": map[string]interface{}{
+				"domain_name":          "EXAMPLE.COM",
+				"creation_date":        "2020-01-01T00:00:00.0Z",
+				"registry_expiry_date": "sometime soon",
+				"domain_status":        "ok",
+			},
+			"raw_response": "Mock WHOIS response data",
+		},
+	}
+
+	summary := CreateSummary(mockResult)
+
+	if summary.Timeline.Registration == nil {
+		t.Error("Expected Registration with fractional seconds to be parsed")
+	}
+	if summary.Timeline.Expiration != nil {
+		t.Error("Expected unparseable Expiration to be left unset")
+	}
+	if len(summary.Warnings) != 1 || !strings.Contains(summary.Warnings[0], "sometime soon") {
+		t.Errorf("Expected a warning for the unparseable expiration date, got %v", summary.Warnings)
+	}
+}
+
 func TestCreateSummary_Structure(t *testing.T) {
 	// Test the structure of CreateSummary with a mock QueryResult
 	testTime := time.Now()
@@ -227,6 +384,42 @@ func TestCreateSummary_Structure(t *testing.T) {
 	// Note: Guidance might be nil if expiration is far in future, which is OK
 }
 
+func TestCreateSummary_RegistryWhoisFields(t *testing.T) {
+	// Registry-specific WHOIS parsers report nameservers and statuses as lists
+	mockResult := query.QueryResult{
+		Query:     "example.de",
+		Type:      string(query.QueryTypeDomain),
+		Protocol:  "WHOIS",
+		Timestamp: time.Now(),
+		Success:   true,
+		Data: map[string]interface{}{
+			"parsed_fields": map[string]interface{}{
+				"domain_name":   "example.de",
+				"name_server":   []string{"a.iana-servers.net", "b.iana-servers.net"},
+				"domain_status": []string{"connect"},
+				"dnssec":        "signedDelegation",
+			},
+			"parser":       "denic",
+			"raw_response": "Domain: example.de\nStatus: connect",
+		},
+	}
+
+	summary := CreateSummary(mockResult)
+
+	if !reflect.DeepEqual(NameserverNames(summary.Nameservers), []string{"a.iana-servers.net", "b.iana-servers.net"}) {
+		t.Errorf("Nameservers = %v, want both DENIC nameservers", summary.Nameservers)
+	}
+	if !reflect.DeepEqual(summary.StatusDetails, []string{"connect"}) {
+		t.Errorf("StatusDetails = %v, want [connect]", summary.StatusDetails)
+	}
+	if summary.Status != "active" {
+		t.Errorf("Status = %q, want active", summary.Status)
+	}
+	if !summary.DNSSEC.Enabled {
+		t.Error("Expected DNSSEC to be enabled for 'signedDelegation'")
+	}
+}
+
 func TestCreateSummary_RDAP(t *testing.T) {
 	// Test basic RDAP structure handling
 	testTime := time.Now()
@@ -271,3 +464,56 @@ func TestCreateSummary_RDAP(t *testing.T) {
 		t.Error("Expected nameservers to be populated from RDAP")
 	}
 }
+
+func TestCreateSummary_RDAPEvents(t *testing.T) {
+	mockRDAPData := map[string]interface{}{
+		"objectClassName": "domain",
+		"ldhName":         "example.com",
+		"Events": []interface{}{
+			map[string]interface{}{"Action": "last update of RDAP database", "Date": "2026-10-18T09:00:00Z"},
+			map[string]interface{}{"Action": "expiration", "Date": "2027-08-13T04:00:00Z"},
+			map[string]interface{}{"Action": "transfer", "Date": "2019-03-01T00:00:00Z", "Actor": "Old Registrar"},
+			map[string]interface{}{"Action": "transfer", "Date": "2024-05-20T00:00:00Z", "Actor": "New Registrar"},
+			map[string]interface{}{"Action": "registration", "Date": "1995-08-14T04:00:00Z"},
+			map[string]interface{}{"Action": "reregistration", "Date": "2010-02-01T00:00:00Z"},
+			map[string]interface{}{"Action": "locked", "Date": "2024-05-21T00:00:00Z"},
+			map[string]interface{}{"Action": "unlocked", "Date": "2024-05-01T00:00:00Z"},
+			map[string]interface{}{"Action": "enum validation expiration", "Date": "2027-01-01T00:00:00Z"},
+		},
+	}
+
+	summary := CreateSummary(query.QueryResult{
+		Query:     "example.com",
+		Type:      string(query.QueryTypeDomain),
+		Protocol:  "RDAP",
+		Timestamp: time.Now(),
+		Success:   true,
+		Data:      mockRDAPData,
+	})
+
+	timeline := summary.Timeline
+	if timeline.LastUpdated != nil {
+		t.Errorf("Expected the RDAP database update to be kept out of LastUpdated, got %v", timeline.LastUpdated.Date)
+	}
+	if timeline.DatabaseUpdated == nil || timeline.DatabaseUpdated.Date.Hour() != 9 {
+		t.Errorf("DatabaseUpdated = %+v, want 2026-10-18T09:00:00Z", timeline.DatabaseUpdated)
+	}
+	if timeline.Transfer == nil || timeline.Transfer.Actor != "New Registrar" {
+		t.Errorf("Transfer = %+v, want the most recent transfer", timeline.Transfer)
+	}
+	if timeline.Reregistration == nil || timeline.Locked == nil || timeline.Unlocked == nil {
+		t.Errorf("Expected reregistration, locked and unlocked events, got %+v", timeline)
+	}
+
+	if len(timeline.Events) != 9 {
+		t.Fatalf("len(Events) = %d, want 9", len(timeline.Events))
+	}
+	for i := 1; i < len(timeline.Events); i++ {
+		if timeline.Events[i].Date.Before(timeline.Events[i-1].Date) {
+			t.Errorf("Events not in date order: %s before %s", timeline.Events[i-1].Action, timeline.Events[i].Action)
+		}
+	}
+	if timeline.Events[0].Action != "registration" {
+		t.Errorf("Events[0].Action = %q, want registration", timeline.Events[0].Action)
+	}
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+
+	"regard/internal/iana"
+)
+
+// LookupRegistrars finds registrars in the bundled IANA registry by IANA ID or
+// by a case-insensitive name fragment
+func LookupRegistrars(term string) []RegistrarInfo {
+	var results []RegistrarInfo
+
+	if registrar, ok := iana.LookupRegistrar(term); ok {
+		return append(results, registrarInfoFromIANA(registrar))
+	}
+
+	for _, registrar := range iana.SearchRegistrars(term) {
+		results = append(results, registrarInfoFromIANA(registrar))
+	}
+	return results
+}
+
+func registrarInfoFromIANA(registrar iana.Registrar) RegistrarInfo {
+	return RegistrarInfo{
+		Name:          registrar.Name,
+		ID:            registrar.ID,
+		IANAID:        registrar.ID,
+		CanonicalName: registrar.Name,
+		Status:        strings.ToLower(registrar.Status),
+		RDAPBaseURL:   registrar.RDAPBaseURL,
+	}
+}
+
+// enrichRegistrar expands the registrar's IANA ID into the registry details and
+// adds the abuse contact published by the registry response
+func enrichRegistrar(summary *Summary) {
+	if summary.Registrar.IANAID == "" && isNumeric(summary.Registrar.ID) {
+		summary.Registrar.IANAID = summary.Registrar.ID
+	}
+
+	if summary.Registrar.IANAID != "" {
+		if registrar, ok := iana.LookupRegistrar(summary.Registrar.IANAID); ok {
+			summary.Registrar.CanonicalName = registrar.Name
+			summary.Registrar.Status = strings.ToLower(registrar.Status)
+			summary.Registrar.RDAPBaseURL = registrar.RDAPBaseURL
+		}
+	}
+
+	// The IANA registry has no abuse contacts; they come from the registrar
+	// entity in the registry response
+	for _, contact := range summary.Contacts {
+		if contact.Role != "abuse" {
+			continue
+		}
+		if contact.Email != "" {
+			summary.Registrar.AbuseEmail = contact.Email
+		}
+		if contact.Phone != "" {
+			summary.Registrar.AbusePhone = contact.Phone
+		}
+		break
+	}
+}
+
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	_, err := strconv.ParseUint(s, 10, 64)
+	return err == nil
+}
@@ -0,0 +1,58 @@
+package domain
+
+import "testing"
+
+func TestEnrichRegistrar(t *testing.T) {
+	summary := Summary{
+		Registrar: RegistrarInfo{Name: "MarkMonitor, Inc.", ID: "292"},
+	}
+
+	enrichRegistrar(&summary)
+
+	if summary.Registrar.IANAID != "292" {
+		t.Errorf("Expected IANAID = 292, got %q", summary.Registrar.IANAID)
+	}
+	if summary.Registrar.CanonicalName != "MarkMonitor Inc." {
+		t.Errorf("Expected canonical name from IANA registry, got %q", summary.Registrar.CanonicalName)
+	}
+	if summary.Registrar.Status != "accredited" {
+		t.Errorf("Expected status accredited, got %q", summary.Registrar.Status)
+	}
+	if summary.Registrar.RDAPBaseURL == "" {
+		t.Error("Expected RDAP base URL to be set")
+	}
+}
+
+func TestEnrichRegistrar_RegistryAbuseContact(t *testing.T) {
+	summary := Summary{
+		Registrar: RegistrarInfo{Name: "Example Registrar", ID: "EXAMPLE-REG"},
+		Contacts: []Contact{
+			{Role: "abuse", Email: "abuse@registrar.example", Phone: "+1.5555551234"},
+		},
+	}
+
+	enrichRegistrar(&summary)
+
+	if summary.Registrar.IANAID != "" {
+		t.Errorf("Expected no IANA ID for non-numeric handle, got %q", summary.Registrar.IANAID)
+	}
+	if summary.Registrar.AbuseEmail != "abuse@registrar.example" || summary.Registrar.AbusePhone != "+1.5555551234" {
+		t.Errorf("Expected abuse contact from registry response, got %+v", summary.Registrar)
+	}
+}
+
+func TestLookupRegistrars(t *testing.T) {
+	results := LookupRegistrars("146")
+	if len(results) != 1 || results[0].IANAID != "146" {
+		t.Errorf("Expected exact ID match for 146, got %+v", results)
+	}
+
+	results = LookupRegistrars("reserved for")
+	if len(results) < 2 {
+		t.Errorf("Expected several reserved registrar IDs, got %d", len(results))
+	}
+
+	if results := LookupRegistrars("no such registrar"); len(results) != 0 {
+		t.Errorf("Expected no results, got %+v", results)
+	}
+}
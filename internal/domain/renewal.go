@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// autoRenewStatuses are the RDAP and EPP spellings of the auto-renew grace period
+var autoRenewStatuses = []string{"autorenewperiod", "auto renew period"}
+
+// EffectiveExpiration returns the date the domain lapses unless renewed. When
+// the registry and registrar disagree, the earlier date applies: a registry
+// that auto-renews moves its date on a year, while the registrar still
+// deletes the domain at its own date if the registrant doesn't pay.
+func EffectiveExpiration(timeline Timeline) *TimelineEvent {
+	registry, registrar := timeline.Expiration, timeline.RegistrarExpiration
+	switch {
+	case registry == nil:
+		return registrar
+	case registrar == nil:
+		return registry
+	case registrar.Date.Before(registry.Date):
+		return registrar
+	default:
+		return registry
+	}
+}
+
+// detectRenewal compares the registry and registrar expiration dates and the
+// domain status, recording auto-renewal and warning when the dates diverge
+func detectRenewal(summary *Summary) {
+	renewal := &RenewalInfo{}
+
+	for _, status := range summary.StatusDetails {
+		lower := strings.ToLower(status)
+		for _, autoRenew := range autoRenewStatuses {
+			if lower == autoRenew {
+				renewal.AutoRenew = true
+				renewal.Reason = "Domain is in the auto-renew grace period"
+			}
+		}
+	}
+
+	registry, registrar := summary.Timeline.Expiration, summary.Timeline.RegistrarExpiration
+	if registry != nil && registrar != nil {
+		days := int(math.Round(registry.Date.Sub(registrar.Date).Hours() / 24))
+		// Allow for the two dates being recorded in different timezones
+		if days > 1 || days < -1 {
+			renewal.DivergenceDays = days
+			summary.Warnings = append(summary.Warnings, fmt.Sprintf(
+				"Registry and registrar expiration dates differ by %d days (registry %s, registrar %s)",
+				absInt(days), registry.Date.Format("2006-01-02"), registrar.Date.Format("2006-01-02")))
+
+			// A registry date about a year ahead means the registry auto-renewed
+			if !renewal.AutoRenew && days >= 330 && registrar.Date.Before(time.Now().AddDate(0, 0, 1)) {
+				renewal.AutoRenew = true
+				renewal.Reason = "Registry has auto-renewed the domain; it lapses at the registrar expiration date unless the registrant renews"
+			}
+		}
+	}
+
+	if renewal.AutoRenew || renewal.DivergenceDays != 0 {
+		summary.Renewal = renewal
+	}
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
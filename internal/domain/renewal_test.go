@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"regard/internal/query"
+)
+
+func TestEffectiveExpiration(t *testing.T) {
+	early := &TimelineEvent{Date: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}
+	late := &TimelineEvent{Date: time.Date(2027, 3, 1, 0, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		name     string
+		timeline Timeline
+		expected *TimelineEvent
+	}{
+		{"No dates", Timeline{}, nil},
+		{"Registry only", Timeline{Expiration: late}, late},
+		{"Registrar only", Timeline{RegistrarExpiration: early}, early},
+		{"Registrar earlier", Timeline{Expiration: late, RegistrarExpiration: early}, early},
+		{"Registry earlier", Timeline{Expiration: early, RegistrarExpiration: late}, early},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := EffectiveExpiration(tt.timeline)
+			if result != tt.expected {
+				t.Errorf("EffectiveExpiration() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCreateSummary_RegistrarExpiration(t *testing.T) {
+	now := time.Now().UTC()
+	registrarExpiry := now.AddDate(0, 0, -5)
+	registryExpiry := registrarExpiry.AddDate(1, 0, 0)
+
+	tests := []struct {
+		name            string
+		registry        time.Time
+		registrar       time.Time
+		status          string
+		expectAutoRenew bool
+		expectWarning   bool
+	}{
+		{
+			name:            "Registry auto-renewed after registrar expiry",
+			registry:        registryExpiry,
+			registrar:       registrarExpiry,
+			status:          "clientTransferProhibited",
+			expectAutoRenew: true,
+			expectWarning:   true,
+		},
+		{
+			name:            "Auto-renew period status",
+			registry:        registryExpiry,
+			registrar:       registrarExpiry,
+			status:          "autoRenewPeriod",
+			expectAutoRenew: true,
+			expectWarning:   true,
+		},
+		{
+			name:      "Matching dates in different timezones",
+			registry:  registryExpiry,
+			registrar: registryExpiry.Add(-20 * time.Hour),
+			status:    "ok",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockResult := query.QueryResult{
+				Query:     "example.com",
+				Type:      string(query.QueryTypeDomain),
+				Protocol:  "WHOIS",
+				Timestamp: now,
+				Success:   true,
+				Data: map[string]interface{}{
+					"parsed_fields": map[string]interface{}{
+						"domain_name":                            "EXAMPLE.COM",
+						"registry_expiry_date":                   tt.registry.Format(time.RFC3339),
+						"registrar_registration_expiration_date": tt.registrar.Format(time.RFC3339),
+						"domain_status":                          tt.status,
+					},
+					"raw_response": "Mock WHOIS response data",
+				},
+			}
+
+			summary := CreateSummary(mockResult)
+
+			if summary.Timeline.RegistrarExpiration == nil {
+				t.Fatal("Expected RegistrarExpiration to be parsed")
+			}
+			autoRenew := summary.Renewal != nil && summary.Renewal.AutoRenew
+			if autoRenew != tt.expectAutoRenew {
+				t.Errorf("AutoRenew = %v, want %v (renewal %+v)", autoRenew, tt.expectAutoRenew, summary.Renewal)
+			}
+			hasWarning := false
+			for _, warning := range summary.Warnings {
+				if strings.Contains(warning, "differ by") {
+					hasWarning = true
+				}
+			}
+			if hasWarning != tt.expectWarning {
+				t.Errorf("divergence warning = %v, want %v (warnings %v)", hasWarning, tt.expectWarning, summary.Warnings)
+			}
+			if tt.expectAutoRenew {
+				if summary.PostExpiration == nil {
+					t.Fatal("Expected guidance based on the registrar expiration date")
+				}
+				if summary.PostExpiration.DaysExpired != 5 {
+					t.Errorf("DaysExpired = %d, want 5", summary.PostExpiration.DaysExpired)
+				}
+			}
+		})
+	}
+}
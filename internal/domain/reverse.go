@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"regard/internal/query"
+)
+
+// ReverseZone describes the in-addr.arpa or ip6.arpa zone a reverse DNS query
+// named and the addresses it covers
+type ReverseZone struct {
+	Zone    string `json:"zone"`
+	Prefix  string `json:"prefix"`            // Address block the zone covers
+	Network string `json:"network,omitempty"` // Registered network named by the RDAP object
+}
+
+// ReverseDNS is the live reverse DNS of the queried address or block
+type ReverseDNS struct {
+	Name        string   `json:"name"`                  // PTR owner name, or the reverse zone of a block
+	PTR         []string `json:"ptr,omitempty"`         // Host names the address points to
+	Nameservers []string `json:"nameservers,omitempty"` // Delegation of a block's reverse zone
+	Resolver    string   `json:"resolver"`
+	Error       string   `json:"error,omitempty"`
+}
+
+// ReverseResolver answers the live lookups reverse DNS needs, as query.DNSClient does
+type ReverseResolver interface {
+	LookupPTR(name string) ([]string, error)
+	LookupNS(name string) ([]string, error)
+	Server() string
+}
+
+// parseReverseZone maps a reverse zone to its address block. A zone is
+// delegated or not; registry domain statuses don't apply.
+func parseReverseZone(result query.QueryResult, summary *Summary) *ReverseZone {
+	prefix, ok := query.ParseReverseName(result.Query)
+	if !ok {
+		return nil
+	}
+	zone := &ReverseZone{Zone: result.Query, Prefix: prefix.String()}
+
+	if result.Protocol == "RDAP" {
+		var object struct {
+			LDHName string
+			Network *struct {
+				Handle       string
+				Name         string
+				StartAddress string
+				EndAddress   string
+			}
+		}
+		if jsonBytes, err := json.Marshal(result.Data); err == nil {
+			_ = json.Unmarshal(jsonBytes, &object)
+		}
+		// The answer may be for an enclosing zone when the name itself isn't registered
+		if object.LDHName != "" {
+			zone.Zone = object.LDHName
+			if enclosing, ok := query.ParseReverseName(object.LDHName); ok {
+				zone.Prefix = enclosing.String()
+			}
+		}
+		if network := object.Network; network != nil {
+			zone.Network = firstNonEmpty(network.Name, network.Handle)
+			if network.StartAddress != "" {
+				zone.Network += fmt.Sprintf(" (%s - %s)", network.StartAddress, network.EndAddress)
+			}
+		}
+	}
+
+	summary.Status = "not delegated"
+	if len(summary.Nameservers) > 0 {
+		summary.Status = "delegated"
+	}
+	return zone
+}
+
+// AddReverseDNS resolves the PTR records of a queried address, or the
+// nameservers of a queried block's reverse zone
+func AddReverseDNS(summary *IPSummary, resolver ReverseResolver) {
+	prefix, ok := queryPrefix(summary.Query)
+	if !ok {
+		return
+	}
+	name, ok := query.ReverseName(prefix)
+	if !ok {
+		summary.Warnings = append(summary.Warnings, fmt.Sprintf("No single reverse zone covers %s; reverse zones are delegated on octet (IPv4) or nibble (IPv6) boundaries", summary.Query))
+		return
+	}
+
+	reverse := &ReverseDNS{Name: name, Resolver: resolver.Server()}
+	var err error
+	if prefix.Bits() == prefix.Addr().BitLen() {
+		reverse.PTR, err = resolver.LookupPTR(name)
+	} else {
+		reverse.Nameservers, err = resolver.LookupNS(name)
+	}
+	if err != nil {
+		reverse.Error = err.Error()
+	}
+	summary.ReverseDNS = reverse
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/openrdap/rdap"
+
+	"regard/internal/query"
+)
+
+func TestCreateSummary_ReverseZone(t *testing.T) {
+	result := query.QueryResult{
+		Query:    "2.0.192.in-addr.arpa",
+		Type:     string(query.QueryTypeReverse),
+		Protocol: "RDAP",
+		Success:  true,
+		Data: &rdap.Domain{
+			LDHName:     "2.0.192.in-addr.arpa",
+			Nameservers: []rdap.Nameserver{{LDHName: "ns1.example.net"}, {LDHName: "ns2.example.net"}},
+			Network:     &rdap.IPNetwork{Handle: "NET-192-0-2-0-1", Name: "TEST-NET-1", StartAddress: "192.0.2.0", EndAddress: "192.0.2.255"},
+		},
+	}
+
+	summary := CreateSummary(result)
+	if summary.ReverseZone == nil {
+		t.Fatal("ReverseZone not set")
+	}
+	if summary.ReverseZone.Prefix != "192.0.2.0/24" || summary.ReverseZone.Network != "TEST-NET-1 (192.0.2.0 - 192.0.2.255)" {
+		t.Errorf("ReverseZone = %+v", summary.ReverseZone)
+	}
+	if summary.Status != "delegated" || len(summary.Nameservers) != 2 {
+		t.Errorf("Status = %q with %d nameservers, want delegated with 2", summary.Status, len(summary.Nameservers))
+	}
+
+	result.Data = &rdap.Domain{LDHName: "2.0.192.in-addr.arpa"}
+	if summary := CreateSummary(result); summary.Status != "not delegated" {
+		t.Errorf("Status without nameservers = %q, want not delegated", summary.Status)
+	}
+
+	// An address answered with its enclosing /24 describes the /24
+	result.Query = "1.2.0.192.in-addr.arpa"
+	result.Data = &rdap.Domain{LDHName: "2.0.192.in-addr.arpa", Nameservers: []rdap.Nameserver{{LDHName: "ns1.example.net"}}}
+	if zone := CreateSummary(result).ReverseZone; zone == nil || zone.Zone != "2.0.192.in-addr.arpa" || zone.Prefix != "192.0.2.0/24" {
+		t.Errorf("ReverseZone for the enclosing zone = %+v", zone)
+	}
+}
+
+func TestAddReverseDNS(t *testing.T) {
+	resolver := fakeResolver{
+		ns:  []string{"ns1.example.net", "ns2.example.net"},
+		ptr: map[string][]string{"8.8.8.8.in-addr.arpa": {"dns.google"}},
+	}
+
+	tests := []struct {
+		query             string
+		expectName        string
+		expectPTR         int
+		expectNameservers int
+		expectError       bool
+	}{
+		{"8.8.8.8", "8.8.8.8.in-addr.arpa", 1, 0, false},
+		{"192.0.2.0/24", "2.0.192.in-addr.arpa", 0, 2, false},
+		{"2001:db8::/32", "8.b.d.0.1.0.0.2.ip6.arpa", 0, 2, false},
+		{"192.0.2.1", "1.2.0.192.in-addr.arpa", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			summary := IPSummary{Query: tt.query}
+			AddReverseDNS(&summary, resolver)
+			reverse := summary.ReverseDNS
+			if reverse == nil {
+				t.Fatalf("AddReverseDNS(%s) set nothing", tt.query)
+			}
+			if reverse.Name != tt.expectName || len(reverse.PTR) != tt.expectPTR || len(reverse.Nameservers) != tt.expectNameservers || (reverse.Error != "") != tt.expectError {
+				t.Errorf("AddReverseDNS(%s) = %+v", tt.query, reverse)
+			}
+		})
+	}
+
+	summary := IPSummary{Query: "192.0.0.0/22"}
+	AddReverseDNS(&summary, resolver)
+	if summary.ReverseDNS != nil || len(summary.Warnings) != 1 {
+		t.Errorf("AddReverseDNS(192.0.0.0/22) = %+v, warnings %v; want a warning only", summary.ReverseDNS, summary.Warnings)
+	}
+}
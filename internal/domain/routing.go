@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"fmt"
+
+	"regard/internal/routing"
+)
+
+// AddRoute fills in the most specific announced prefix covering the queried
+// address or prefix, and the ASes originating it
+func AddRoute(summary *IPSummary, table *routing.Table) {
+	prefix, ok := queryPrefix(summary.Query)
+	if !ok {
+		return
+	}
+
+	route, ok := table.LookupPrefix(prefix)
+	if !ok {
+		summary.Warnings = append(summary.Warnings, fmt.Sprintf("No route covers %s in %s", summary.Query, table.Source))
+		return
+	}
+	summary.Routing = &route
+}
+
+// AddOriginatedPrefixes lists the prefixes the queried AS originates in the routing table
+func AddOriginatedPrefixes(summary *Summary, table *routing.Table) {
+	if summary.ASN == nil {
+		return
+	}
+	asn, ok := parseASNumber(summary.ASN.Number)
+	if !ok {
+		return
+	}
+
+	summary.ASN.Prefixes = nil
+	for _, prefix := range table.Originated(asn) {
+		summary.ASN.Prefixes = append(summary.ASN.Prefixes, prefix.String())
+	}
+	summary.ASN.RoutingTable = table.Source
+}
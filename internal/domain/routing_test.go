@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"regard/internal/routing"
+)
+
+func testRoutingTable(t *testing.T) *routing.Table {
+	t.Helper()
+	table, err := routing.Read(strings.NewReader("8.0.0.0\t9\t3356\n8.8.8.0\t24\t15169\n8.8.4.0\t24\t15169\n2001:4860::\t32\t15169\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	table.Source = "test.pfx2as"
+	return table
+}
+
+func TestAddRoute(t *testing.T) {
+	table := testRoutingTable(t)
+
+	tests := []struct {
+		query         string
+		expectPrefix  string
+		expectOrigins []uint32
+	}{
+		{"8.8.8.8", "8.8.8.0/24", []uint32{15169}},
+		{"8.8.0.0/16", "8.0.0.0/9", []uint32{3356}},
+		{"2001:4860:4860::8888", "2001:4860::/32", []uint32{15169}},
+		{"9.9.9.9", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			summary := IPSummary{Query: tt.query}
+			AddRoute(&summary, table)
+			if tt.expectPrefix == "" {
+				if summary.Routing != nil || len(summary.Warnings) != 1 {
+					t.Errorf("AddRoute(%s) = %+v, warnings %v; want no route and a warning", tt.query, summary.Routing, summary.Warnings)
+				}
+				return
+			}
+			if summary.Routing == nil {
+				t.Fatalf("AddRoute(%s) found no route", tt.query)
+			}
+			if summary.Routing.Prefix.String() != tt.expectPrefix || !reflect.DeepEqual(summary.Routing.Origins, tt.expectOrigins) {
+				t.Errorf("AddRoute(%s) = %s %v, want %s %v", tt.query, summary.Routing.Prefix, summary.Routing.Origins, tt.expectPrefix, tt.expectOrigins)
+			}
+		})
+	}
+}
+
+func TestAddOriginatedPrefixes(t *testing.T) {
+	summary := Summary{ASN: &ASNInfo{Number: "AS15169"}}
+	AddOriginatedPrefixes(&summary, testRoutingTable(t))
+
+	expected := []string{"8.8.4.0/24", "8.8.8.0/24", "2001:4860::/32"}
+	if !reflect.DeepEqual(summary.ASN.Prefixes, expected) {
+		t.Errorf("Prefixes = %v, want %v", summary.ASN.Prefixes, expected)
+	}
+	if summary.ASN.RoutingTable != "test.pfx2as" {
+		t.Errorf("RoutingTable = %q, want test.pfx2as", summary.ASN.RoutingTable)
+	}
+}
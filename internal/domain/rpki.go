@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"net/netip"
+
+	"regard/internal/rpki"
+)
+
+// RouteValidity holds the RPKI route origin validation of announced routes
+type RouteValidity struct {
+	Source string            `json:"source"` // The VRP export validated against
+	Routes []rpki.Validation `json:"routes"`
+}
+
+// Count returns how many routes are in the given state
+func (v *RouteValidity) Count(state rpki.State) int {
+	count := 0
+	for _, route := range v.Routes {
+		if route.State == state {
+			count++
+		}
+	}
+	return count
+}
+
+// AddRouteValidity validates the announced prefix against each of its origin
+// ASes. It needs the route from AddRoute.
+func AddRouteValidity(summary *IPSummary, vrps *rpki.Set) {
+	if summary.Routing == nil {
+		return
+	}
+
+	validity := &RouteValidity{Source: vrps.Source}
+	for _, origin := range summary.Routing.Origins {
+		validity.Routes = append(validity.Routes, vrps.Validate(summary.Routing.Prefix, origin))
+	}
+	summary.RPKI = validity
+}
+
+// AddOriginatedValidity validates each prefix the queried AS originates, as
+// listed by AddOriginatedPrefixes
+func AddOriginatedValidity(summary *Summary, vrps *rpki.Set) {
+	if summary.ASN == nil || summary.ASN.RoutingTable == "" {
+		return
+	}
+	asn, ok := parseASNumber(summary.ASN.Number)
+	if !ok {
+		return
+	}
+
+	validity := &RouteValidity{Source: vrps.Source, Routes: []rpki.Validation{}}
+	for _, text := range summary.ASN.Prefixes {
+		prefix, err := netip.ParsePrefix(text)
+		if err != nil {
+			continue
+		}
+		validity.Routes = append(validity.Routes, vrps.Validate(prefix, asn))
+	}
+	summary.ASN.RPKI = validity
+}
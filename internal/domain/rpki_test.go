@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"regard/internal/rpki"
+)
+
+func testVRPs(t *testing.T) *rpki.Set {
+	t.Helper()
+	vrps, err := rpki.Read(strings.NewReader(`{"roas": [
+		{"asn": "AS15169", "prefix": "8.8.8.0/24", "maxLength": 24, "ta": "arin"},
+		{"asn": "AS15169", "prefix": "2001:4860::/32", "maxLength": 48, "ta": "arin"},
+		{"asn": "AS64500", "prefix": "8.8.4.0/24", "maxLength": 24, "ta": "arin"}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	vrps.Source = "vrps.json"
+	return vrps
+}
+
+func TestAddRouteValidity(t *testing.T) {
+	table, vrps := testRoutingTable(t), testVRPs(t)
+
+	tests := []struct {
+		query       string
+		expectState rpki.State
+	}{
+		{"8.8.8.8", rpki.Valid},
+		{"8.8.4.4", rpki.Invalid},
+		{"8.8.0.0/16", rpki.NotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			summary := IPSummary{Query: tt.query}
+			AddRoute(&summary, table)
+			AddRouteValidity(&summary, vrps)
+			if summary.RPKI == nil || len(summary.RPKI.Routes) != 1 {
+				t.Fatalf("AddRouteValidity(%s) = %+v, want one validated route", tt.query, summary.RPKI)
+			}
+			if state := summary.RPKI.Routes[0].State; state != tt.expectState {
+				t.Errorf("AddRouteValidity(%s) state = %s, want %s", tt.query, state, tt.expectState)
+			}
+		})
+	}
+
+	summary := IPSummary{Query: "9.9.9.9"}
+	AddRouteValidity(&summary, vrps)
+	if summary.RPKI != nil {
+		t.Errorf("AddRouteValidity without a route = %+v, want nil", summary.RPKI)
+	}
+}
+
+func TestAddOriginatedValidity(t *testing.T) {
+	summary := Summary{ASN: &ASNInfo{Number: "AS15169"}}
+	AddOriginatedPrefixes(&summary, testRoutingTable(t))
+	AddOriginatedValidity(&summary, testVRPs(t))
+
+	validity := summary.ASN.RPKI
+	if validity == nil || len(validity.Routes) != 3 {
+		t.Fatalf("AddOriginatedValidity() = %+v, want three routes", validity)
+	}
+	if validity.Count(rpki.Valid) != 2 || validity.Count(rpki.Invalid) != 1 || validity.Count(rpki.NotFound) != 0 {
+		t.Errorf("AddOriginatedValidity() = %v", validity.Routes)
+	}
+	if validity.Routes[0].Prefix.String() != "8.8.4.0/24" || validity.Routes[0].Reason != rpki.ReasonOrigin {
+		t.Errorf("Routes[0] = %+v, want 8.8.4.0/24 invalid for its origin", validity.Routes[0])
+	}
+}
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"strings"
+
+	"regard/internal/rpsl"
+)
+
+// rpslContactRefs maps the attributes RIR objects use to reference contacts to contact roles
+var rpslContactRefs = []struct {
+	attr string
+	role string
+}{
+	{"org", "registrant"},
+	{"organization", "registrant"},
+	{"owner-c", "registrant"},
+	{"admin-c", "administrative"},
+	{"tech-c", "technical"},
+	{"abuse-c", "abuse"},
+	{"mnt-irt", "abuse"},
+}
+
+// rpslOrganization returns the organisation an object refers to with "org"
+// (RIPE, APNIC, AFRINIC) or "Organization" (ARIN)
+func rpslOrganization(response *rpsl.Response, object rpsl.Object) (rpsl.Object, bool) {
+	for _, attr := range []string{"org", "organization"} {
+		if orgs := response.Resolve(object, attr); len(orgs) > 0 {
+			return orgs[0], true
+		}
+	}
+	return rpsl.Object{}, false
+}
+
+// rpslAbuseContact finds the abuse address for an object: its abuse-c role or
+// IRT, its organisation's abuse-c, the RIR's abuse comment, or the abuse POC
+// ARIN lists after the organization
+func rpslAbuseContact(response *rpsl.Response, object rpsl.Object) string {
+	abuseEmail := func(o rpsl.Object) string {
+		for _, attr := range []string{"abuse-c", "mnt-irt"} {
+			for _, contact := range response.Resolve(o, attr) {
+				if email := contact.Get("abuse-mailbox", "e-mail"); email != "" {
+					return email
+				}
+			}
+		}
+		return ""
+	}
+
+	if email := abuseEmail(object); email != "" {
+		return email
+	}
+	if org, ok := rpslOrganization(response, object); ok {
+		if email := firstNonEmpty(abuseEmail(org), org.Get("abuse-mailbox")); email != "" {
+			return email
+		}
+	}
+	if email := response.AbuseComment(object); email != "" {
+		return email
+	}
+	for _, following := range response.Following(object) {
+		if email := following.Get("orgabuseemail"); email != "" {
+			return email
+		}
+	}
+	return object.Get("abuse-mailbox")
+}
+
+// rpslContacts resolves the contacts an object references into Contacts
+func rpslContacts(response *rpsl.Response, object rpsl.Object) []Contact {
+	var contacts []Contact
+	seen := make(map[string]bool)
+	for _, ref := range rpslContactRefs {
+		for _, referenced := range response.Resolve(object, ref.attr) {
+			contact := rpslContact(referenced, ref.role)
+			key := ref.role + "/" + contact.Handle
+			if seen[key] || !hasContactData(contact) {
+				continue
+			}
+			seen[key] = true
+			contacts = append(contacts, contact)
+		}
+	}
+	sortContacts(contacts)
+	return contacts
+}
+
+// rpslContact converts an organisation, role, person or IRT object into a Contact
+func rpslContact(object rpsl.Object, role string) Contact {
+	contact := Contact{
+		Role:   role,
+		Handle: firstNonEmpty(object.Get("nic-hdl", "nic-hdl-br", "orgid"), object.Key()),
+	}
+	switch object.Class() {
+	case "organisation", "orgname": //nolint:misspell // RIPE's class name
+		setContactField(&contact, "organization", object.Get("org-name", "orgname"))
+	default:
+		setContactField(&contact, "name", object.Get("person", "role", "irt", "orgabusename", "orgtechname"))
+	}
+	setContactField(&contact, "email", object.Get("abuse-mailbox", "e-mail", "orgabuseemail", "orgtechemail"))
+	setContactField(&contact, "phone", object.Get("phone", "orgabusephone", "orgtechphone"))
+	setContactField(&contact, "country", strings.ToUpper(object.Get("country")))
+	for _, line := range object.GetAll("address") {
+		setContactField(&contact, "address", line)
+	}
+	return contact
+}
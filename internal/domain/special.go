@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"regard/internal/iana"
+	"regard/internal/query"
+)
+
+// SpecialPurpose describes an address or ASN reserved by the IANA special-purpose
+// registries, which no RIR record would explain usefully
+type SpecialPurpose struct {
+	Query              string `json:"query"`
+	QueryType          string `json:"query_type"` // ip, reverse or asn
+	Protocol           string `json:"protocol"`
+	Block              string `json:"block"` // CIDR prefix or AS number range
+	Name               string `json:"name"`
+	RFC                string `json:"rfc"`
+	Allocated          string `json:"allocated,omitempty"`
+	Terminated         string `json:"terminated,omitempty"`
+	GloballyReachable  *bool  `json:"globally_reachable"` // null where the registry gives N/A
+	Source             *bool  `json:"source,omitempty"`
+	Destination        *bool  `json:"destination,omitempty"`
+	Forwardable        *bool  `json:"forwardable,omitempty"`
+	ReservedByProtocol *bool  `json:"reserved_by_protocol,omitempty"`
+}
+
+// LookupSpecialPurpose checks an address, prefix, reverse DNS name or ASN against
+// the bundled IANA special-purpose registries without any network access
+func LookupSpecialPurpose(input string) (*SpecialPurpose, bool) {
+	input = strings.TrimSpace(input)
+
+	switch queryType := query.DetectQueryType(input); queryType {
+	case query.QueryTypeIP, query.QueryTypeReverse:
+		prefix, ok := queryPrefix(input)
+		if queryType == query.QueryTypeReverse {
+			prefix, ok = query.ParseReverseName(input)
+		}
+		if !ok {
+			return nil, false
+		}
+		block, ok := iana.LookupSpecialAddress(prefix)
+		if !ok {
+			return nil, false
+		}
+		return &SpecialPurpose{
+			Query:              input,
+			QueryType:          string(queryType),
+			Protocol:           "IANA",
+			Block:              block.Prefix.String(),
+			Name:               block.Name,
+			RFC:                block.RFC,
+			Allocated:          block.Allocated,
+			Terminated:         block.Terminated,
+			GloballyReachable:  block.GloballyReachable,
+			Source:             block.Source,
+			Destination:        block.Destination,
+			Forwardable:        block.Forwardable,
+			ReservedByProtocol: block.ReservedByProtocol,
+		}, true
+
+	case query.QueryTypeASN:
+		number, ok := parseASNumber(input)
+		if !ok {
+			return nil, false
+		}
+		entry, ok := iana.LookupSpecialASN(number)
+		if !ok {
+			return nil, false
+		}
+		block := fmt.Sprintf("AS%d", entry.Start)
+		if entry.End != entry.Start {
+			block += fmt.Sprintf(" - AS%d", entry.End)
+		}
+		// None of these may appear in the global routing table
+		reachable := false
+		return &SpecialPurpose{
+			Query:             input,
+			QueryType:         string(query.QueryTypeASN),
+			Protocol:          "IANA",
+			Block:             block,
+			Name:              entry.Reason,
+			RFC:               entry.RFC,
+			GloballyReachable: &reachable,
+		}, true
+	}
+
+	return nil, false
+}
+
+// queryPrefix reads an address or CIDR prefix as a prefix, treating
+// addresses as full-length prefixes
+func queryPrefix(input string) (netip.Prefix, bool) {
+	if prefix, err := netip.ParsePrefix(input); err == nil {
+		return prefix.Masked(), true
+	}
+	addr, err := netip.ParseAddr(input)
+	if err != nil {
+		return netip.Prefix{}, false
+	}
+	addr = addr.WithZone("")
+	return netip.PrefixFrom(addr, addr.BitLen()), true
+}
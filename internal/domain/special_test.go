@@ -0,0 +1,43 @@
+package domain
+
+import "testing"
+
+func TestLookupSpecialPurpose(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectFound     bool
+		expectBlock     string
+		expectReachable bool
+	}{
+		{"192.168.1.1", true, "192.168.0.0/16", false},
+		{"10.0.0.0/16", true, "10.0.0.0/8", false},
+		{"::1", true, "::1/128", false},
+		{"fe80::1%eth0", true, "fe80::/10", false},
+		{"192.175.48.6", true, "192.175.48.0/24", true},
+		{"AS64512", true, "AS64512 - AS65534", false},
+		{"as0", true, "AS0", false},
+		{"1.168.192.in-addr.arpa", true, "192.168.0.0/16", false},
+		{"8.8.8.8", false, "", false},
+		{"10.0.0.0/7", false, "", false},
+		{"AS15169", false, "", false},
+		{"example.com", false, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			special, ok := LookupSpecialPurpose(tt.input)
+			if ok != tt.expectFound {
+				t.Fatalf("LookupSpecialPurpose(%q) found = %v, want %v", tt.input, ok, tt.expectFound)
+			}
+			if !ok {
+				return
+			}
+			if special.Block != tt.expectBlock {
+				t.Errorf("LookupSpecialPurpose(%q).Block = %q, want %q", tt.input, special.Block, tt.expectBlock)
+			}
+			if special.GloballyReachable == nil || *special.GloballyReachable != tt.expectReachable {
+				t.Errorf("LookupSpecialPurpose(%q).GloballyReachable = %v, want %v", tt.input, special.GloballyReachable, tt.expectReachable)
+			}
+		})
+	}
+}
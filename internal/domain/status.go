@@ -27,6 +27,15 @@ func InterpretStatus(statusDetails []string, rawData string) string {
 
 	for _, status := range statusDetails {
 		lowerStatus := strings.ToLower(status)
+		// Registry-specific availability statuses, e.g. DENIC "free" and EURid "AVAILABLE"
+		if lowerStatus == "free" || lowerStatus == "available" {
+			return "available"
+		}
+		// Registry-specific registered statuses, e.g. DENIC "connect", JPRS
+		// "Connected", registro.br "published" and Nominet "Registered until expiry date"
+		if lowerStatus == "connect" || lowerStatus == "connected" || strings.Contains(lowerStatus, "published") || strings.HasPrefix(lowerStatus, "registered") {
+			hasActive = true
+		}
 		if strings.Contains(lowerStatus, "active") || strings.Contains(lowerStatus, "ok") {
 			hasActive = true
 		}
@@ -103,6 +103,42 @@ Name Server: NS1.GOOGLE.COM`,
 			rawData:       "Domain has active status with some other text",
 			expected:      "active",
 		},
+		{
+			name:          "DENIC connect",
+			statusDetails: []string{"connect"},
+			rawData:       "Domain: example.de\nStatus: connect",
+			expected:      "active",
+		},
+		{
+			name:          "JPRS Connected",
+			statusDetails: []string{"Connected"},
+			rawData:       "[Domain Name]   EXAMPLE.JP\n[State]   Connected (2026/03/31)",
+			expected:      "active",
+		},
+		{
+			name:          "Disconnected is not registered",
+			statusDetails: []string{"disconnect"},
+			rawData:       "[Domain Name]   EXAMPLE.JP\n[State]   Disconnect",
+			expected:      "unknown",
+		},
+		{
+			name:          "DENIC free",
+			statusDetails: []string{"free"},
+			rawData:       "Domain: unregistered.de\nStatus: free",
+			expected:      "available",
+		},
+		{
+			name:          "Nominet registered",
+			statusDetails: []string{"Registered until expiry date"},
+			rawData:       "Registration status:\n    Registered until expiry date.",
+			expected:      "active",
+		},
+		{
+			name:          "registro.br published",
+			statusDetails: []string{"published"},
+			rawData:       "domain: example.com.br\nstatus: published",
+			expected:      "active",
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+
+	"regard/internal/iana"
+)
+
+// CreateTLDSummary builds a summary for a top-level domain from the bundled
+// IANA root zone database, the RDAP bootstrap file and the lifecycle policy table
+func CreateTLDSummary(name string) (Summary, error) {
+	tldName := iana.NormalizeTLD(name)
+
+	summary := Summary{
+		Domain:    tldName,
+		Protocol:  "IANA",
+		QueryType: "tld",
+	}
+
+	info := &TLDInfo{
+		Name:        tldName,
+		RDAPServers: iana.RDAPServersForTLD(tldName),
+	}
+
+	entry, inRootZone := iana.LookupTLD(tldName)
+	if !inRootZone && len(info.RDAPServers) == 0 {
+		return summary, fmt.Errorf("unknown TLD: %s", tldName)
+	}
+
+	if inRootZone {
+		summary.Status = "delegated"
+		info.Type = entry.Type
+		info.Manager = entry.Manager
+		info.WhoisServer = entry.WhoisServer
+		switch entry.DNSSEC {
+		case "signed":
+			summary.DNSSEC.Enabled = true
+			summary.DNSSEC.Details = "DS records published in the root zone"
+		case "unsigned":
+			summary.DNSSEC.Details = "no DS records in the root zone"
+		}
+		if !entry.RegistrationDate.IsZero() {
+			summary.Timeline.Registration = &TimelineEvent{
+				Date:          entry.RegistrationDate,
+				HumanReadable: HumanReadableTime(entry.RegistrationDate),
+			}
+		}
+	} else {
+		summary.Status = "unknown"
+	}
+
+	info.HasRDAP = len(info.RDAPServers) > 0
+
+	// The bundled copies only carry full details for some TLDs
+	var missing []string
+	if inRootZone && entry.WhoisServer == "" && entry.DNSSEC == "" {
+		missing = append(missing, "WHOIS server", "DNSSEC status")
+	}
+	if !info.HasRDAP && !iana.RDAPBootstrapRefreshed() {
+		info.RDAPUnknown = true
+		missing = append(missing, "RDAP servers")
+	}
+	if len(missing) > 0 {
+		list := missing[len(missing)-1]
+		if len(missing) > 1 {
+			list = strings.Join(missing[:len(missing)-1], ", ") + " or " + list
+		}
+		summary.Warnings = append(summary.Warnings, fmt.Sprintf("The bundled IANA data has no %s for this TLD; run regard tld --refresh", list))
+	}
+	if policy, ok := LookupLifecyclePolicy(tldName); ok {
+		info.Lifecycle = &policy
+	}
+
+	summary.TLD = info
+	return summary, nil
+}
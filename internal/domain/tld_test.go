@@ -0,0 +1,58 @@
+package domain
+
+import "testing"
+
+func TestCreateTLDSummary(t *testing.T) {
+	summary, err := CreateTLDSummary(".com")
+	if err != nil {
+		t.Fatalf("CreateTLDSummary() unexpected error: %v", err)
+	}
+
+	if summary.Domain != "com" || summary.QueryType != "tld" || summary.Status != "delegated" {
+		t.Errorf("Unexpected summary header: %+v", summary)
+	}
+	if summary.TLD == nil {
+		t.Fatal("Expected TLD section to be set")
+	}
+	if summary.TLD.WhoisServer != "whois.verisign-grs.com" {
+		t.Errorf("Expected Verisign WHOIS server, got %q", summary.TLD.WhoisServer)
+	}
+	if !summary.TLD.HasRDAP {
+		t.Error("Expected com to have RDAP")
+	}
+	if !summary.DNSSEC.Enabled {
+		t.Error("Expected com to be DNSSEC-signed")
+	}
+	if summary.TLD.Lifecycle == nil || summary.TLD.Lifecycle.DropDays() != 80 {
+		t.Errorf("Expected gTLD lifecycle with an 80-day drop, got %+v", summary.TLD.Lifecycle)
+	}
+	if summary.Timeline.Registration == nil {
+		t.Error("Expected registration date from root zone database")
+	}
+
+	uk, err := CreateTLDSummary("uk")
+	if err != nil {
+		t.Fatalf("CreateTLDSummary(uk) unexpected error: %v", err)
+	}
+	if uk.TLD.WhoisServer != "whois.nic.uk" {
+		t.Errorf("Expected Nominet WHOIS server, got %q", uk.TLD.WhoisServer)
+	}
+	if uk.TLD.Lifecycle == nil || uk.TLD.Lifecycle.DropDays() != 0 {
+		t.Errorf("Expected uk lifecycle without a fixed drop schedule, got %+v", uk.TLD.Lifecycle)
+	}
+
+	shop, err := CreateTLDSummary("shop")
+	if err != nil {
+		t.Fatalf("CreateTLDSummary(shop) unexpected error: %v", err)
+	}
+	if shop.TLD.Type != "generic" || shop.DNSSEC.Details != "" || len(shop.Warnings) != 1 {
+		t.Errorf("Expected a generic TLD with unknown DNSSEC and a refresh warning, got %+v %+v", shop.TLD, shop.Warnings)
+	}
+	if shop.TLD.HasRDAP || !shop.TLD.RDAPUnknown {
+		t.Errorf("Expected shop's RDAP to be unknown from the partial bundled bootstrap file, got %+v", shop.TLD)
+	}
+
+	if _, err := CreateTLDSummary("notatld"); err == nil {
+		t.Error("Expected error for unknown TLD")
+	}
+}
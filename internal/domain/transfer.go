@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"regard/internal/iana"
+)
+
+// icannTransferLockDays is how long the ICANN Transfer Policy blocks
+// inter-registrar transfers after a registration or a transfer
+const icannTransferLockDays = 60
+
+// registryLockStatuses are the server statuses a registry lock service sets
+var registryLockStatuses = []string{"serverupdateprohibited", "serverdeleteprohibited", "servertransferprohibited"}
+
+// AnalyzeTransfer works out whether a domain can be moved to another
+// registrar now, and if not, when and why
+func AnalyzeTransfer(summary Summary) *TransferInfo {
+	if summary.Status == "available" || (len(summary.StatusDetails) == 0 && summary.Timeline.Registration == nil) {
+		return nil
+	}
+
+	now := time.Now()
+	info := &TransferInfo{Eligible: true}
+	statuses := normalizeStatuses(summary.StatusDetails)
+
+	if statuses["pendingtransfer"] {
+		info.PendingTransfer = true
+		info.block("A transfer is already in progress (pendingTransfer)")
+	}
+	if statuses["clienttransferprohibited"] {
+		info.RegistrarLock = true
+		info.block("Registrar lock (clientTransferProhibited): ask the current registrar to unlock the domain")
+	}
+
+	var serverLocks []string
+	for _, status := range registryLockStatuses {
+		if statuses[status] {
+			serverLocks = append(serverLocks, status)
+		}
+	}
+	if len(serverLocks) > 0 {
+		info.RegistryLock = true
+		if statuses["servertransferprohibited"] {
+			info.block(fmt.Sprintf("Registry lock (%s): the current registrar must ask the registry to lift it", strings.Join(serverLocks, ", ")))
+		} else {
+			info.Reasons = append(info.Reasons, fmt.Sprintf("Registry lock (%s) does not block transfers, but may need lifting to change the domain afterwards", strings.Join(serverLocks, ", ")))
+		}
+	}
+
+	for _, status := range []string{"redemptionperiod", "pendingrestore", "pendingdelete"} {
+		if statuses[status] {
+			info.block(fmt.Sprintf("Domain is being deleted (%s) and must be restored before it can move", status))
+		}
+	}
+
+	// The 60-day lock applies to ICANN-accredited gTLDs; ccTLDs set their own rules
+	if icannTransferPolicy(summary.Domain) {
+		for _, lock := range []struct {
+			label string
+			event *TimelineEvent
+		}{
+			{"registration", summary.Timeline.Registration},
+			{"transfer", summary.Timeline.Transfer},
+		} {
+			if lock.event == nil || lock.event.Approximate {
+				continue
+			}
+			until := lock.event.Date.AddDate(0, 0, icannTransferLockDays)
+			if until.After(now) {
+				info.block(fmt.Sprintf("ICANN %d-day lock after the %s on %s", icannTransferLockDays, lock.label, lock.event.Date.Format("2006-01-02")))
+				if info.EligibleDate == nil || until.After(*info.EligibleDate) {
+					info.EligibleDate = &until
+				}
+			}
+		}
+	}
+
+	switch {
+	case info.Eligible:
+		info.Message = "Transferable now"
+	case info.EligibleDate != nil && info.onlyTimeLocked(statuses):
+		info.Message = fmt.Sprintf("Transferable from %s (%s)", info.EligibleDate.Format("2006-01-02"), HumanReadableTime(*info.EligibleDate))
+	default:
+		info.Message = "Not transferable until the locks below are removed"
+	}
+
+	return info
+}
+
+// block records a reason the domain cannot be transferred now
+func (t *TransferInfo) block(reason string) {
+	t.Eligible = false
+	t.Reasons = append(t.Reasons, reason)
+}
+
+// onlyTimeLocked reports whether the 60-day lock is the only thing blocking a transfer
+func (t *TransferInfo) onlyTimeLocked(statuses map[string]bool) bool {
+	return !t.PendingTransfer && !t.RegistrarLock && !statuses["servertransferprohibited"] &&
+		!statuses["redemptionperiod"] && !statuses["pendingrestore"] && !statuses["pendingdelete"]
+}
+
+// normalizeStatuses reduces RDAP ("client transfer prohibited") and EPP
+// ("clientTransferProhibited https://icann.org/epp#...") statuses to one form
+func normalizeStatuses(statusDetails []string) map[string]bool {
+	statuses := make(map[string]bool)
+	for _, status := range statusDetails {
+		status = strings.ToLower(status)
+		if i := strings.Index(status, "http"); i > 0 {
+			status = status[:i]
+		}
+		statuses[strings.Join(strings.Fields(status), "")] = true
+	}
+	return statuses
+}
+
+// icannTransferPolicy reports whether a domain is under a gTLD bound by the
+// ICANN Transfer Policy
+func icannTransferPolicy(domainName string) bool {
+	labels := strings.Split(strings.ToLower(strings.TrimSuffix(domainName, ".")), ".")
+	tld := labels[len(labels)-1]
+	if entry, ok := iana.LookupTLD(tld); ok {
+		return entry.Type == "generic" || entry.Type == "sponsored" || entry.Type == "generic-restricted"
+	}
+	return len(tld) > 2
+}
@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnalyzeTransfer(t *testing.T) {
+	now := time.Now()
+	longAgo := &TimelineEvent{Date: now.AddDate(-5, 0, 0)}
+
+	tests := []struct {
+		name           string
+		domain         string
+		statuses       []string
+		registration   *TimelineEvent
+		transfer       *TimelineEvent
+		expectEligible bool
+		expectDate     bool
+		registrarLock  bool
+		registryLock   bool
+		checkReason    string
+	}{
+		{
+			name:           "Unlocked domain",
+			domain:         "example.com",
+			statuses:       []string{"ok"},
+			registration:   longAgo,
+			expectEligible: true,
+		},
+		{
+			name:          "Registrar lock (EPP with URL)",
+			domain:        "example.com",
+			statuses:      []string{"clientTransferProhibited https://icann.org/epp#clientTransferProhibited"},
+			registration:  longAgo,
+			registrarLock: true,
+			checkReason:   "ask the current registrar",
+		},
+		{
+			name:         "Registry lock (RDAP)",
+			domain:       "example.com",
+			statuses:     []string{"server delete prohibited", "server transfer prohibited", "server update prohibited"},
+			registration: longAgo,
+			registryLock: true,
+			checkReason:  "serverupdateprohibited, serverdeleteprohibited, servertransferprohibited",
+		},
+		{
+			name:           "Server update lock only",
+			domain:         "example.com",
+			statuses:       []string{"serverUpdateProhibited"},
+			registration:   longAgo,
+			expectEligible: true,
+			registryLock:   true,
+		},
+		{
+			name:         "Pending transfer",
+			domain:       "example.com",
+			statuses:     []string{"pendingTransfer"},
+			registration: longAgo,
+			checkReason:  "already in progress",
+		},
+		{
+			name:         "Registered 10 days ago",
+			domain:       "example.com",
+			statuses:     []string{"ok"},
+			registration: &TimelineEvent{Date: now.AddDate(0, 0, -10)},
+			expectDate:   true,
+			checkReason:  "60-day lock after the registration",
+		},
+		{
+			name:         "Transferred 30 days ago",
+			domain:       "example.org",
+			statuses:     []string{"active"},
+			registration: longAgo,
+			transfer:     &TimelineEvent{Date: now.AddDate(0, 0, -30)},
+			expectDate:   true,
+			checkReason:  "60-day lock after the transfer",
+		},
+		{
+			name:           "ccTLD registered 10 days ago",
+			domain:         "example.de",
+			statuses:       []string{"connect"},
+			registration:   &TimelineEvent{Date: now.AddDate(0, 0, -10)},
+			expectEligible: true,
+		},
+		{
+			name:         "Redemption period",
+			domain:       "example.com",
+			statuses:     []string{"redemption period"},
+			registration: longAgo,
+			checkReason:  "must be restored",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := Summary{
+				Domain:        tt.domain,
+				Status:        "active",
+				StatusDetails: tt.statuses,
+				Timeline:      Timeline{Registration: tt.registration, Transfer: tt.transfer},
+			}
+
+			info := AnalyzeTransfer(summary)
+			if info == nil {
+				t.Fatal("AnalyzeTransfer() = nil")
+			}
+			if info.Eligible != tt.expectEligible {
+				t.Errorf("Eligible = %v, want %v (reasons %v)", info.Eligible, tt.expectEligible, info.Reasons)
+			}
+			if (info.EligibleDate != nil) != tt.expectDate {
+				t.Errorf("EligibleDate = %v, want set %v", info.EligibleDate, tt.expectDate)
+			}
+			if tt.expectDate && !strings.HasPrefix(info.Message, "Transferable from") {
+				t.Errorf("Message = %q, want a date it becomes transferable", info.Message)
+			}
+			if info.RegistrarLock != tt.registrarLock {
+				t.Errorf("RegistrarLock = %v, want %v", info.RegistrarLock, tt.registrarLock)
+			}
+			if info.RegistryLock != tt.registryLock {
+				t.Errorf("RegistryLock = %v, want %v", info.RegistryLock, tt.registryLock)
+			}
+			if tt.checkReason != "" && !strings.Contains(strings.Join(info.Reasons, "\n"), tt.checkReason) {
+				t.Errorf("Reasons = %v, want one containing %q", info.Reasons, tt.checkReason)
+			}
+		})
+	}
+}
+
+func TestAnalyzeTransfer_Available(t *testing.T) {
+	if info := AnalyzeTransfer(Summary{Domain: "example.com", Status: "available"}); info != nil {
+		t.Errorf("AnalyzeTransfer() = %+v, want nil for an available domain", info)
+	}
+}
@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+
+	"regard/internal/query"
+	"regard/internal/rpsl"
+)
+
+// maxTreeDepth bounds how many parent networks a tree walk follows
+const maxTreeDepth = 10
+
+// RDAPFetcher retrieves the parent networks a netblock tree walks up through
+type RDAPFetcher interface {
+	Lookup(query string) query.QueryResult // An address or CIDR prefix
+	Follow(href string) query.QueryResult  // An RDAP link
+}
+
+// NetworkTree is the allocation chain for an address, from the RIR block down
+// to the most specific network
+type NetworkTree struct {
+	Query    string      `json:"query"`
+	Protocol string      `json:"protocol"`
+	Levels   []IPSummary `json:"levels"` // Least specific first
+	Warnings []string    `json:"warnings,omitempty"`
+}
+
+// BuildNetworkTree assembles the allocation chain for an address. RDAP results
+// are walked upwards through "up" links and parent handles. WHOIS results
+// should come from a less-specific query, which lists every level at once.
+func BuildNetworkTree(result query.QueryResult, fetcher RDAPFetcher) *NetworkTree {
+	tree := &NetworkTree{Query: result.Query, Protocol: result.Protocol}
+
+	if result.Protocol == "RDAP" {
+		walkRDAPParents(tree, result, fetcher)
+	} else if data, ok := result.Data.(map[string]interface{}); ok {
+		if raw, ok := data["raw_response"].(string); ok {
+			whoisNetworkLevels(tree, result, raw)
+		}
+	}
+
+	if len(tree.Levels) == 0 {
+		tree.Warnings = append(tree.Warnings, "No network objects found")
+	}
+	return tree
+}
+
+// walkRDAPParents follows each network's "up" link, or looks up the
+// enclosing prefix when only a parent handle is given
+func walkRDAPParents(tree *NetworkTree, result query.QueryResult, fetcher RDAPFetcher) {
+	seen := make(map[string]bool)
+	var levels []IPSummary
+
+	for current := result; ; {
+		level := CreateIPSummary(current)
+		level.Query = result.Query
+		key := firstNonEmpty(level.Handle, level.StartAddress+"-"+level.EndAddress)
+		if seen[key] {
+			tree.Warnings = append(tree.Warnings, fmt.Sprintf("Parent links loop back to %s", key))
+			break
+		}
+		seen[key] = true
+		levels = append(levels, level)
+
+		if len(levels) >= maxTreeDepth {
+			tree.Warnings = append(tree.Warnings, fmt.Sprintf("Stopped after %d levels", maxTreeDepth))
+			break
+		}
+
+		var parent query.QueryResult
+		if level.ParentLink != "" {
+			parent = fetcher.Follow(level.ParentLink)
+		} else if supernet := parentQuery(level); level.ParentHandle != "" && supernet != "" {
+			parent = fetcher.Lookup(supernet)
+		} else {
+			break
+		}
+		if !parent.Success {
+			tree.Warnings = append(tree.Warnings, fmt.Sprintf("Could not fetch the parent of %s: %s", key, parent.Error))
+			break
+		}
+		current = parent
+	}
+
+	// Walked from the most specific network up, so reverse
+	for i := len(levels) - 1; i >= 0; i-- {
+		tree.Levels = append(tree.Levels, levels[i])
+	}
+}
+
+// whoisNetworkLevels reads every network object in a less-specific WHOIS response
+func whoisNetworkLevels(tree *NetworkTree, result query.QueryResult, raw string) {
+	response := rpsl.Parse(raw)
+	seen := make(map[string]bool)
+
+	for _, network := range response.Find("netrange", "inetnum", "inet6num") {
+		level := IPSummary{Query: result.Query, Protocol: result.Protocol, Server: result.Server}
+		parseRIRNetwork(response, network, &level)
+		completeIPSummary(&level)
+		key := level.StartAddress + "-" + level.EndAddress
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		tree.Levels = append(tree.Levels, level)
+	}
+
+	// Enclosing networks start no later and end no earlier than those inside them
+	sort.SliceStable(tree.Levels, func(i, j int) bool {
+		a, b := tree.Levels[i], tree.Levels[j]
+		aStart, errA := netip.ParseAddr(a.StartAddress)
+		bStart, errB := netip.ParseAddr(b.StartAddress)
+		if errA != nil || errB != nil {
+			return false
+		}
+		if c := aStart.Compare(bStart); c != 0 {
+			return c < 0
+		}
+		aEnd, _ := netip.ParseAddr(a.EndAddress)
+		bEnd, _ := netip.ParseAddr(b.EndAddress)
+		return aEnd.Compare(bEnd) > 0
+	})
+}
+
+// parentQuery returns the prefix to look up for a network's parent: the
+// smallest prefix strictly enclosing the network's range
+func parentQuery(level IPSummary) string {
+	start, err := netip.ParseAddr(level.StartAddress)
+	if err != nil {
+		return ""
+	}
+	end, err := netip.ParseAddr(level.EndAddress)
+	if err != nil || start.BitLen() != end.BitLen() {
+		return ""
+	}
+
+	// Shortest prefix shared by both ends of the range
+	bits := start.BitLen()
+	for bits > 0 && !netip.PrefixFrom(start, bits).Masked().Contains(end) {
+		bits--
+	}
+	covering := netip.PrefixFrom(start, bits).Masked()
+	if covering.Addr() == start && lastAddr(covering) == end {
+		// The range is exactly this prefix, so go one bit shorter
+		if bits == 0 {
+			return ""
+		}
+		covering = netip.PrefixFrom(start, bits-1).Masked()
+	}
+	return covering.String()
+}
+
+// Holder returns the best label for who holds a network level
+func (s IPSummary) Holder() string {
+	return strings.TrimSpace(firstNonEmpty(s.Organization, s.Name, s.Handle))
+}
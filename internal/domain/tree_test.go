@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"regard/internal/query"
+)
+
+// fakeFetcher answers RDAP lookups and links from maps of network objects
+type fakeFetcher struct {
+	lookups map[string]map[string]interface{}
+	links   map[string]map[string]interface{}
+	asked   []string
+}
+
+func (f *fakeFetcher) result(q string, network map[string]interface{}) query.QueryResult {
+	f.asked = append(f.asked, q)
+	if network == nil {
+		return query.QueryResult{Query: q, Protocol: "RDAP", Error: "404 not found"}
+	}
+	return query.QueryResult{Query: q, Type: string(query.QueryTypeIP), Protocol: "RDAP", Success: true, Data: network}
+}
+
+func (f *fakeFetcher) Lookup(q string) query.QueryResult { return f.result(q, f.lookups[q]) }
+
+func (f *fakeFetcher) Follow(href string) query.QueryResult { return f.result(href, f.links[href]) }
+
+func rdapNetwork(handle, start, end, name, parent, up string) map[string]interface{} {
+	network := map[string]interface{}{
+		"Handle":       handle,
+		"StartAddress": start,
+		"EndAddress":   end,
+		"Name":         name,
+		"ParentHandle": parent,
+	}
+	if up != "" {
+		network["Links"] = []interface{}{map[string]interface{}{"Rel": "up", "Href": up}}
+	}
+	return network
+}
+
+func TestBuildNetworkTree_RDAP(t *testing.T) {
+	customer := rdapNetwork("CUST", "203.0.113.0", "203.0.113.255", "CUSTOMER-NET", "ISP", "https://rdap.example/ip/203.0.112.0/22")
+	fetcher := &fakeFetcher{
+		links: map[string]map[string]interface{}{
+			"https://rdap.example/ip/203.0.112.0/22": rdapNetwork("ISP", "203.0.112.0", "203.0.115.255", "ISP-NET", "RIR", ""),
+		},
+		lookups: map[string]map[string]interface{}{
+			"203.0.112.0/21": rdapNetwork("RIR", "203.0.0.0", "203.0.255.255", "RIR-BLOCK", "", ""),
+		},
+	}
+
+	tree := BuildNetworkTree(query.QueryResult{Query: "203.0.113.5", Protocol: "RDAP", Success: true, Data: customer}, fetcher)
+
+	var handles []string
+	for _, level := range tree.Levels {
+		handles = append(handles, level.Handle)
+	}
+	if !reflect.DeepEqual(handles, []string{"RIR", "ISP", "CUST"}) {
+		t.Errorf("Levels = %v, want [RIR ISP CUST]", handles)
+	}
+	expectedAsked := []string{"https://rdap.example/ip/203.0.112.0/22", "203.0.112.0/21"}
+	if !reflect.DeepEqual(fetcher.asked, expectedAsked) {
+		t.Errorf("Fetched %v, want %v", fetcher.asked, expectedAsked)
+	}
+	if len(tree.Warnings) != 0 {
+		t.Errorf("Unexpected warnings: %v", tree.Warnings)
+	}
+	if !reflect.DeepEqual(tree.Levels[1].CIDRs, []string{"203.0.112.0/22"}) {
+		t.Errorf("Levels[1].CIDRs = %v, want [203.0.112.0/22]", tree.Levels[1].CIDRs)
+	}
+}
+
+func TestBuildNetworkTree_RDAPLoopAndFailure(t *testing.T) {
+	looping := rdapNetwork("LOOP", "192.0.2.0", "192.0.2.255", "LOOP-NET", "", "https://rdap.example/self")
+	fetcher := &fakeFetcher{links: map[string]map[string]interface{}{"https://rdap.example/self": looping}}
+
+	tree := BuildNetworkTree(query.QueryResult{Query: "192.0.2.1", Protocol: "RDAP", Success: true, Data: looping}, fetcher)
+	if len(tree.Levels) != 1 || len(tree.Warnings) != 1 {
+		t.Errorf("Loop: %d levels, warnings %v; want 1 level and a warning", len(tree.Levels), tree.Warnings)
+	}
+
+	orphan := rdapNetwork("ORPHAN", "192.0.2.0", "192.0.2.255", "ORPHAN-NET", "MISSING", "")
+	tree = BuildNetworkTree(query.QueryResult{Query: "192.0.2.1", Protocol: "RDAP", Success: true, Data: orphan}, &fakeFetcher{})
+	if len(tree.Levels) != 1 || len(tree.Warnings) != 1 {
+		t.Errorf("Missing parent: %d levels, warnings %v; want 1 level and a warning", len(tree.Levels), tree.Warnings)
+	}
+}
+
+func TestBuildNetworkTree_Whois(t *testing.T) {
+	raw := `% Information related to '203.0.113.0 - 203.0.113.255'
+
+inetnum:        203.0.113.0 - 203.0.113.255
+netname:        CUSTOMER-NET
+status:         ASSIGNED NON-PORTABLE
+
+inetnum:        203.0.0.0 - 203.0.255.255
+netname:        RIR-BLOCK
+status:         ALLOCATED PORTABLE
+
+inetnum:        203.0.112.0 - 203.0.115.255
+netname:        ISP-NET
+status:         ALLOCATED NON-PORTABLE
+`
+	result := query.QueryResult{
+		Query:    "203.0.113.5",
+		Protocol: "WHOIS",
+		Success:  true,
+		Data:     map[string]interface{}{"raw_response": raw},
+	}
+
+	tree := BuildNetworkTree(result, &fakeFetcher{})
+
+	var names []string
+	for _, level := range tree.Levels {
+		names = append(names, fmt.Sprintf("%s %v", level.Name, level.CIDRs))
+	}
+	expected := []string{"RIR-BLOCK [203.0.0.0/16]", "ISP-NET [203.0.112.0/22]", "CUSTOMER-NET [203.0.113.0/24]"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Levels = %v, want %v", names, expected)
+	}
+}
+
+func TestParentQuery(t *testing.T) {
+	tests := []struct {
+		start    string
+		end      string
+		expected string
+	}{
+		{"203.0.113.0", "203.0.113.255", "203.0.112.0/23"},
+		{"192.0.2.0", "192.0.2.10", "192.0.2.0/28"},
+		{"0.0.0.0", "255.255.255.255", ""},
+		{"2001:db8::", "2001:db8:ffff:ffff:ffff:ffff:ffff:ffff", "2001:db8::/31"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.start, func(t *testing.T) {
+			if got := parentQuery(IPSummary{StartAddress: tt.start, EndAddress: tt.end}); got != tt.expected {
+				t.Errorf("parentQuery(%s - %s) = %q, want %q", tt.start, tt.end, got, tt.expected)
+			}
+		})
+	}
+}
@@ -10,36 +10,168 @@ type Summary struct {
 	Protocol       string          `json:"protocol"`
 	QueryType      string          `json:"query_type,omitempty"`
 	Timeline       Timeline        `json:"timeline"`
-	Nameservers    []string        `json:"nameservers"`
+	Nameservers    []Nameserver    `json:"nameservers"`
 	DNSSEC         DNSSECInfo      `json:"dnssec"`
 	Registrar      RegistrarInfo   `json:"registrar"`
+	Contacts       []Contact       `json:"contacts,omitempty"`
+	Redactions     []Redaction     `json:"redactions,omitempty"`
+	Notices        []Notice        `json:"notices,omitempty"`
+	Remarks        []Notice        `json:"remarks,omitempty"`
+	Renewal        *RenewalInfo    `json:"renewal,omitempty"`
+	Transfer       *TransferInfo   `json:"transfer,omitempty"`
+	DNSCheck       *DNSCheck       `json:"dns_check,omitempty"`
 	PostExpiration *ExpirationInfo `json:"post_expiration,omitempty"`
 	ASN            *ASNInfo        `json:"asn,omitempty"`
+	TLD            *TLDInfo        `json:"tld,omitempty"`
+	ReverseZone    *ReverseZone    `json:"reverse_zone,omitempty"`
+	Warnings       []string        `json:"warnings,omitempty"`
 }
 
 // Timeline represents important dates in a domain's lifecycle
 type Timeline struct {
-	Registration *TimelineEvent `json:"registration,omitempty"`
-	LastUpdated  *TimelineEvent `json:"last_updated,omitempty"`
-	Expiration   *TimelineEvent `json:"expiration,omitempty"`
+	Registration        *TimelineEvent `json:"registration,omitempty"`
+	LastUpdated         *TimelineEvent `json:"last_updated,omitempty"`
+	Expiration          *TimelineEvent `json:"expiration,omitempty"`           // Registry expiry date
+	RegistrarExpiration *TimelineEvent `json:"registrar_expiration,omitempty"` // Registrar registration expiration date
+	Transfer            *TimelineEvent `json:"transfer,omitempty"`             // Most recent transfer
+	Reregistration      *TimelineEvent `json:"reregistration,omitempty"`
+	Locked              *TimelineEvent `json:"locked,omitempty"`
+	Unlocked            *TimelineEvent `json:"unlocked,omitempty"`
+	DatabaseUpdated     *TimelineEvent `json:"database_updated,omitempty"` // Freshness of the RDAP data, not a change to the domain
+
+	// Events holds every RDAP event in date order, including actions without a field above
+	Events []TimelineEvent `json:"events,omitempty"`
 }
 
 // TimelineEvent represents a single event with date and human-readable format
 type TimelineEvent struct {
 	Date          time.Time `json:"date"`
 	HumanReadable string    `json:"human_readable"`
+	Approximate   bool      `json:"approximate,omitempty"` // Date is an upper bound, e.g. "before Aug-1996"
+	Action        string    `json:"action,omitempty"`      // RDAP event action, set in Timeline.Events
+	Actor         string    `json:"actor,omitempty"`       // RDAP event actor, e.g. the gaining registrar
+}
+
+// Nameserver is a host a domain is delegated to, with any glue addresses the
+// registry holds for it
+type Nameserver struct {
+	Name        string   `json:"name"`
+	Handle      string   `json:"handle,omitempty"`
+	IPv4        []string `json:"ipv4,omitempty"`
+	IPv6        []string `json:"ipv6,omitempty"`
+	InBailiwick bool     `json:"in_bailiwick"` // Host is inside the domain, so it needs glue
+	Status      []string `json:"status,omitempty"`
 }
 
 // DNSSECInfo represents DNSSEC status information
 type DNSSECInfo struct {
-	Enabled bool   `json:"enabled"`
-	Details string `json:"details,omitempty"`
+	Enabled    bool       `json:"enabled"`
+	Details    string     `json:"details,omitempty"`
+	DSRecords  []DSRecord `json:"ds_records,omitempty"`
+	Keys       []DNSKey   `json:"keys,omitempty"`
+	MaxSigLife uint64     `json:"max_sig_life,omitempty"` // Seconds
+	Issues     []string   `json:"issues,omitempty"`       // Deprecated algorithms and digest types
+}
+
+// DSRecord is a delegation signer record published in the parent zone
+type DSRecord struct {
+	KeyTag         int    `json:"key_tag"`
+	Algorithm      int    `json:"algorithm"`
+	AlgorithmName  string `json:"algorithm_name,omitempty"`
+	DigestType     int    `json:"digest_type"`
+	DigestTypeName string `json:"digest_type_name,omitempty"`
+	Digest         string `json:"digest"`
+	Deprecated     bool   `json:"deprecated,omitempty"`
+}
+
+// DNSKey is key data a registry holds for a signed delegation
+type DNSKey struct {
+	KeyTag        int    `json:"key_tag,omitempty"` // Set for keys resolved from the live DNS
+	Flags         int    `json:"flags"`
+	Protocol      int    `json:"protocol"`
+	Algorithm     int    `json:"algorithm"`
+	AlgorithmName string `json:"algorithm_name,omitempty"`
+	PublicKey     string `json:"public_key"`
+	Deprecated    bool   `json:"deprecated,omitempty"`
 }
 
-// RegistrarInfo represents registrar information
+// RegistrarInfo represents registrar information, enriched from the IANA registrar ID registry
 type RegistrarInfo struct {
-	Name string `json:"name"`
-	ID   string `json:"id,omitempty"`
+	Name          string `json:"name"`
+	ID            string `json:"id,omitempty"`
+	IANAID        string `json:"iana_id,omitempty"`
+	CanonicalName string `json:"canonical_name,omitempty"`
+	Status        string `json:"status,omitempty"` // accredited, terminated or reserved
+	RDAPBaseURL   string `json:"rdap_base_url,omitempty"`
+	AbuseEmail    string `json:"abuse_email,omitempty"`
+	AbusePhone    string `json:"abuse_phone,omitempty"`
+}
+
+// Contact represents a registrant, administrative, technical, billing or abuse contact
+type Contact struct {
+	Role         string   `json:"role"`
+	Handle       string   `json:"handle,omitempty"`
+	Name         string   `json:"name,omitempty"`
+	Organization string   `json:"organization,omitempty"`
+	Email        string   `json:"email,omitempty"`
+	Phone        string   `json:"phone,omitempty"`
+	Country      string   `json:"country,omitempty"`
+	Address      []string `json:"address,omitempty"`
+	Redacted     []string `json:"redacted,omitempty"` // Fields withheld by the registry for privacy
+}
+
+// Redaction describes a field the registry withheld, per RFC 9537
+type Redaction struct {
+	Field  string `json:"field"`
+	Method string `json:"method,omitempty"` // removal, emptyValue, partialValue or replacementValue
+	Reason string `json:"reason,omitempty"`
+	Path   string `json:"path,omitempty"`
+}
+
+// Notice represents an RDAP notice or remark, such as terms of service
+type Notice struct {
+	Title       string   `json:"title,omitempty"`
+	Type        string   `json:"type,omitempty"`
+	Description []string `json:"description,omitempty"`
+	Links       []string `json:"links,omitempty"`
+}
+
+// RenewalInfo describes auto-renewal and how the registry and registrar
+// expiration dates relate
+type RenewalInfo struct {
+	AutoRenew      bool   `json:"auto_renew"`
+	Reason         string `json:"reason,omitempty"`
+	DivergenceDays int    `json:"divergence_days,omitempty"` // Registry date minus registrar date
+}
+
+// DNSCheck compares the registry's delegation with the live DNS
+type DNSCheck struct {
+	Resolver    string           `json:"resolver"`
+	Nameservers []LiveNameserver `json:"nameservers"`
+	DNSKeys     []DNSKey         `json:"dnskeys,omitempty"`
+	DSMatched   int              `json:"ds_matched"` // Registry DS records matching a live DNSKEY
+	Mismatches  []string         `json:"mismatches,omitempty"`
+}
+
+// LiveNameserver is a nameserver from the registry delegation or the zone's NS records
+type LiveNameserver struct {
+	Name        string   `json:"name"`
+	Addresses   []string `json:"addresses,omitempty"`
+	InRegistry  bool     `json:"in_registry"`
+	InDNS       bool     `json:"in_dns"`
+	Lame        bool     `json:"lame,omitempty"`        // Unresolvable or not answering authoritatively
+	Unreachable []string `json:"unreachable,omitempty"` // Addresses this host has no route to, so unchecked
+}
+
+// TransferInfo describes whether a domain can move to another registrar
+type TransferInfo struct {
+	Eligible        bool       `json:"eligible"`
+	Message         string     `json:"message"`
+	EligibleDate    *time.Time `json:"eligible_date,omitempty"` // When the ICANN 60-day lock ends
+	RegistrarLock   bool       `json:"registrar_lock"`          // clientTransferProhibited
+	RegistryLock    bool       `json:"registry_lock"`           // server*Prohibited
+	PendingTransfer bool       `json:"pending_transfer,omitempty"`
+	Reasons         []string   `json:"reasons,omitempty"`
 }
 
 // ExpirationInfo provides guidance for expired domains
@@ -51,12 +183,37 @@ type ExpirationInfo struct {
 
 // ASNInfo represents Autonomous System Number information
 type ASNInfo struct {
-	Number       string   `json:"number"`
-	Name         string   `json:"name"`
-	Description  string   `json:"description,omitempty"`
-	Country      string   `json:"country,omitempty"`
-	Organization string   `json:"organization"`
-	Status       string   `json:"status,omitempty"`
-	AbuseContact string   `json:"abuse_contact,omitempty"`
-	Peers        []string `json:"peers,omitempty"`
+	Number       string         `json:"number"`
+	Handle       string         `json:"handle,omitempty"`
+	StartAutnum  uint32         `json:"start_autnum,omitempty"`
+	EndAutnum    uint32         `json:"end_autnum,omitempty"`
+	Name         string         `json:"name"`
+	Type         string         `json:"type,omitempty"` // e.g. "DIRECT ALLOCATION" or "ASSIGNED"
+	Description  string         `json:"description,omitempty"`
+	Country      string         `json:"country,omitempty"`
+	Organization string         `json:"organization"`
+	Status       string         `json:"status,omitempty"`
+	AbuseContact string         `json:"abuse_contact,omitempty"`
+	Peers        []string       `json:"peers,omitempty"`
+	ASSets       []string       `json:"as_sets,omitempty"`       // Named in the routing policy
+	Prefixes     []string       `json:"prefixes,omitempty"`      // Originated in the local routing table
+	RoutingTable string         `json:"routing_table,omitempty"` // The table dump the prefixes came from
+	RPKI         *RouteValidity `json:"rpki,omitempty"`          // Origin validation of the originated prefixes
+
+	// IRR data, with --expand
+	IRRServer       string           `json:"irr_server,omitempty"`
+	IRRRoutes       []string         `json:"irr_routes,omitempty"` // route and route6 objects with this origin
+	ASSetExpansions []ASSetExpansion `json:"as_set_expansions,omitempty"`
+}
+
+// TLDInfo represents a top-level domain's root zone and registry policy details
+type TLDInfo struct {
+	Name        string           `json:"name"`
+	Type        string           `json:"type,omitempty"`
+	Manager     string           `json:"registry_operator,omitempty"`
+	WhoisServer string           `json:"whois_server,omitempty"`
+	HasRDAP     bool             `json:"has_rdap"`
+	RDAPServers []string         `json:"rdap_servers,omitempty"`
+	RDAPUnknown bool             `json:"rdap_unknown,omitempty"` // Missing only from the partial bundled bootstrap file
+	Lifecycle   *LifecyclePolicy `json:"lifecycle,omitempty"`
 }
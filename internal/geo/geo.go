@@ -0,0 +1,143 @@
+// Package geo reads local MaxMind DB (.mmdb) geolocation and ASN databases,
+// such as GeoLite2 and DB-IP, without any network access
+package geo
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"time"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// Record is what the geolocation databases say about an address. It is
+// database data, not registry data, and may disagree with the RIR records.
+type Record struct {
+	Network        string   `json:"network,omitempty"` // Most specific database network containing the address
+	Country        string   `json:"country,omitempty"` // ISO 3166-1 code
+	CountryName    string   `json:"country_name,omitempty"`
+	Region         string   `json:"region,omitempty"`
+	City           string   `json:"city,omitempty"`
+	Latitude       *float64 `json:"latitude,omitempty"`
+	Longitude      *float64 `json:"longitude,omitempty"`
+	AccuracyRadius uint16   `json:"accuracy_radius_km,omitempty"`
+	ASN            uint32   `json:"asn,omitempty"`
+	ASOrganization string   `json:"as_organization,omitempty"`
+	Sources        []string `json:"sources"` // Databases that answered, with their build dates
+}
+
+// mmdbRecord is the GeoIP2/GeoLite2 City, Country and ASN layout, which DB-IP also uses
+type mmdbRecord struct {
+	Country struct {
+		ISOCode string            `maxminddb:"iso_code"`
+		Names   map[string]string `maxminddb:"names"`
+	} `maxminddb:"country"`
+	Subdivisions []struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"subdivisions"`
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+	Location struct {
+		Latitude       *float64 `maxminddb:"latitude"`
+		Longitude      *float64 `maxminddb:"longitude"`
+		AccuracyRadius uint16   `maxminddb:"accuracy_radius"`
+	} `maxminddb:"location"`
+	ASN            uint32 `maxminddb:"autonomous_system_number"`
+	ASOrganization string `maxminddb:"autonomous_system_organization"`
+}
+
+// Databases is a set of open MaxMind DB files, consulted in order
+type Databases struct {
+	readers []*maxminddb.Reader
+	paths   []string
+}
+
+// Open opens each database file. The files stay open until Close.
+func Open(paths []string) (*Databases, error) {
+	dbs := &Databases{}
+	for _, path := range paths {
+		reader, err := maxminddb.Open(path)
+		if err != nil {
+			dbs.Close()
+			return nil, fmt.Errorf("opening geolocation database %s: %w", path, err)
+		}
+		dbs.readers = append(dbs.readers, reader)
+		dbs.paths = append(dbs.paths, path)
+	}
+	return dbs, nil
+}
+
+// Close releases the database files
+func (d *Databases) Close() {
+	for _, reader := range d.readers {
+		_ = reader.Close()
+	}
+	d.readers = nil
+}
+
+// Lookup merges what every database knows about an address. Earlier
+// databases win where two disagree. It returns nil when none has an entry.
+func (d *Databases) Lookup(addr netip.Addr) (*Record, error) {
+	var merged *Record
+	addr = addr.Unmap()
+	for i, reader := range d.readers {
+		// IPv4-only databases reject IPv6 lookups outright
+		if addr.Is6() && reader.Metadata.IPVersion == 4 {
+			continue
+		}
+		var entry mmdbRecord
+		network, ok, err := reader.LookupNetwork(net.IP(addr.AsSlice()), &entry)
+		if err != nil {
+			return nil, fmt.Errorf("reading geolocation database %s: %w", d.paths[i], err)
+		}
+		if !ok {
+			continue
+		}
+		if merged == nil {
+			merged = &Record{}
+		}
+		merged.merge(entry, network)
+		merged.Sources = append(merged.Sources, describeDatabase(reader.Metadata, d.paths[i]))
+	}
+	return merged, nil
+}
+
+// merge fills the fields still empty from a database entry
+func (r *Record) merge(entry mmdbRecord, network *net.IPNet) {
+	if r.Network == "" && network != nil {
+		r.Network = network.String()
+	}
+	if r.Country == "" {
+		r.Country = entry.Country.ISOCode
+		r.CountryName = entry.Country.Names["en"]
+	}
+	if r.Region == "" && len(entry.Subdivisions) > 0 {
+		r.Region = entry.Subdivisions[0].Names["en"]
+	}
+	if r.City == "" {
+		r.City = entry.City.Names["en"]
+	}
+	if r.Latitude == nil && r.Longitude == nil && entry.Location.Latitude != nil && entry.Location.Longitude != nil {
+		r.Latitude, r.Longitude = entry.Location.Latitude, entry.Location.Longitude
+		r.AccuracyRadius = entry.Location.AccuracyRadius
+	}
+	if r.ASN == 0 {
+		r.ASN = entry.ASN
+		r.ASOrganization = entry.ASOrganization
+	}
+}
+
+// describeDatabase names a database by its type and build date, e.g. "GeoLite2-City (2025-10-14)"
+func describeDatabase(metadata maxminddb.Metadata, path string) string {
+	name := metadata.DatabaseType
+	if name == "" {
+		name = path
+	}
+	if metadata.BuildEpoch == 0 {
+		return name
+	}
+	built := time.Unix(int64(metadata.BuildEpoch), 0).UTC().Format("2006-01-02")
+	return fmt.Sprintf("%s (%s)", name, built)
+}
@@ -0,0 +1,221 @@
+package geo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// writeTestDatabase writes a minimal IPv6 MaxMind DB file (record size 24)
+// mapping each prefix to its record
+func writeTestDatabase(t *testing.T, databaseType string, records map[string]map[string]interface{}) string {
+	t.Helper()
+
+	var data bytes.Buffer
+	type leaf struct {
+		prefix netip.Prefix
+		offset int
+	}
+	var leaves []leaf
+	prefixes := make([]string, 0, len(records))
+	for prefix := range records {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+	for _, prefix := range prefixes {
+		leaves = append(leaves, leaf{netip.MustParsePrefix(prefix), data.Len()})
+		data.Write(encodeTestValue(records[prefix]))
+	}
+
+	// Search tree: IPv4 prefixes live under ::/96. Records are node indexes,
+	// -1 for empty or -2-offset for data.
+	nodes := [][2]int{{-1, -1}}
+	for _, l := range leaves {
+		var bits [16]byte
+		length := l.prefix.Bits()
+		if l.prefix.Addr().Is4() {
+			v4 := l.prefix.Addr().As4()
+			copy(bits[12:], v4[:])
+			length += 96
+		} else {
+			bits = l.prefix.Addr().As16()
+		}
+		node := 0
+		for i := 0; i < length; i++ {
+			bit := (bits[i/8] >> (7 - uint(i%8))) & 1
+			if i == length-1 {
+				nodes[node][bit] = -2 - l.offset
+				break
+			}
+			if nodes[node][bit] < 0 {
+				nodes = append(nodes, [2]int{-1, -1})
+				nodes[node][bit] = len(nodes) - 1
+			}
+			node = nodes[node][bit]
+		}
+	}
+
+	var file bytes.Buffer
+	nodeCount := len(nodes)
+	for _, node := range nodes {
+		for _, record := range node {
+			value := record
+			switch {
+			case record == -1:
+				value = nodeCount
+			case record < -1:
+				value = nodeCount + 16 + (-2 - record)
+			}
+			file.Write([]byte{byte(value >> 16), byte(value >> 8), byte(value)})
+		}
+	}
+	file.Write(make([]byte, 16))
+	file.Write(data.Bytes())
+	file.WriteString("\xab\xcd\xefMaxMind.com")
+	file.Write(encodeTestValue(map[string]interface{}{
+		"node_count":                  uint32(nodeCount),
+		"record_size":                 uint16(24),
+		"ip_version":                  uint16(6),
+		"database_type":               databaseType,
+		"languages":                   []interface{}{"en"},
+		"binary_format_major_version": uint16(2),
+		"binary_format_minor_version": uint16(0),
+		"build_epoch":                 uint64(1760400000), // 2025-10-14
+		"description":                 map[string]interface{}{"en": "test database"},
+	}))
+
+	path := filepath.Join(t.TempDir(), databaseType+".mmdb")
+	if err := os.WriteFile(path, file.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// encodeTestValue encodes a value in the MaxMind DB data section format. Sizes
+// must stay below 285, which is plenty for test records.
+func encodeTestValue(value interface{}) []byte {
+	control := func(typeNum, size int) []byte {
+		sizeBits, extra := size, []byte{}
+		if size >= 29 {
+			sizeBits, extra = 29, []byte{byte(size - 29)}
+		}
+		if typeNum > 7 {
+			return append([]byte{byte(sizeBits), byte(typeNum - 7)}, extra...)
+		}
+		return append([]byte{byte(typeNum<<5 | sizeBits)}, extra...)
+	}
+	unsigned := func(typeNum int, n uint64, width int) []byte {
+		raw := make([]byte, 8)
+		binary.BigEndian.PutUint64(raw, n)
+		raw = bytes.TrimLeft(raw[8-width:], "\x00")
+		return append(control(typeNum, len(raw)), raw...)
+	}
+
+	switch v := value.(type) {
+	case string:
+		return append(control(2, len(v)), v...)
+	case float64:
+		raw := make([]byte, 8)
+		binary.BigEndian.PutUint64(raw, math.Float64bits(v))
+		return append(control(3, 8), raw...)
+	case uint16:
+		return unsigned(5, uint64(v), 2)
+	case uint32:
+		return unsigned(6, uint64(v), 4)
+	case uint64:
+		return unsigned(9, v, 8)
+	case []interface{}:
+		encoded := control(11, len(v))
+		for _, item := range v {
+			encoded = append(encoded, encodeTestValue(item)...)
+		}
+		return encoded
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		encoded := control(7, len(v))
+		for _, key := range keys {
+			encoded = append(encoded, encodeTestValue(key)...)
+			encoded = append(encoded, encodeTestValue(v[key])...)
+		}
+		return encoded
+	}
+	panic("unsupported test value")
+}
+
+func TestDatabases_Lookup(t *testing.T) {
+	city := writeTestDatabase(t, "GeoLite2-City", map[string]map[string]interface{}{
+		"198.51.100.0/24": {
+			"country":      map[string]interface{}{"iso_code": "NL", "names": map[string]interface{}{"en": "Netherlands"}},
+			"subdivisions": []interface{}{map[string]interface{}{"names": map[string]interface{}{"en": "North Holland"}}},
+			"city":         map[string]interface{}{"names": map[string]interface{}{"en": "Amsterdam"}},
+			"location":     map[string]interface{}{"latitude": 52.3759, "longitude": 4.8975, "accuracy_radius": uint16(20)},
+		},
+		"2001:db8::/32": {
+			"country": map[string]interface{}{"iso_code": "DE", "names": map[string]interface{}{"en": "Germany"}},
+		},
+	})
+	asn := writeTestDatabase(t, "GeoLite2-ASN", map[string]map[string]interface{}{
+		"198.51.0.0/16": {"autonomous_system_number": uint32(64500), "autonomous_system_organization": "Example Networks"},
+	})
+
+	databases, err := Open([]string{city, asn})
+	if err != nil {
+		t.Fatalf("Open() unexpected error: %v", err)
+	}
+	defer databases.Close()
+
+	record, err := databases.Lookup(netip.MustParseAddr("198.51.100.7"))
+	if err != nil {
+		t.Fatalf("Lookup() unexpected error: %v", err)
+	}
+	if record == nil {
+		t.Fatal("Lookup() = nil, want a record")
+	}
+	if record.Country != "NL" || record.CountryName != "Netherlands" || record.Region != "North Holland" || record.City != "Amsterdam" {
+		t.Errorf("Location = %s %s %s %s", record.Country, record.CountryName, record.Region, record.City)
+	}
+	if record.Latitude == nil || *record.Latitude != 52.3759 || record.AccuracyRadius != 20 {
+		t.Errorf("Coordinates = %v, radius %d", record.Latitude, record.AccuracyRadius)
+	}
+	if record.ASN != 64500 || record.ASOrganization != "Example Networks" {
+		t.Errorf("AS = %d %q, want 64500 Example Networks", record.ASN, record.ASOrganization)
+	}
+	if record.Network != "198.51.100.0/24" {
+		t.Errorf("Network = %q, want the most specific 198.51.100.0/24", record.Network)
+	}
+	expectedSources := []string{"GeoLite2-City (2025-10-14)", "GeoLite2-ASN (2025-10-14)"}
+	if !reflect.DeepEqual(record.Sources, expectedSources) {
+		t.Errorf("Sources = %v, want %v", record.Sources, expectedSources)
+	}
+
+	// Only the ASN database covers this address
+	record, _ = databases.Lookup(netip.MustParseAddr("198.51.7.1"))
+	if record == nil || record.Country != "" || record.ASN != 64500 || len(record.Sources) != 1 {
+		t.Errorf("Lookup(198.51.7.1) = %+v, want only ASN data", record)
+	}
+
+	record, _ = databases.Lookup(netip.MustParseAddr("2001:db8::1"))
+	if record == nil || record.Country != "DE" {
+		t.Errorf("Lookup(2001:db8::1) = %+v, want DE", record)
+	}
+
+	if record, err := databases.Lookup(netip.MustParseAddr("192.0.2.1")); record != nil || err != nil {
+		t.Errorf("Lookup(192.0.2.1) = %+v, %v; want nil", record, err)
+	}
+}
+
+func TestOpen_MissingFile(t *testing.T) {
+	if _, err := Open([]string{filepath.Join(t.TempDir(), "missing.mmdb")}); err == nil {
+		t.Error("Expected error for a missing database")
+	}
+}
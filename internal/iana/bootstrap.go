@@ -0,0 +1,101 @@
+package iana
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// RDAPDNSURL is the IANA RDAP bootstrap file for DNS registrations
+const RDAPDNSURL = "https://data.iana.org/rdap/dns.json"
+
+// rdapDNSCacheFile is the name of the refreshed bootstrap file in the cache directory
+const rdapDNSCacheFile = "rdap-dns.json"
+
+// Bundled partial copy of the IANA RDAP bootstrap file for DNS registrations,
+// listing only some TLDs until go generate replaces it with IANA's file
+//
+//go:embed data/rdap-dns.json
+var bundledRDAPDNS []byte
+
+// BootstrapFile is an IANA RDAP bootstrap registry (RFC 9224)
+type BootstrapFile struct {
+	Description string       `json:"description"`
+	Publication string       `json:"publication"`
+	Services    [][][]string `json:"services"`
+	Version     string       `json:"version"`
+}
+
+var (
+	rdapDNSOnce      sync.Once
+	rdapDNS          *BootstrapFile
+	rdapDNSRefreshed bool
+)
+
+// ParseBootstrapFile decodes an RDAP bootstrap registry
+func ParseBootstrapFile(data []byte) (*BootstrapFile, error) {
+	var file BootstrapFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing RDAP bootstrap file: %w", err)
+	}
+	return &file, nil
+}
+
+// Lookup returns the service URLs registered for an exact entry, such as a TLD
+func (f *BootstrapFile) Lookup(entry string) []string {
+	entry = strings.ToLower(entry)
+	for _, service := range f.Services {
+		if len(service) < 2 {
+			continue
+		}
+		for _, candidate := range service[0] {
+			if strings.ToLower(candidate) == entry {
+				return service[1]
+			}
+		}
+	}
+	return nil
+}
+
+// FetchRDAPDNS downloads the current RDAP bootstrap file for DNS
+// registrations, checking that it parses
+func FetchRDAPDNS() ([]byte, error) {
+	data, err := download(RDAPDNSURL, "RDAP bootstrap file")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ParseBootstrapFile(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// RDAPServersForTLD returns the RDAP base URLs listed for a TLD in the DNS
+// bootstrap file, preferring the refreshed copy in the cache directory
+func RDAPServersForTLD(name string) []string {
+	loadRDAPDNS()
+	if rdapDNS == nil {
+		return nil
+	}
+	return rdapDNS.Lookup(NormalizeTLD(name))
+}
+
+// RDAPBootstrapRefreshed reports whether the DNS bootstrap file was refreshed,
+// rather than being the partial bundled copy
+func RDAPBootstrapRefreshed() bool {
+	loadRDAPDNS()
+	return rdapDNSRefreshed
+}
+
+func loadRDAPDNS() {
+	rdapDNSOnce.Do(func() {
+		rdapDNS, _ = ParseBootstrapFile(bundledRDAPDNS)
+		if data, err := readCache(rdapDNSCacheFile); err == nil {
+			if refreshed, err := ParseBootstrapFile(data); err == nil {
+				rdapDNS, rdapDNSRefreshed = refreshed, true
+			}
+		}
+	})
+}
@@ -0,0 +1,36 @@
+//go:build ignore
+
+// Regenerates the bundled IANA registries in data/ from their published copies
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"regard/internal/iana"
+)
+
+func main() {
+	sources := []struct {
+		file  string
+		fetch func() ([]byte, error)
+	}{
+		{"registrar-ids.csv", iana.FetchRegistrarIDs},
+		{"root-zone.csv", iana.FetchRootZone},
+		{"rdap-dns.json", iana.FetchRDAPDNS},
+	}
+
+	for _, source := range sources {
+		data, err := source.fetch()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(filepath.Join("data", source.file), data, 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Wrote data/%s (%d bytes)\n", source.file, len(data))
+	}
+}
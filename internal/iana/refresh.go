@@ -0,0 +1,77 @@
+package iana
+
+//go:generate go run gen.go
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// downloadTimeout bounds each registry download
+const downloadTimeout = 30 * time.Second
+
+// cacheDir overrides the default cache directory when set
+var cacheDir string
+
+// SetCacheDir sets the directory refreshed registries are stored in. It must be
+// called before any registry is loaded.
+func SetCacheDir(dir string) {
+	cacheDir = dir
+}
+
+// cachePath returns the path of a file in regard's cache directory
+func cachePath(name string) (string, error) {
+	if cacheDir != "" {
+		return filepath.Join(cacheDir, name), nil
+	}
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("locating cache directory: %w", err)
+	}
+	return filepath.Join(base, "regard", name), nil
+}
+
+// readCache returns a refreshed registry from the cache directory
+func readCache(name string) ([]byte, error) {
+	path, err := cachePath(name)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(path)
+}
+
+// saveCache stores a refreshed registry in the cache directory
+func saveCache(name string, data []byte) error {
+	path, err := cachePath(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating cache directory: %w", err)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// download fetches an IANA registry, naming it in errors as what
+func download(url, what string) ([]byte, error) {
+	client := &http.Client{Timeout: downloadTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("downloading %s: %w", what, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading %s: unexpected status %s", what, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("downloading %s: %w", what, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,167 @@
+package iana
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// RegistrarIDsURL is the IANA registrar ID registry in CSV form
+const RegistrarIDsURL = "https://www.iana.org/assignments/registrar-ids/registrar-ids-1.csv"
+
+// registrarCacheFile is the name of the refreshed registry copy in the cache directory
+const registrarCacheFile = "registrar-ids.csv"
+
+// Bundled partial copy of the IANA registrar ID registry, listing only some
+// registrars until go generate replaces it with IANA's registry
+//
+//go:embed data/registrar-ids.csv
+var bundledRegistrarIDs string
+
+// Registrar is an entry in the IANA registrar ID registry
+type Registrar struct {
+	ID          string
+	Name        string
+	Status      string // Accredited, Terminated or Reserved
+	RDAPBaseURL string
+}
+
+var (
+	registrarsOnce      sync.Once
+	registrars          map[string]Registrar
+	registrarsRefreshed bool
+)
+
+// LookupRegistrar returns the registry entry for an IANA registrar ID
+func LookupRegistrar(id string) (Registrar, bool) {
+	registrar, ok := loadRegistrars()[strings.TrimSpace(id)]
+	return registrar, ok
+}
+
+// SearchRegistrars returns registrars whose name contains the search term,
+// ordered by numeric ID
+func SearchRegistrars(term string) []Registrar {
+	term = strings.ToLower(strings.TrimSpace(term))
+	var matches []Registrar
+	if term == "" {
+		return matches
+	}
+
+	for _, registrar := range loadRegistrars() {
+		if strings.Contains(strings.ToLower(registrar.Name), term) {
+			matches = append(matches, registrar)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if len(matches[i].ID) != len(matches[j].ID) {
+			return len(matches[i].ID) < len(matches[j].ID)
+		}
+		return matches[i].ID < matches[j].ID
+	})
+	return matches
+}
+
+// FetchRegistrarIDs downloads the current IANA registrar ID registry,
+// checking that it parses
+func FetchRegistrarIDs() ([]byte, error) {
+	data, err := download(RegistrarIDsURL, "registrar IDs")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := parseRegistrarCSV(string(data)); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// RefreshRegistrars downloads the current IANA registrar ID registry into the
+// cache directory, returning the number of registrars it contains
+func RefreshRegistrars() (int, error) {
+	data, err := FetchRegistrarIDs()
+	if err != nil {
+		return 0, err
+	}
+	if err := saveCache(registrarCacheFile, data); err != nil {
+		return 0, fmt.Errorf("saving registrar IDs: %w", err)
+	}
+	parsed, _ := parseRegistrarCSV(string(data))
+	return len(parsed), nil
+}
+
+// loadRegistrars parses the bundled registry once, or the refreshed copy from
+// the cache directory when one exists
+func loadRegistrars() map[string]Registrar {
+	registrarsOnce.Do(func() {
+		registrars, _ = parseRegistrarCSV(bundledRegistrarIDs)
+		if registrars == nil {
+			registrars = map[string]Registrar{}
+		}
+
+		data, err := readCache(registrarCacheFile)
+		if err != nil {
+			return
+		}
+		refreshed, err := parseRegistrarCSV(string(data))
+		if err != nil {
+			return
+		}
+		registrars, registrarsRefreshed = refreshed, true
+	})
+	return registrars
+}
+
+// RegistrarsRefreshed reports whether the registrar registry was refreshed,
+// rather than being the partial bundled copy
+func RegistrarsRefreshed() bool {
+	loadRegistrars()
+	return registrarsRefreshed
+}
+
+// parseRegistrarCSV reads the IANA registrar ID CSV
+func parseRegistrarCSV(data string) (map[string]Registrar, error) {
+	reader := csv.NewReader(strings.NewReader(strings.TrimPrefix(data, "\ufeff")))
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parsing registrar IDs: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("parsing registrar IDs: empty registry")
+	}
+
+	// Locate columns by header in case IANA reorders or adds them
+	columns := map[string]int{}
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["id"]; !ok {
+		return nil, fmt.Errorf("parsing registrar IDs: missing ID column")
+	}
+
+	get := func(record []string, column string) string {
+		if i, ok := columns[column]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	result := make(map[string]Registrar, len(records)-1)
+	for _, record := range records[1:] {
+		registrar := Registrar{
+			ID:          get(record, "id"),
+			Name:        get(record, "registrar name"),
+			Status:      get(record, "status"),
+			RDAPBaseURL: get(record, "rdap base url"),
+		}
+		if registrar.ID != "" {
+			result[registrar.ID] = registrar
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,57 @@
+package iana
+
+import "testing"
+
+func TestParseRegistrarCSV(t *testing.T) {
+	data := "\ufeffID,Registrar Name,Status,RDAP Base URL\n" +
+		"292,\"MarkMonitor Inc.\",Accredited,https://rdap.markmonitor.com/rdap/\n" +
+		"7,Defunct Registrar,Terminated,\n" +
+		",Missing ID,Accredited,\n"
+
+	registrars, err := parseRegistrarCSV(data)
+	if err != nil {
+		t.Fatalf("parseRegistrarCSV() unexpected error: %v", err)
+	}
+
+	if len(registrars) != 2 {
+		t.Fatalf("Expected 2 registrars, got %d", len(registrars))
+	}
+
+	markMonitor := registrars["292"]
+	if markMonitor.Name != "MarkMonitor Inc." || markMonitor.Status != "Accredited" || markMonitor.RDAPBaseURL != "https://rdap.markmonitor.com/rdap/" {
+		t.Errorf("Unexpected registrar 292: %+v", markMonitor)
+	}
+	if registrars["7"].Status != "Terminated" {
+		t.Errorf("Expected registrar 7 to be terminated, got %+v", registrars["7"])
+	}
+
+	if _, err := parseRegistrarCSV("Name,Status\nfoo,bar\n"); err == nil {
+		t.Error("Expected error for CSV without an ID column")
+	}
+}
+
+func TestBundledRegistrars(t *testing.T) {
+	registrars, err := parseRegistrarCSV(bundledRegistrarIDs)
+	if err != nil {
+		t.Fatalf("bundled registrar IDs do not parse: %v", err)
+	}
+
+	registrar, ok := registrars["376"]
+	if !ok {
+		t.Fatal("Expected IANA reserved registrar 376 in bundled data")
+	}
+	if registrar.Status != "Reserved" {
+		t.Errorf("Expected registrar 376 status Reserved, got %q", registrar.Status)
+	}
+}
+
+func TestSearchRegistrars(t *testing.T) {
+	matches := SearchRegistrars("markmonitor")
+	if len(matches) == 0 || matches[0].ID != "292" {
+		t.Errorf("Expected MarkMonitor (292) to match, got %+v", matches)
+	}
+
+	if matches := SearchRegistrars(""); len(matches) != 0 {
+		t.Errorf("Expected no matches for empty search, got %d", len(matches))
+	}
+}
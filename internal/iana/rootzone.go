@@ -0,0 +1,259 @@
+package iana
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"html"
+	"io"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RootZoneURL is the IANA root zone database, listing every TLD with its type and manager
+const RootZoneURL = "https://www.iana.org/domains/root/db"
+
+const (
+	// rootZoneCacheFile is the name of the refreshed root zone copy in the cache directory
+	rootZoneCacheFile = "root-zone.csv"
+	// rootZoneWhoisServer holds each TLD's WHOIS server, DNSSEC and registration date
+	rootZoneWhoisServer = "whois.iana.org:43"
+	// rootZoneWorkers bounds the concurrent WHOIS queries a refresh makes
+	rootZoneWorkers = 8
+)
+
+// Bundled copy of the IANA root zone database. Every delegated TLD is listed,
+// but only some carry a WHOIS server, DNSSEC status and registration date
+// until go generate replaces it with data from IANA.
+//
+//go:embed data/root-zone.csv
+var bundledRootZone string
+
+// TLD is an entry in the IANA root zone database
+type TLD struct {
+	Name             string
+	Type             string // generic, country-code, sponsored, infrastructure, generic-restricted
+	Manager          string
+	WhoisServer      string
+	DNSSEC           string // signed or unsigned; empty when the bundled copy doesn't record it
+	RegistrationDate time.Time
+}
+
+var (
+	rootZoneOnce sync.Once
+	rootZone     map[string]TLD
+)
+
+// rootZoneRow matches a TLD in the root zone database page: its A-label from
+// the link, then the type and manager columns
+var rootZoneRow = regexp.MustCompile(`(?s)<a href="/domains/root/db/([^"/]+)\.html">.*?</td>\s*<td>([^<]*)</td>\s*<td>([^<]*)</td>`)
+
+// LookupTLD returns the root zone database entry for a TLD
+func LookupTLD(name string) (TLD, bool) {
+	tld, ok := loadRootZone()[NormalizeTLD(name)]
+	return tld, ok
+}
+
+// NormalizeTLD lowercases a TLD and strips surrounding dots
+func NormalizeTLD(name string) string {
+	return strings.ToLower(strings.Trim(strings.TrimSpace(name), "."))
+}
+
+// FetchRootZone downloads the IANA root zone database and asks IANA's WHOIS
+// server for each TLD's WHOIS server, DNSSEC status and registration date,
+// returning the database in the bundled CSV layout
+func FetchRootZone() ([]byte, error) {
+	page, err := download(RootZoneURL, "root zone database")
+	if err != nil {
+		return nil, err
+	}
+	tlds := parseRootZoneHTML(string(page))
+	if len(tlds) == 0 {
+		return nil, fmt.Errorf("parsing root zone database: no TLDs found")
+	}
+
+	// TLDs IANA's WHOIS server doesn't answer for keep their current details
+	current := loadRootZone()
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < rootZoneWorkers; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				tld := &tlds[i]
+				response, err := queryIANAWhois(tld.Name)
+				if err != nil {
+					existing := current[tld.Name]
+					tld.WhoisServer, tld.DNSSEC, tld.RegistrationDate = existing.WhoisServer, existing.DNSSEC, existing.RegistrationDate
+					continue
+				}
+				tld.WhoisServer, tld.DNSSEC, tld.RegistrationDate = parseIANAWhois(response)
+			}
+		}()
+	}
+	for i := range tlds {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return formatRootZoneCSV(tlds)
+}
+
+// RefreshRootZone downloads the current root zone database and RDAP bootstrap
+// file into the cache directory, returning the number of TLDs
+func RefreshRootZone() (int, error) {
+	rootZoneData, err := FetchRootZone()
+	if err != nil {
+		return 0, err
+	}
+	bootstrapData, err := FetchRDAPDNS()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := saveCache(rootZoneCacheFile, rootZoneData); err != nil {
+		return 0, fmt.Errorf("saving root zone database: %w", err)
+	}
+	if err := saveCache(rdapDNSCacheFile, bootstrapData); err != nil {
+		return 0, fmt.Errorf("saving RDAP bootstrap file: %w", err)
+	}
+
+	parsed, _ := parseRootZoneCSV(string(rootZoneData))
+	return len(parsed), nil
+}
+
+// loadRootZone parses the bundled database once, or the refreshed copy from
+// the cache directory when one exists
+func loadRootZone() map[string]TLD {
+	rootZoneOnce.Do(func() {
+		rootZone, _ = parseRootZoneCSV(bundledRootZone)
+		if rootZone == nil {
+			rootZone = map[string]TLD{}
+		}
+
+		data, err := readCache(rootZoneCacheFile)
+		if err != nil {
+			return
+		}
+		if refreshed, err := parseRootZoneCSV(string(data)); err == nil {
+			rootZone = refreshed
+		}
+	})
+	return rootZone
+}
+
+func parseRootZoneCSV(data string) (map[string]TLD, error) {
+	records, err := csv.NewReader(strings.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parsing root zone database: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("parsing root zone database: empty database")
+	}
+
+	result := make(map[string]TLD, len(records)-1)
+	for _, record := range records[1:] {
+		if len(record) < 6 {
+			continue
+		}
+		tld := TLD{
+			Name:        NormalizeTLD(record[0]),
+			Type:        record[1],
+			Manager:     record[2],
+			WhoisServer: record[3],
+			DNSSEC:      record[4],
+		}
+		if date, err := time.Parse("2006-01-02", record[5]); err == nil {
+			tld.RegistrationDate = date
+		}
+		result[tld.Name] = tld
+	}
+
+	return result, nil
+}
+
+func formatRootZoneCSV(tlds []TLD) ([]byte, error) {
+	sort.Slice(tlds, func(i, j int) bool { return tlds[i].Name < tlds[j].Name })
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	_ = writer.Write([]string{"TLD", "Type", "Manager", "WHOIS Server", "DNSSEC", "Registration Date"})
+	for _, tld := range tlds {
+		date := ""
+		if !tld.RegistrationDate.IsZero() {
+			date = tld.RegistrationDate.Format("2006-01-02")
+		}
+		_ = writer.Write([]string{tld.Name, tld.Type, tld.Manager, tld.WhoisServer, tld.DNSSEC, date})
+	}
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
+
+// parseRootZoneHTML reads the delegated TLDs from the root zone database page,
+// skipping retired TLDs, which IANA lists as "Not assigned"
+func parseRootZoneHTML(page string) []TLD {
+	var tlds []TLD
+	for _, match := range rootZoneRow.FindAllStringSubmatch(page, -1) {
+		manager := strings.TrimSpace(html.UnescapeString(match[3]))
+		if manager == "Not assigned" {
+			continue
+		}
+		tlds = append(tlds, TLD{
+			Name:    NormalizeTLD(match[1]),
+			Type:    strings.TrimSpace(match[2]),
+			Manager: manager,
+		})
+	}
+	return tlds
+}
+
+// queryIANAWhois asks IANA's WHOIS server about a TLD
+func queryIANAWhois(name string) (string, error) {
+	conn, err := net.DialTimeout("tcp", rootZoneWhoisServer, downloadTimeout)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(downloadTimeout)); err != nil {
+		return "", err
+	}
+
+	if _, err := io.WriteString(conn, name+"\r\n"); err != nil {
+		return "", err
+	}
+	response, err := io.ReadAll(conn)
+	return string(response), err
+}
+
+// parseIANAWhois reads the WHOIS server, DNSSEC status and creation date from
+// IANA's WHOIS record for a TLD. DS records ("ds-rdata:") mean it is signed.
+func parseIANAWhois(response string) (whoisServer, dnssec string, created time.Time) {
+	dnssec = "unsigned"
+	scanner := bufio.NewScanner(strings.NewReader(response))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "whois":
+			whoisServer = value
+		case "ds-rdata":
+			dnssec = "signed"
+		case "created":
+			if date, err := time.Parse("2006-01-02", value); err == nil {
+				created = date
+			}
+		}
+	}
+	return whoisServer, dnssec, created
+}
@@ -0,0 +1,139 @@
+package iana
+
+import "testing"
+
+func TestLookupTLD(t *testing.T) {
+	tests := []struct {
+		input       string
+		expectFound bool
+		expectType  string
+		expectWhois string
+	}{
+		{"com", true, "generic", "whois.verisign-grs.com"},
+		{".UK", true, "country-code", "whois.nic.uk"},
+		{"arpa.", true, "infrastructure", "whois.iana.org"},
+		{"notatld", false, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			tld, ok := LookupTLD(tt.input)
+			if ok != tt.expectFound {
+				t.Fatalf("LookupTLD(%q) found = %v, want %v", tt.input, ok, tt.expectFound)
+			}
+			if !ok {
+				return
+			}
+			if tld.Type != tt.expectType {
+				t.Errorf("Expected type %q, got %q", tt.expectType, tld.Type)
+			}
+			if tld.WhoisServer != tt.expectWhois {
+				t.Errorf("Expected WHOIS server %q, got %q", tt.expectWhois, tld.WhoisServer)
+			}
+			if tld.RegistrationDate.IsZero() {
+				t.Error("Expected registration date to be parsed")
+			}
+		})
+	}
+}
+
+func TestBootstrapFileLookup(t *testing.T) {
+	file, err := ParseBootstrapFile([]byte(`{
+		"version": "1.0",
+		"services": [
+			[["com", "net"], ["https://rdap.example.com/v1/", "http://rdap.example.com/v1/"]],
+			[["malformed"]]
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("ParseBootstrapFile() unexpected error: %v", err)
+	}
+
+	if urls := file.Lookup("NET"); len(urls) != 2 || urls[0] != "https://rdap.example.com/v1/" {
+		t.Errorf("Expected two URLs for net, got %v", urls)
+	}
+	if urls := file.Lookup("malformed"); urls != nil {
+		t.Errorf("Expected no URLs for malformed service, got %v", urls)
+	}
+
+	if servers := RDAPServersForTLD("com"); len(servers) == 0 {
+		t.Error("Expected bundled bootstrap file to list RDAP servers for com")
+	}
+	if servers := RDAPServersForTLD("notatld"); len(servers) != 0 {
+		t.Errorf("Expected no RDAP servers for notatld, got %v", servers)
+	}
+}
+
+func TestBundledRootZoneCoverage(t *testing.T) {
+	tlds, err := parseRootZoneCSV(bundledRootZone)
+	if err != nil {
+		t.Fatalf("bundled root zone database does not parse: %v", err)
+	}
+	if len(tlds) < 1000 {
+		t.Errorf("Expected the full root zone, got %d TLDs", len(tlds))
+	}
+	for name, expectType := range map[string]string{"de": "country-code", "xn--p1ai": "country-code", "shop": "generic", "museum": "sponsored"} {
+		if tld, ok := tlds[name]; !ok || tld.Type != expectType {
+			t.Errorf("Expected %s to be %s, got %+v", name, expectType, tld)
+		}
+	}
+}
+
+func TestParseRootZoneHTML(t *testing.T) {
+	page := `<table id="tld-table"><tbody>
+<tr>
+    <td>
+        <span class="domain tld"><a href="/domains/root/db/aaa.html">.aaa</a></span></td>
+    <td>generic</td>
+    <td>American Automobile Association, Inc.</td>
+</tr>
+<tr>
+    <td>
+        <span class="domain tld"><a href="/domains/root/db/xn--p1ai.html">.рф</a></span></td>
+    <td>country-code</td>
+    <td>Coordination Center for TLD RU</td>
+</tr>
+<tr>
+    <td>
+        <span class="domain tld"><a href="/domains/root/db/active.html">.active</a></span></td>
+    <td>generic</td>
+    <td>Not assigned</td>
+</tr>
+<tr>
+    <td>
+        <span class="domain tld"><a href="/domains/root/db/att.html">.att</a></span></td>
+    <td>generic</td>
+    <td>AT&amp;T Services, Inc.</td>
+</tr>
+</tbody></table>`
+
+	tlds := parseRootZoneHTML(page)
+	if len(tlds) != 3 {
+		t.Fatalf("Expected 3 delegated TLDs, got %+v", tlds)
+	}
+	if tlds[1].Name != "xn--p1ai" || tlds[1].Type != "country-code" {
+		t.Errorf("Expected the A-label from the link, got %+v", tlds[1])
+	}
+	if tlds[2].Manager != "AT&T Services, Inc." {
+		t.Errorf("Expected HTML entities to be decoded, got %q", tlds[2].Manager)
+	}
+}
+
+func TestParseIANAWhois(t *testing.T) {
+	response := `domain:       UK
+organisation: Nominet UK
+whois:        whois.nic.uk
+status:       ACTIVE
+ds-rdata:     43876 8 2 a107ed2ac1bd14d924173bc7e827a1153582072394f9272ba37e2353bc659603
+created:      1985-07-24
+changed:      2024-01-10
+`
+	whoisServer, dnssec, created := parseIANAWhois(response)
+	if whoisServer != "whois.nic.uk" || dnssec != "signed" || created.Format("2006-01-02") != "1985-07-24" {
+		t.Errorf("parseIANAWhois() = %q, %q, %v", whoisServer, dnssec, created)
+	}
+
+	if _, dnssec, _ := parseIANAWhois("domain: EXAMPLE\nstatus: ACTIVE\n"); dnssec != "unsigned" {
+		t.Errorf("Expected a TLD without DS records to be unsigned, got %q", dnssec)
+	}
+}
@@ -0,0 +1,205 @@
+package iana
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"net/netip"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Bundled snapshots of the IANA IPv4 and IPv6 special-purpose address registries (RFC 6890)
+//
+//go:embed data/iana-ipv4-special-registry.csv
+var bundledIPv4Special string
+
+//go:embed data/iana-ipv6-special-registry.csv
+var bundledIPv6Special string
+
+// Bundled snapshot of the IANA special-purpose AS number registry, plus the
+// reserved 65552-131071 block from the AS number registry
+//
+//go:embed data/iana-as-numbers-special-registry.csv
+var bundledASNSpecial string
+
+// SpecialAddressBlock is an entry in a special-purpose address registry. The
+// flags are nil where the registry gives N/A, as for deprecated blocks.
+type SpecialAddressBlock struct {
+	Prefix             netip.Prefix
+	Name               string
+	RFC                string
+	Allocated          string // YYYY-MM
+	Terminated         string // YYYY-MM, empty while the block is in use
+	Source             *bool  // Valid as a source address
+	Destination        *bool  // Valid as a destination address
+	Forwardable        *bool  // Routers may forward packets to or from it
+	GloballyReachable  *bool  // Valid beyond the administrative domain it is used in
+	ReservedByProtocol *bool  // Special behaviour is built into the protocol
+}
+
+// SpecialASN is a reserved, private-use or documentation AS number range
+type SpecialASN struct {
+	Start  uint32
+	End    uint32
+	Reason string
+	RFC    string
+}
+
+var (
+	specialOnce      sync.Once
+	specialAddresses []SpecialAddressBlock
+	specialASNs      []SpecialASN
+)
+
+// LookupSpecialAddress returns the most specific special-purpose block that
+// wholly contains the prefix. Single addresses are passed as full-length prefixes.
+func LookupSpecialAddress(prefix netip.Prefix) (SpecialAddressBlock, bool) {
+	loadSpecialRegistries()
+
+	var best SpecialAddressBlock
+	found := false
+	for _, block := range specialAddresses {
+		if block.Prefix.Addr().BitLen() != prefix.Addr().BitLen() || block.Prefix.Bits() > prefix.Bits() {
+			continue
+		}
+		if !block.Prefix.Contains(prefix.Addr()) {
+			continue
+		}
+		if !found || block.Prefix.Bits() > best.Prefix.Bits() {
+			best, found = block, true
+		}
+	}
+	return best, found
+}
+
+// LookupSpecialASN returns the special-purpose range an AS number falls in
+func LookupSpecialASN(asn uint32) (SpecialASN, bool) {
+	loadSpecialRegistries()
+
+	for _, entry := range specialASNs {
+		if asn >= entry.Start && asn <= entry.End {
+			return entry, true
+		}
+	}
+	return SpecialASN{}, false
+}
+
+func loadSpecialRegistries() {
+	specialOnce.Do(func() {
+		for _, data := range []string{bundledIPv4Special, bundledIPv6Special} {
+			blocks, _ := parseSpecialAddressCSV(data)
+			specialAddresses = append(specialAddresses, blocks...)
+		}
+		specialASNs, _ = parseSpecialASNCSV(bundledASNSpecial)
+	})
+}
+
+// parseSpecialAddressCSV reads an IANA special-purpose address registry,
+// splitting entries that list several blocks into one entry per block
+func parseSpecialAddressCSV(data string) ([]SpecialAddressBlock, error) {
+	records, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(data, "\ufeff"))).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parsing special-purpose address registry: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("parsing special-purpose address registry: empty registry")
+	}
+
+	var result []SpecialAddressBlock
+	for _, record := range records[1:] {
+		if len(record) < 10 {
+			continue
+		}
+		block := SpecialAddressBlock{
+			Name:               strings.Trim(record[1], `"`),
+			RFC:                formatReference(record[2]),
+			Allocated:          record[3],
+			Source:             registryFlag(record[5]),
+			Destination:        registryFlag(record[6]),
+			Forwardable:        registryFlag(record[7]),
+			GloballyReachable:  registryFlag(record[8]),
+			ReservedByProtocol: registryFlag(record[9]),
+		}
+		if record[4] != "N/A" {
+			block.Terminated = record[4]
+		}
+		for _, field := range strings.Split(record[0], ",") {
+			prefix, err := netip.ParsePrefix(strings.TrimSpace(field))
+			if err != nil {
+				continue
+			}
+			block.Prefix = prefix.Masked()
+			result = append(result, block)
+		}
+	}
+
+	return result, nil
+}
+
+// parseSpecialASNCSV reads the IANA special-purpose AS number registry
+func parseSpecialASNCSV(data string) ([]SpecialASN, error) {
+	records, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(data, "\ufeff"))).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parsing special-purpose AS number registry: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("parsing special-purpose AS number registry: empty registry")
+	}
+
+	var result []SpecialASN
+	for _, record := range records[1:] {
+		if len(record) < 3 {
+			continue
+		}
+		startText, endText, isRange := strings.Cut(record[0], "-")
+		if !isRange {
+			endText = startText
+		}
+		start, err := strconv.ParseUint(strings.TrimSpace(startText), 10, 32)
+		if err != nil {
+			continue
+		}
+		end, err := strconv.ParseUint(strings.TrimSpace(endText), 10, 32)
+		if err != nil {
+			continue
+		}
+		result = append(result, SpecialASN{
+			Start:  uint32(start),
+			End:    uint32(end),
+			Reason: formatReference(record[1]),
+			RFC:    formatReference(record[2]),
+		})
+	}
+
+	return result, nil
+}
+
+// registryFlag reads a True/False registry column, ignoring footnote markers
+func registryFlag(value string) *bool {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return nil
+	}
+	switch strings.ToLower(fields[0]) {
+	case "true":
+		flag := true
+		return &flag
+	case "false":
+		flag := false
+		return &flag
+	}
+	return nil
+}
+
+var rfcReferencePattern = regexp.MustCompile(`\[RFC\s*(\d+)\]`)
+
+// formatReference turns registry references like "[RFC6890][RFC8190], Section 2.1"
+// into "RFC 6890, RFC 8190, Section 2.1"
+func formatReference(reference string) string {
+	reference = rfcReferencePattern.ReplaceAllString(reference, "[RFC $1]")
+	reference = strings.ReplaceAll(reference, "][", ", ")
+	return strings.NewReplacer("[", "", "]", "").Replace(reference)
+}
@@ -0,0 +1,81 @@
+package iana
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func TestLookupSpecialAddress(t *testing.T) {
+	tests := []struct {
+		input       string
+		expectFound bool
+		expectBlock string
+		expectName  string
+		expectRFC   string
+	}{
+		{"10.1.2.3/32", true, "10.0.0.0/8", "Private-Use", "RFC 1918"},
+		{"192.0.0.9/32", true, "192.0.0.9/32", "Port Control Protocol Anycast", "RFC 7723"},
+		{"192.0.0.171/32", true, "192.0.0.171/32", "NAT64/DNS64 Discovery", "RFC 8880, RFC 7050, Section 2.2"},
+		{"0.0.0.0/32", true, "0.0.0.0/32", "This host on this network", "RFC 1122, Section 3.2.1.3"},
+		{"172.16.0.0/16", true, "172.16.0.0/12", "Private-Use", "RFC 1918"},
+		{"2001:db8::1/128", true, "2001:db8::/32", "Documentation", "RFC 3849"},
+		{"fe80::1/128", true, "fe80::/10", "Link-Local Unicast", "RFC 4291"},
+		{"172.0.0.0/8", false, "", "", ""},
+		{"8.8.8.8/32", false, "", "", ""},
+		{"2001:4860::/32", false, "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			block, ok := LookupSpecialAddress(netip.MustParsePrefix(tt.input))
+			if ok != tt.expectFound {
+				t.Fatalf("LookupSpecialAddress(%s) found = %v, want %v", tt.input, ok, tt.expectFound)
+			}
+			if !ok {
+				return
+			}
+			if block.Prefix.String() != tt.expectBlock || block.Name != tt.expectName || block.RFC != tt.expectRFC {
+				t.Errorf("LookupSpecialAddress(%s) = %s %q %q, want %s %q %q", tt.input,
+					block.Prefix, block.Name, block.RFC, tt.expectBlock, tt.expectName, tt.expectRFC)
+			}
+		})
+	}
+}
+
+func TestLookupSpecialAddress_Flags(t *testing.T) {
+	private, _ := LookupSpecialAddress(netip.MustParsePrefix("192.168.1.1/32"))
+	if private.GloballyReachable == nil || *private.GloballyReachable || private.Forwardable == nil || !*private.Forwardable {
+		t.Errorf("192.168.0.0/16 flags = reachable %v, forwardable %v; want false, true", private.GloballyReachable, private.Forwardable)
+	}
+
+	deprecated, _ := LookupSpecialAddress(netip.MustParsePrefix("192.88.99.1/32"))
+	if deprecated.Terminated != "2015-03" || deprecated.GloballyReachable != nil {
+		t.Errorf("192.88.99.0/24 = terminated %q, reachable %v; want 2015-03 and N/A", deprecated.Terminated, deprecated.GloballyReachable)
+	}
+}
+
+func TestLookupSpecialASN(t *testing.T) {
+	tests := []struct {
+		asn          uint32
+		expectFound  bool
+		expectReason string
+	}{
+		{0, true, "Reserved by RFC 7607"},
+		{23456, true, "AS_TRANS"},
+		{64500, true, "For documentation and sample code"},
+		{64512, true, "For private use"},
+		{65535, true, "Reserved"},
+		{100000, true, "Reserved"},
+		{4200000000, true, "For private use"},
+		{4294967295, true, "Reserved"},
+		{15169, false, ""},
+		{131072, false, ""},
+	}
+
+	for _, tt := range tests {
+		entry, ok := LookupSpecialASN(tt.asn)
+		if ok != tt.expectFound || entry.Reason != tt.expectReason {
+			t.Errorf("LookupSpecialASN(%d) = %q, %v; want %q, %v", tt.asn, entry.Reason, ok, tt.expectReason, tt.expectFound)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"regard/internal/domain"
+)
+
+// OutputASSetExpansion renders an as-set resolved to its member AS numbers
+func OutputASSetExpansion(expansion *domain.ASSetExpansion, useColor bool) {
+	colors := newPalette(useColor)
+	bold, yellow, blue := colors.bold, colors.yellow, colors.blue
+
+	printHeader(bold(expansion.Name), "IRR")
+
+	fmt.Printf("\n%s %s\n", bold("Member ASes:"), blue(fmt.Sprintf("(%d)", len(expansion.ASNs))))
+	if len(expansion.ASNs) == 0 {
+		fmt.Printf("  • %s\n", yellow("None"))
+	}
+	for _, line := range wrapWords(expansion.ASNs, getTerminalWidth()-4) {
+		fmt.Printf("  %s\n", line)
+	}
+
+	if len(expansion.Sets) > 0 {
+		fmt.Printf("\n%s %s\n", bold("Nested As-Sets:"), blue(fmt.Sprintf("(%d)", len(expansion.Sets))))
+		for _, line := range wrapWords(expansion.Sets, getTerminalWidth()-4) {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+
+	if len(expansion.Cycles) > 0 {
+		fmt.Printf("\n%s\n", bold("Cycles:"))
+		for _, cycle := range expansion.Cycles {
+			fmt.Printf("  • %s\n", yellow(strings.Join(cycle, " -> ")))
+		}
+	}
+
+	warnings := expansion.Warnings
+	for _, set := range expansion.Missing {
+		warnings = append(warnings, fmt.Sprintf("%s is not in the IRR", set))
+	}
+	if len(warnings) > 0 {
+		fmt.Printf("\n%s\n", bold("Warnings:"))
+		for _, warning := range warnings {
+			fmt.Printf("  • %s\n", yellow(warning))
+		}
+	}
+
+	if expansion.Server != "" {
+		fmt.Printf("\n%s %s\n", bold("Server:"), expansion.Server)
+	}
+}
+
+// wrapWords joins words with ", " into lines no wider than width
+func wrapWords(words []string, width int) []string {
+	var lines []string
+	line := ""
+	for i, word := range words {
+		if i < len(words)-1 {
+			word += ","
+		}
+		if line != "" && len(line)+1+len(word) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,45 @@
+package output
+
+import (
+	"reflect"
+	"testing"
+
+	"regard/internal/domain"
+)
+
+func TestOutputASSetExpansion(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.Errorf("OutputASSetExpansion panicked: %v", r)
+		}
+	}()
+
+	OutputASSetExpansion(&domain.ASSetExpansion{
+		Name:     "AS-EXAMPLE",
+		Server:   "whois.radb.net",
+		ASNs:     []string{"AS64500", "AS64501", "AS64502"},
+		Sets:     []string{"AS-A", "AS-B"},
+		Cycles:   [][]string{{"AS-EXAMPLE", "AS-B", "AS-EXAMPLE"}},
+		Missing:  []string{"AS-GONE"},
+		Warnings: []string{"stopped after expanding 1000 as-sets"},
+	}, true)
+	OutputASSetExpansion(&domain.ASSetExpansion{Name: "AS-EMPTY", ASNs: []string{}}, false)
+}
+
+func TestWrapWords(t *testing.T) {
+	tests := []struct {
+		words    []string
+		width    int
+		expected []string
+	}{
+		{nil, 20, nil},
+		{[]string{"AS1", "AS2", "AS3"}, 80, []string{"AS1, AS2, AS3"}},
+		{[]string{"AS64500", "AS64501", "AS64502"}, 18, []string{"AS64500, AS64501,", "AS64502"}},
+	}
+
+	for _, tt := range tests {
+		if got := wrapWords(tt.words, tt.width); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("wrapWords(%v, %d) = %q, want %q", tt.words, tt.width, got, tt.expected)
+		}
+	}
+}
@@ -31,7 +31,46 @@ func OutputJSON(result query.QueryResult, useColor bool) {
 
 // OutputSummaryJSON renders a domain summary as formatted JSON
 func OutputSummaryJSON(summary domain.Summary, useColor bool) {
-	jsonBytes, err := json.MarshalIndent(summary, "", "  ")
+	outputIndentedJSON(summary, useColor)
+}
+
+// OutputIPSummaryJSON renders an IP network summary as formatted JSON
+func OutputIPSummaryJSON(summary domain.IPSummary, useColor bool) {
+	outputIndentedJSON(summary, useColor)
+}
+
+// OutputNetworkTreeJSON renders a netblock allocation chain as formatted JSON
+func OutputNetworkTreeJSON(tree *domain.NetworkTree, useColor bool) {
+	outputIndentedJSON(tree, useColor)
+}
+
+// OutputSpecialPurposeJSON renders a special-purpose address or ASN as formatted JSON
+func OutputSpecialPurposeJSON(special *domain.SpecialPurpose, useColor bool) {
+	outputIndentedJSON(special, useColor)
+}
+
+// OutputASSetExpansionJSON renders an expanded as-set as formatted JSON
+func OutputASSetExpansionJSON(expansion *domain.ASSetExpansion, useColor bool) {
+	outputIndentedJSON(expansion, useColor)
+}
+
+// OutputRegistrarsJSON renders registrar registry entries as formatted JSON
+func OutputRegistrarsJSON(registrars []domain.RegistrarInfo, useColor bool) {
+	outputIndentedJSON(registrars, useColor)
+}
+
+// OutputErrorJSON renders an error message as a JSON object
+func OutputErrorJSON(message string) {
+	jsonBytes, err := json.Marshal(map[string]string{"error": message})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
+		return
+	}
+	fmt.Println(string(jsonBytes))
+}
+
+func outputIndentedJSON(value interface{}, useColor bool) {
+	jsonBytes, err := json.MarshalIndent(value, "", "  ")
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
 		return
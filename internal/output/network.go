@@ -0,0 +1,262 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"regard/internal/domain"
+	"regard/internal/geo"
+	"regard/internal/rpki"
+)
+
+// OutputIPSummary renders an IP network summary in human-readable format
+func OutputIPSummary(summary domain.IPSummary, useColor bool) {
+	colors := newPalette(useColor)
+	bold, yellow, blue := colors.bold, colors.yellow, colors.blue
+	green, red := colors.green, colors.red
+
+	// Header: query (netname) <spacer> protocol
+	headerLeft := bold(summary.Query)
+	if summary.Name != "" {
+		headerLeft += " " + blue(summary.Name)
+	}
+	printHeader(headerLeft, summary.Protocol)
+
+	fmt.Printf("\n%s\n", bold("Network:"))
+	if summary.StartAddress != "" {
+		fmt.Printf("  • %s: %s - %s\n", bold("Range"), summary.StartAddress, summary.EndAddress)
+	}
+	if len(summary.CIDRs) > 0 {
+		fmt.Printf("  • %s: %s\n", bold("CIDR"), strings.Join(summary.CIDRs, ", "))
+	}
+	if summary.Handle != "" {
+		fmt.Printf("  • %s: %s\n", bold("Handle"), summary.Handle)
+	}
+	if summary.Type != "" {
+		fmt.Printf("  • %s: %s\n", bold("Type"), summary.Type)
+	}
+	if summary.ParentHandle != "" {
+		fmt.Printf("  • %s: %s\n", bold("Parent"), summary.ParentHandle)
+	}
+	if summary.Country != "" {
+		fmt.Printf("  • %s: %s\n", bold("Country"), summary.Country)
+	}
+	if len(summary.Status) > 0 {
+		fmt.Printf("  • %s: %s\n", bold("Status"), strings.Join(summary.Status, ", "))
+	}
+
+	if summary.Organization != "" {
+		fmt.Printf("\n%s %s\n", bold("Organization:"), summary.Organization)
+	}
+	if summary.AbuseContact != "" {
+		fmt.Printf("%s %s\n", bold("Abuse Contact:"), summary.AbuseContact)
+	}
+
+	if summary.Registration != nil || summary.LastUpdated != nil {
+		fmt.Printf("\n%s\n", bold("Timeline:"))
+		if summary.Registration != nil {
+			fmt.Printf("  • %s: %s (%s)\n", bold("Registered"), timelineDate(summary.Registration), blue(summary.Registration.HumanReadable))
+		}
+		if summary.LastUpdated != nil {
+			fmt.Printf("  • %s: %s (%s)\n", bold("Last updated"), timelineDate(summary.LastUpdated), blue(summary.LastUpdated.HumanReadable))
+		}
+	}
+
+	if len(summary.Contacts) > 0 {
+		fmt.Printf("\n%s\n", bold("Contacts:"))
+		for _, contact := range summary.Contacts {
+			label := contact.Organization
+			if label == "" {
+				label = contact.Name
+			}
+			if label == "" {
+				label = contact.Handle
+			}
+			fmt.Printf("  • %s: %s", bold(contactRoleLabel(contact.Role)), label)
+			if contact.Email != "" {
+				fmt.Printf(" <%s>", contact.Email)
+			}
+			fmt.Println()
+		}
+	}
+
+	if summary.Routing != nil {
+		fmt.Printf("\n%s\n", bold("Routing:"))
+		fmt.Printf("  • %s: %s\n", bold("Announced prefix"), summary.Routing.Prefix)
+		origins := make([]string, len(summary.Routing.Origins))
+		for i, asn := range summary.Routing.Origins {
+			origins[i] = fmt.Sprintf("AS%d", asn)
+		}
+		if len(origins) > 1 {
+			fmt.Printf("  • %s: %s %s\n", bold("Origin ASes"), strings.Join(origins, ", "), yellow("(multiple origins)"))
+		} else {
+			fmt.Printf("  • %s: %s\n", bold("Origin AS"), strings.Join(origins, ", "))
+		}
+		fmt.Printf("  • %s: %s\n", bold("Source"), summary.Routing.Source)
+	}
+
+	if summary.RPKI != nil {
+		fmt.Printf("\n%s %s\n", bold("RPKI Origin Validation:"), blue("("+summary.RPKI.Source+")"))
+		for _, route := range summary.RPKI.Routes {
+			fmt.Printf("  • %s: %s\n", bold(fmt.Sprintf("AS%d", route.Origin)), validationText(route, green, red, yellow))
+		}
+	}
+
+	if summary.Geolocation != nil {
+		outputGeolocation(summary.Geolocation, bold, blue)
+	}
+
+	if reverse := summary.ReverseDNS; reverse != nil {
+		fmt.Printf("\n%s %s\n", bold("Reverse DNS:"), blue("(via "+reverse.Resolver+")"))
+		fmt.Printf("  • %s: %s\n", bold("Name"), reverse.Name)
+		switch {
+		case reverse.Error != "":
+			fmt.Printf("  • %s\n", yellow(reverse.Error))
+		case len(reverse.PTR) > 0:
+			fmt.Printf("  • %s: %s\n", bold("PTR"), strings.Join(reverse.PTR, ", "))
+		case len(reverse.Nameservers) > 0:
+			fmt.Printf("  • %s: %s\n", bold("Nameservers"), strings.Join(reverse.Nameservers, ", "))
+		}
+	}
+
+	if len(summary.Warnings) > 0 {
+		fmt.Printf("\n%s\n", bold("Warnings:"))
+		for _, warning := range summary.Warnings {
+			fmt.Printf("  • %s\n", yellow(warning))
+		}
+	}
+}
+
+// outputGeolocation renders local geolocation database data under its own
+// heading, so it isn't mistaken for registry data
+func outputGeolocation(record *geo.Record, bold, blue func(string) string) {
+	fmt.Printf("\n%s %s\n", bold("Geolocation Database:"), blue("(not registry data)"))
+
+	var place []string
+	for _, part := range []string{record.City, record.Region, record.CountryName} {
+		if part != "" {
+			place = append(place, part)
+		}
+	}
+	if len(place) > 0 || record.Country != "" {
+		location := strings.Join(place, ", ")
+		if record.Country != "" {
+			location = strings.TrimSpace(fmt.Sprintf("%s (%s)", location, record.Country))
+		}
+		fmt.Printf("  • %s: %s\n", bold("Location"), location)
+	}
+	if record.Latitude != nil && record.Longitude != nil {
+		fmt.Printf("  • %s: %.4f, %.4f", bold("Coordinates"), *record.Latitude, *record.Longitude)
+		if record.AccuracyRadius > 0 {
+			fmt.Printf(" (within %d km)", record.AccuracyRadius)
+		}
+		fmt.Println()
+	}
+	if record.ASN != 0 {
+		fmt.Printf("  • %s: AS%d %s\n", bold("AS"), record.ASN, record.ASOrganization)
+	}
+	if record.Network != "" {
+		fmt.Printf("  • %s: %s\n", bold("Network"), record.Network)
+	}
+	fmt.Printf("  • %s: %s\n", bold("Source"), strings.Join(record.Sources, ", "))
+}
+
+// validationText renders an RPKI validation state with the VRPs behind it
+func validationText(validation rpki.Validation, green, red, yellow func(string) string) string {
+	vrps := make([]string, len(validation.VRPs))
+	for i, vrp := range validation.VRPs {
+		vrps[i] = vrp.String()
+	}
+	switch validation.State {
+	case rpki.Valid:
+		return fmt.Sprintf("%s (%s)", green("valid"), strings.Join(vrps, ", "))
+	case rpki.Invalid:
+		return fmt.Sprintf("%s, %s (%s)", red("invalid"), validation.Reason, strings.Join(vrps, ", "))
+	}
+	return yellow("not found") + ", no VRP covers the prefix"
+}
+
+// OutputNetworkTree renders the allocation chain as an indented tree
+func OutputNetworkTree(tree *domain.NetworkTree, useColor bool) {
+	colors := newPalette(useColor)
+	bold, yellow, blue := colors.bold, colors.yellow, colors.blue
+
+	printHeader(bold(tree.Query), tree.Protocol)
+	fmt.Println()
+
+	for i, level := range tree.Levels {
+		block := strings.Join(level.CIDRs, ", ")
+		if block == "" {
+			block = fmt.Sprintf("%s - %s", level.StartAddress, level.EndAddress)
+		}
+
+		prefix := ""
+		if i > 0 {
+			prefix = strings.Repeat("   ", i-1) + "└─ "
+		}
+		fmt.Printf("%s%s  %s", prefix, bold(block), level.Holder())
+		if level.Name != "" && level.Name != level.Holder() {
+			fmt.Printf(" (%s)", blue(level.Name))
+		}
+		if level.Type != "" {
+			fmt.Printf(" [%s]", level.Type)
+		}
+		if level.Country != "" {
+			fmt.Printf(" %s", level.Country)
+		}
+		fmt.Println()
+	}
+
+	if len(tree.Warnings) > 0 {
+		fmt.Printf("\n%s\n", bold("Warnings:"))
+		for _, warning := range tree.Warnings {
+			fmt.Printf("  • %s\n", yellow(warning))
+		}
+	}
+}
+
+// OutputSpecialPurpose renders a special-purpose address or ASN answered offline
+func OutputSpecialPurpose(special *domain.SpecialPurpose, useColor bool) {
+	colors := newPalette(useColor)
+	bold, yellow, blue := colors.bold, colors.yellow, colors.blue
+
+	printHeader(bold(special.Query)+" "+blue(special.Name), special.Protocol)
+
+	heading := "Special-Purpose Address:"
+	if special.QueryType == "asn" {
+		heading = "Special-Purpose AS Number:"
+	}
+	fmt.Printf("\n%s\n", bold(heading))
+	fmt.Printf("  • %s: %s\n", bold("Block"), special.Block)
+	fmt.Printf("  • %s: %s\n", bold("Purpose"), special.Name)
+	if special.RFC != "" {
+		fmt.Printf("  • %s: %s\n", bold("Reference"), special.RFC)
+	}
+	if special.Allocated != "" {
+		fmt.Printf("  • %s: %s\n", bold("Allocated"), special.Allocated)
+	}
+	if special.Terminated != "" {
+		fmt.Printf("  • %s: %s\n", bold("Terminated"), yellow(special.Terminated))
+	}
+	fmt.Printf("  • %s: %s\n", bold("Globally reachable"), registryFlagText(special.GloballyReachable))
+	if special.QueryType != "asn" {
+		fmt.Printf("  • %s: %s\n", bold("Forwardable"), registryFlagText(special.Forwardable))
+		fmt.Printf("  • %s: %s\n", bold("Valid source"), registryFlagText(special.Source))
+		fmt.Printf("  • %s: %s\n", bold("Valid destination"), registryFlagText(special.Destination))
+		fmt.Printf("  • %s: %s\n", bold("Reserved by protocol"), registryFlagText(special.ReservedByProtocol))
+	}
+
+	fmt.Printf("\n%s\n", yellow("Answered from the bundled IANA registries; use --lookup to query the registries anyway"))
+}
+
+// registryFlagText renders a True/False/N/A registry column
+func registryFlagText(flag *bool) string {
+	switch {
+	case flag == nil:
+		return "n/a"
+	case *flag:
+		return "yes"
+	default:
+		return "no"
+	}
+}
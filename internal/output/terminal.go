@@ -10,44 +10,23 @@ import (
 	"golang.org/x/term"
 
 	"regard/internal/domain"
+	"regard/internal/rpki"
 )
 
-// OutputSummary renders a domain summary in human-readable format
-func OutputSummary(summary domain.Summary, useColor bool) {
-	// Color functions
-	bold := func(s string) string {
-		if useColor {
-			return fmt.Sprintf("\033[1m%s\033[0m", s)
-		}
-		return s
-	}
-
-	green := func(s string) string {
-		if useColor {
-			return fmt.Sprintf("\033[32m%s\033[0m", s)
-		}
-		return s
-	}
-
-	yellow := func(s string) string {
-		if useColor {
-			return fmt.Sprintf("\033[33m%s\033[0m", s)
-		}
-		return s
-	}
-
-	red := func(s string) string {
-		if useColor {
-			return fmt.Sprintf("\033[31m%s\033[0m", s)
-		}
-		return s
-	}
+// OutputSummary renders a domain summary, with notices and redactions when showNotices is set
+func OutputSummary(summary domain.Summary, useColor bool, showNotices bool) {
+	colors := newPalette(useColor)
+	bold, yellow, blue := colors.bold, colors.yellow, colors.blue
+	green, red := colors.green, colors.red
 
-	blue := func(s string) string {
-		if useColor {
-			return fmt.Sprintf("\033[34m%s\033[0m", s)
+	// expiryColor picks a colour by how close an expiration date is
+	expiryColor := func(event *domain.TimelineEvent) func(string) string {
+		if event.Date.Before(time.Now()) {
+			return red
+		} else if event.Date.Before(time.Now().AddDate(0, 0, 30)) {
+			return yellow
 		}
-		return s
+		return green
 	}
 
 	// Header - compact format: domain (status) <spacer> protocol
@@ -68,22 +47,7 @@ func OutputSummary(summary domain.Summary, useColor bool) {
 		statusText = "AVAILABLE"
 	}
 
-	// Calculate padding for alignment using terminal width
-	headerLeft := fmt.Sprintf("%s %s", bold(summary.Domain), statusColor(statusText))
-	// Strip ANSI codes for length calculation
-	headerLeftStripped := stripAnsiCodes(fmt.Sprintf("%s %s", summary.Domain, statusText))
-
-	// Get terminal width, fallback to 80 if unable to detect
-	termWidth := getTerminalWidth()
-	rightSide := summary.Protocol
-
-	// Calculate padding: total width - left side - right side - 1 space minimum
-	padding := termWidth - len(headerLeftStripped) - len(rightSide) - 1
-	if padding < 1 {
-		padding = 1
-	}
-
-	fmt.Printf("%s%s%s\n", headerLeft, strings.Repeat(" ", padding), rightSide)
+	printHeader(fmt.Sprintf("%s %s", bold(summary.Domain), statusColor(statusText)), summary.Protocol)
 
 	// For available domains, show a celebratory message and skip most sections
 	if summary.Status == "available" {
@@ -92,32 +56,71 @@ func OutputSummary(summary domain.Summary, useColor bool) {
 	}
 
 	// Timeline (only show header if there are timeline entries)
-	hasTimelineEntries := summary.Timeline.Registration != nil || summary.Timeline.LastUpdated != nil || summary.Timeline.Expiration != nil
+	hasTimelineEntries := summary.Timeline.Registration != nil || summary.Timeline.LastUpdated != nil || summary.Timeline.Expiration != nil || summary.Timeline.RegistrarExpiration != nil || len(summary.Timeline.Events) > 0
 	if hasTimelineEntries {
 		fmt.Printf("\n%s\n", bold("Timeline:"))
 		if summary.Timeline.Registration != nil {
 			fmt.Printf("  • %s: %s (%s)\n",
 				bold("Registered"),
-				summary.Timeline.Registration.Date.Format("2006-01-02"),
+				timelineDate(summary.Timeline.Registration),
 				blue(summary.Timeline.Registration.HumanReadable))
 		}
+		lifecycleEvents := []struct {
+			label string
+			event *domain.TimelineEvent
+		}{
+			{"Re-registered", summary.Timeline.Reregistration},
+			{"Transferred", summary.Timeline.Transfer},
+			{"Locked", summary.Timeline.Locked},
+			{"Unlocked", summary.Timeline.Unlocked},
+		}
+		for _, e := range lifecycleEvents {
+			if e.event == nil {
+				continue
+			}
+			fmt.Printf("  • %s: %s (%s)", bold(e.label), timelineDate(e.event), blue(e.event.HumanReadable))
+			if e.event.Actor != "" {
+				fmt.Printf(" by %s", e.event.Actor)
+			}
+			fmt.Println()
+		}
 		if summary.Timeline.LastUpdated != nil {
 			fmt.Printf("  • %s: %s (%s)\n",
 				bold("Last updated"),
-				summary.Timeline.LastUpdated.Date.Format("2006-01-02"),
+				timelineDate(summary.Timeline.LastUpdated),
 				blue(summary.Timeline.LastUpdated.HumanReadable))
 		}
 		if summary.Timeline.Expiration != nil {
-			expiryColor := green
-			if summary.Timeline.Expiration.Date.Before(time.Now()) {
-				expiryColor = red
-			} else if summary.Timeline.Expiration.Date.Before(time.Now().AddDate(0, 0, 30)) {
-				expiryColor = yellow
+			label := "Expires"
+			if summary.Timeline.RegistrarExpiration != nil {
+				label = "Registry expires"
 			}
 			fmt.Printf("  • %s: %s (%s)\n",
-				bold("Expires"),
-				summary.Timeline.Expiration.Date.Format("2006-01-02"),
-				expiryColor(summary.Timeline.Expiration.HumanReadable))
+				bold(label),
+				timelineDate(summary.Timeline.Expiration),
+				expiryColor(summary.Timeline.Expiration)(summary.Timeline.Expiration.HumanReadable))
+		}
+		if summary.Timeline.RegistrarExpiration != nil {
+			fmt.Printf("  • %s: %s (%s)\n",
+				bold("Registrar expires"),
+				timelineDate(summary.Timeline.RegistrarExpiration),
+				expiryColor(summary.Timeline.RegistrarExpiration)(summary.Timeline.RegistrarExpiration.HumanReadable))
+		}
+		if summary.Renewal != nil && summary.Renewal.AutoRenew {
+			fmt.Printf("  • %s: %s\n", bold("Auto-renew"), summary.Renewal.Reason)
+		}
+		// Event actions without a field of their own, such as "deletion"
+		for _, event := range summary.Timeline.Events {
+			if domain.TimelineFieldAction(event.Action) {
+				continue
+			}
+			fmt.Printf("  • %s: %s (%s)\n", bold(event.Action), timelineDate(&event), blue(event.HumanReadable))
+		}
+		if summary.Timeline.DatabaseUpdated != nil {
+			fmt.Printf("  • %s: %s (%s)\n",
+				bold("RDAP data as of"),
+				summary.Timeline.DatabaseUpdated.Date.Format("2006-01-02 15:04 MST"),
+				blue(summary.Timeline.DatabaseUpdated.HumanReadable))
 		}
 	}
 
@@ -125,12 +128,23 @@ func OutputSummary(summary domain.Summary, useColor bool) {
 	if len(summary.Nameservers) > 0 {
 		fmt.Printf("\n%s\n", bold("Nameservers:"))
 		for _, ns := range summary.Nameservers {
-			fmt.Printf("  • %s\n", ns)
+			fmt.Printf("  • %s", ns.Name)
+			if glue := append(append([]string{}, ns.IPv4...), ns.IPv6...); len(glue) > 0 {
+				fmt.Printf(" (%s)", strings.Join(glue, ", "))
+			}
+			if ns.InBailiwick && len(ns.IPv4)+len(ns.IPv6) == 0 {
+				fmt.Printf(" %s", yellow("in-bailiwick without glue"))
+			}
+			if len(ns.Status) > 0 {
+				fmt.Printf(" [%s]", strings.Join(ns.Status, ", "))
+			}
+			fmt.Println()
 		}
 	}
 
-	// DNSSEC (only show for domains, not ASNs or IPs)
-	if summary.QueryType == "domain" {
+	// DNSSEC (only show for domains and TLDs, not ASNs or IPs)
+	// TLDs missing from the bundled root zone copy have no DNSSEC status
+	if summary.QueryType == "domain" || (summary.QueryType == "tld" && summary.DNSSEC.Details != "") {
 		fmt.Printf("\n%s ", bold("DNSSEC:"))
 		if summary.DNSSEC.Enabled {
 			fmt.Printf("%s", green("enabled"))
@@ -141,15 +155,119 @@ func OutputSummary(summary domain.Summary, useColor bool) {
 			fmt.Printf("%s", red("disabled"))
 		}
 		fmt.Println()
+		for _, ds := range summary.DNSSEC.DSRecords {
+			fmt.Printf("  • DS %d %s %s %s\n", ds.KeyTag,
+				dnssecName(ds.AlgorithmName, ds.Algorithm), dnssecName(ds.DigestTypeName, ds.DigestType), ds.Digest)
+		}
+		for _, key := range summary.DNSSEC.Keys {
+			fmt.Printf("  • DNSKEY %d %d %s\n", key.Flags, key.Protocol, dnssecName(key.AlgorithmName, key.Algorithm))
+		}
+		if summary.DNSSEC.MaxSigLife > 0 {
+			fmt.Printf("  • Max signature life: %s\n", time.Duration(summary.DNSSEC.MaxSigLife)*time.Second)
+		}
+		for _, issue := range summary.DNSSEC.Issues {
+			fmt.Printf("  • %s\n", yellow(issue))
+		}
+	}
+
+	// Live DNS comparison
+	if summary.DNSCheck != nil {
+		outputDNSCheck(summary.DNSCheck, bold, green, yellow, red)
+	}
+
+	// Address block a reverse zone covers
+	if summary.ReverseZone != nil {
+		fmt.Printf("\n%s\n", bold("Reverse Zone:"))
+		fmt.Printf("  • %s: %s\n", bold("Zone"), summary.ReverseZone.Zone)
+		fmt.Printf("  • %s: %s\n", bold("Covers"), summary.ReverseZone.Prefix)
+		if summary.ReverseZone.Network != "" {
+			fmt.Printf("  • %s: %s\n", bold("Network"), summary.ReverseZone.Network)
+		}
+	}
+
+	// TLD registry details
+	if summary.TLD != nil {
+		if summary.TLD.Manager != "" {
+			fmt.Printf("\n%s %s", bold("Registry operator:"), summary.TLD.Manager)
+			if summary.TLD.Type != "" {
+				fmt.Printf(" (%s)", summary.TLD.Type)
+			}
+			fmt.Println()
+		} else if summary.TLD.Type != "" {
+			fmt.Printf("\n%s %s\n", bold("Type:"), summary.TLD.Type)
+		}
+		if summary.TLD.WhoisServer != "" {
+			fmt.Printf("%s %s\n", bold("WHOIS server:"), summary.TLD.WhoisServer)
+		}
+		fmt.Printf("%s ", bold("RDAP:"))
+		if summary.TLD.HasRDAP {
+			fmt.Printf("%s (%s)\n", green("available"), strings.Join(summary.TLD.RDAPServers, ", "))
+		} else if summary.TLD.RDAPUnknown {
+			fmt.Printf("%s\n", yellow("unknown, not in the partial bundled bootstrap file"))
+		} else {
+			fmt.Printf("%s\n", yellow("not in the bootstrap file"))
+		}
+
+		if summary.TLD.Lifecycle != nil {
+			lifecycle := summary.TLD.Lifecycle
+			fmt.Printf("\n%s\n", bold("Expiration lifecycle:"))
+			fmt.Printf("  • Renewal grace: %d days\n", lifecycle.RenewalGraceDays)
+			if lifecycle.RedemptionDays > 0 {
+				fmt.Printf("  • Redemption: %d days\n", lifecycle.RedemptionDays)
+			}
+			if lifecycle.PendingDeleteDays > 0 {
+				fmt.Printf("  • Pending delete: %d days\n", lifecycle.PendingDeleteDays)
+			}
+			if lifecycle.DropDays() > 0 {
+				fmt.Printf("  • Drops about %d days after expiry\n", lifecycle.DropDays())
+			}
+			if lifecycle.Notes != "" {
+				fmt.Printf("  %s\n", lifecycle.Notes)
+			}
+		}
 	}
 
 	// Registrar
 	if summary.Registrar.Name != "" {
-		fmt.Printf("\n%s %s", bold("Registrar:"), summary.Registrar.Name)
-		if summary.Registrar.ID != "" {
-			fmt.Printf(" (ID: %s)", summary.Registrar.ID)
-		}
 		fmt.Println()
+		outputRegistrar(summary.Registrar, bold, green, red)
+	}
+
+	// Contacts
+	if len(summary.Contacts) > 0 {
+		fmt.Printf("\n%s\n", bold("Contacts:"))
+		for _, contact := range summary.Contacts {
+			label := contact.Name
+			if contact.Organization != "" {
+				if label != "" {
+					label = fmt.Sprintf("%s, %s", label, contact.Organization)
+				} else {
+					label = contact.Organization
+				}
+			}
+			if label == "" {
+				label = contact.Handle
+			}
+			if label == "" && len(contact.Redacted) > 0 {
+				label = yellow("redacted")
+			}
+			fmt.Printf("  • %s: %s\n", bold(contactRoleLabel(contact.Role)), label)
+			if contact.Email != "" {
+				fmt.Printf("      Email: %s\n", contact.Email)
+			}
+			if contact.Phone != "" {
+				fmt.Printf("      Phone: %s\n", contact.Phone)
+			}
+			if len(contact.Address) > 0 {
+				fmt.Printf("      Address: %s\n", strings.Join(contact.Address, ", "))
+			}
+			if contact.Country != "" {
+				fmt.Printf("      Country: %s\n", contact.Country)
+			}
+			if len(contact.Redacted) > 0 {
+				fmt.Printf("      %s %s\n", yellow("Redacted:"), strings.Join(contact.Redacted, ", "))
+			}
+		}
 	}
 
 	// ASN Information
@@ -164,6 +282,19 @@ func OutputSummary(summary domain.Summary, useColor bool) {
 			fmt.Printf("%s %s\n", bold("AS Name:"), summary.ASN.Name)
 		}
 
+		// The registration can cover a block of AS numbers
+		if summary.ASN.StartAutnum != summary.ASN.EndAutnum {
+			fmt.Printf("%s %s\n", bold("AS Range:"), summary.ASN.Range())
+		}
+
+		if summary.ASN.Handle != "" {
+			fmt.Printf("%s %s\n", bold("Handle:"), summary.ASN.Handle)
+		}
+
+		if summary.ASN.Type != "" {
+			fmt.Printf("%s %s\n", bold("Type:"), summary.ASN.Type)
+		}
+
 		if summary.ASN.AbuseContact != "" {
 			fmt.Printf("%s %s\n", bold("Abuse Contact:"), summary.ASN.AbuseContact)
 		}
@@ -182,6 +313,80 @@ func OutputSummary(summary domain.Summary, useColor bool) {
 				fmt.Printf("  ... and %d more\n", len(summary.ASN.Peers)-10)
 			}
 		}
+
+		if len(summary.ASN.ASSets) > 0 {
+			fmt.Printf("\n%s\n", bold("AS-Sets:"))
+			for _, set := range summary.ASN.ASSets {
+				fmt.Printf("  • %s", set)
+				for _, expansion := range summary.ASN.ASSetExpansions {
+					if expansion.Name != set {
+						continue
+					}
+					fmt.Printf(" %s", blue(fmt.Sprintf("(%d ASes, %d nested sets)", len(expansion.ASNs), len(expansion.Sets))))
+					if len(expansion.Cycles) > 0 {
+						fmt.Printf(" %s", yellow(fmt.Sprintf("%d cycles", len(expansion.Cycles))))
+					}
+				}
+				fmt.Println()
+			}
+		}
+
+		if summary.ASN.IRRServer != "" {
+			fmt.Printf("\n%s %s\n", bold("IRR Route Objects:"), blue("("+summary.ASN.IRRServer+")"))
+			if len(summary.ASN.IRRRoutes) == 0 {
+				fmt.Printf("  • %s\n", yellow("None registered with this origin"))
+			}
+			maxRoutes := 10
+			if len(summary.ASN.IRRRoutes) < maxRoutes {
+				maxRoutes = len(summary.ASN.IRRRoutes)
+			}
+			for i := 0; i < maxRoutes; i++ {
+				fmt.Printf("  • %s\n", summary.ASN.IRRRoutes[i])
+			}
+			if len(summary.ASN.IRRRoutes) > 10 {
+				fmt.Printf("  ... and %d more\n", len(summary.ASN.IRRRoutes)-10)
+			}
+		}
+
+		if summary.ASN.RoutingTable != "" {
+			fmt.Printf("\n%s %s\n", bold("Announced Prefixes:"), blue("("+summary.ASN.RoutingTable+")"))
+			if len(summary.ASN.Prefixes) == 0 {
+				fmt.Printf("  • %s\n", yellow("None originated by this AS"))
+			}
+			// Validated routes carry their own prefix, so they are listed as is
+			// rather than matched to Prefixes by position
+			lines := summary.ASN.Prefixes
+			if summary.ASN.RPKI != nil {
+				lines = make([]string, 0, len(summary.ASN.RPKI.Routes))
+				for _, route := range summary.ASN.RPKI.Routes {
+					lines = append(lines, fmt.Sprintf("%s  %s", route.Prefix, validationText(route, green, red, yellow)))
+				}
+			}
+			maxPrefixes := 10
+			if len(lines) < maxPrefixes {
+				maxPrefixes = len(lines)
+			}
+			for _, line := range lines[:maxPrefixes] {
+				fmt.Printf("  • %s\n", line)
+			}
+			if len(lines) > 10 {
+				fmt.Printf("  ... and %d more\n", len(lines)-10)
+			}
+
+			if validity := summary.ASN.RPKI; validity != nil && len(validity.Routes) > 0 {
+				fmt.Printf("\n%s %s, %s, %s %s\n", bold("RPKI Origin Validation:"),
+					green(fmt.Sprintf("%d valid", validity.Count(rpki.Valid))),
+					red(fmt.Sprintf("%d invalid", validity.Count(rpki.Invalid))),
+					yellow(fmt.Sprintf("%d not found", validity.Count(rpki.NotFound))),
+					blue("("+validity.Source+")"))
+				// Invalid routes beyond the listed prefixes are still worth naming
+				for i, route := range validity.Routes {
+					if i >= maxPrefixes && route.State == rpki.Invalid {
+						fmt.Printf("  • %s  %s\n", route.Prefix, validationText(route, green, red, yellow))
+					}
+				}
+			}
+		}
 	}
 
 	// Domain status details
@@ -192,11 +397,224 @@ func OutputSummary(summary domain.Summary, useColor bool) {
 		}
 	}
 
+	// Transfer eligibility
+	if summary.Transfer != nil {
+		transferColor := green
+		if !summary.Transfer.Eligible {
+			transferColor = yellow
+		}
+		fmt.Printf("\n%s %s\n", bold("Transfer:"), transferColor(summary.Transfer.Message))
+		for _, reason := range summary.Transfer.Reasons {
+			fmt.Printf("  • %s\n", reason)
+		}
+	}
+
 	// Post-expiration guidance
 	if summary.PostExpiration != nil {
 		fmt.Printf("\n%s\n", bold("Post-expiration guidance:"))
 		fmt.Printf("  %s\n", summary.PostExpiration.GuidanceMessage)
 	}
+
+	// Data that couldn't be interpreted
+	if len(summary.Warnings) > 0 {
+		fmt.Printf("\n%s\n", bold("Warnings:"))
+		for _, warning := range summary.Warnings {
+			fmt.Printf("  • %s\n", yellow(warning))
+		}
+	}
+
+	if showNotices {
+		outputNotices(summary, bold, yellow, blue)
+	}
+}
+
+// outputDNSCheck renders the live DNS comparison
+func outputDNSCheck(check *domain.DNSCheck, bold, green, yellow, red func(string) string) {
+	fmt.Printf("\n%s (resolver %s)\n", bold("DNS check:"), check.Resolver)
+	for _, ns := range check.Nameservers {
+		state := green("ok")
+		switch {
+		case ns.Lame:
+			state = red("lame")
+		case !ns.InRegistry:
+			state = yellow("not in registry")
+		case !ns.InDNS:
+			state = yellow("not in DNS")
+		}
+		fmt.Printf("  • %s %s", ns.Name, state)
+		if len(ns.Addresses) > 0 {
+			fmt.Printf(" (%s)", strings.Join(ns.Addresses, ", "))
+		}
+		if len(ns.Unreachable) > 0 {
+			fmt.Printf(" %s", yellow("unchecked, no route from here: "+strings.Join(ns.Unreachable, ", ")))
+		}
+		fmt.Println()
+	}
+	for _, key := range check.DNSKeys {
+		fmt.Printf("  • DNSKEY %d %d %s\n", key.KeyTag, key.Flags, dnssecName(key.AlgorithmName, key.Algorithm))
+	}
+	if len(check.Mismatches) == 0 {
+		fmt.Printf("  %s\n", green("Live DNS matches the registry delegation"))
+		return
+	}
+	for _, mismatch := range check.Mismatches {
+		fmt.Printf("  • %s\n", red(mismatch))
+	}
+}
+
+// dnssecName shows a DNSSEC algorithm or digest type by name, with its number
+func dnssecName(name string, number int) string {
+	if name == "" {
+		return fmt.Sprintf("%d", number)
+	}
+	return fmt.Sprintf("%s(%d)", name, number)
+}
+
+// timelineDate formats a timeline date, marking dates that are only an upper bound
+func timelineDate(event *domain.TimelineEvent) string {
+	if event.Approximate {
+		return "before " + event.Date.Format("2006-01-02")
+	}
+	return event.Date.Format("2006-01-02")
+}
+
+// outputNotices renders the redacted fields, notices and remarks sections
+func outputNotices(summary domain.Summary, bold, yellow, blue func(string) string) {
+	if len(summary.Redactions) > 0 {
+		fmt.Printf("\n%s\n", bold("Redacted fields:"))
+		for _, redaction := range summary.Redactions {
+			fmt.Printf("  • %s", yellow(redaction.Field))
+			if redaction.Method != "" {
+				fmt.Printf(" (%s)", redaction.Method)
+			}
+			if redaction.Reason != "" {
+				fmt.Printf(": %s", redaction.Reason)
+			}
+			fmt.Println()
+		}
+	}
+
+	printNotices := func(title string, notices []domain.Notice) {
+		if len(notices) == 0 {
+			return
+		}
+		fmt.Printf("\n%s\n", bold(title))
+		for _, notice := range notices {
+			heading := notice.Title
+			if heading == "" {
+				heading = notice.Type
+			}
+			if heading != "" {
+				fmt.Printf("  • %s\n", bold(heading))
+			} else {
+				fmt.Printf("  •\n")
+			}
+			for _, line := range notice.Description {
+				fmt.Printf("      %s\n", line)
+			}
+			for _, link := range notice.Links {
+				fmt.Printf("      %s\n", blue(link))
+			}
+		}
+	}
+
+	printNotices("Notices:", summary.Notices)
+	printNotices("Remarks:", summary.Remarks)
+}
+
+// OutputRegistrars renders registrar registry entries in human-readable format
+func OutputRegistrars(registrars []domain.RegistrarInfo, useColor bool) {
+	colors := newPalette(useColor)
+	for i, registrar := range registrars {
+		if i > 0 {
+			fmt.Println()
+		}
+		outputRegistrar(registrar, colors.bold, colors.green, colors.red)
+	}
+}
+
+// outputRegistrar renders the registrar line and any IANA registry details
+func outputRegistrar(registrar domain.RegistrarInfo, bold, green, red func(string) string) {
+	fmt.Printf("%s %s", bold("Registrar:"), registrar.Name)
+	if registrar.ID != "" {
+		fmt.Printf(" (ID: %s)", registrar.ID)
+	}
+	fmt.Println()
+
+	if registrar.CanonicalName != "" && registrar.CanonicalName != registrar.Name {
+		fmt.Printf("  IANA name: %s\n", registrar.CanonicalName)
+	}
+	if registrar.IANAID != "" && registrar.IANAID != registrar.ID {
+		fmt.Printf("  IANA ID: %s\n", registrar.IANAID)
+	}
+	if registrar.Status != "" {
+		statusColor := green
+		if registrar.Status == "terminated" {
+			statusColor = red
+		}
+		fmt.Printf("  Status: %s\n", statusColor(registrar.Status))
+	}
+	if registrar.RDAPBaseURL != "" {
+		fmt.Printf("  RDAP: %s\n", registrar.RDAPBaseURL)
+	}
+	if registrar.AbuseEmail != "" || registrar.AbusePhone != "" {
+		abuse := []string{}
+		if registrar.AbuseEmail != "" {
+			abuse = append(abuse, registrar.AbuseEmail)
+		}
+		if registrar.AbusePhone != "" {
+			abuse = append(abuse, registrar.AbusePhone)
+		}
+		fmt.Printf("  Abuse: %s\n", strings.Join(abuse, ", "))
+	}
+}
+
+// contactRoleLabel converts an RDAP contact role into a display label
+func contactRoleLabel(role string) string {
+	switch role {
+	case "administrative":
+		return "Admin"
+	case "technical":
+		return "Tech"
+	case "":
+		return "Contact"
+	}
+	return strings.ToUpper(role[:1]) + role[1:]
+}
+
+// palette holds the colour functions the renderers share; each is a no-op
+// when colour is off
+type palette struct {
+	bold, green, yellow, red, blue func(string) string
+}
+
+// newPalette builds the shared colour functions
+func newPalette(useColor bool) palette {
+	colorize := func(code string) func(string) string {
+		return func(s string) string {
+			if useColor {
+				return fmt.Sprintf("\033[%sm%s\033[0m", code, s)
+			}
+			return s
+		}
+	}
+	return palette{
+		bold:   colorize("1"),
+		green:  colorize("32"),
+		yellow: colorize("33"),
+		red:    colorize("31"),
+		blue:   colorize("34"),
+	}
+}
+
+// printHeader prints left, which may be coloured, and right at either edge
+// of the terminal
+func printHeader(left, right string) {
+	padding := getTerminalWidth() - len(stripAnsiCodes(left)) - len(right) - 1
+	if padding < 1 {
+		padding = 1
+	}
+	fmt.Printf("%s%s%s\n", left, strings.Repeat(" ", padding), right)
 }
 
 func stripAnsiCodes(s string) string {
@@ -1,10 +1,14 @@
 package output
 
 import (
+	"net/netip"
 	"testing"
 	"time"
 
 	"regard/internal/domain"
+	"regard/internal/geo"
+	"regard/internal/routing"
+	"regard/internal/rpki"
 )
 
 func TestStripAnsiCodes(t *testing.T) {
@@ -88,16 +92,43 @@ func TestOutputSummary_Structure(t *testing.T) {
 						HumanReadable: "in 1 year",
 					},
 				},
-				Nameservers: []string{"ns1.example.com", "ns2.example.com"},
+				Nameservers: []domain.Nameserver{
+					{Name: "ns1.example.com", IPv4: []string{"192.0.2.1"}, IPv6: []string{"2001:db8::1"}, InBailiwick: true},
+					{Name: "ns2.example.net", Status: []string{"active"}},
+				},
 				DNSSEC: domain.DNSSECInfo{
 					Enabled: true,
 					Details: "signed",
+					DSRecords: []domain.DSRecord{
+						{KeyTag: 370, Algorithm: 13, AlgorithmName: "ECDSAP256SHA256", DigestType: 2, DigestTypeName: "SHA-256", Digest: "BE74359954660069D5C63D200C39F5603827D7DD02B56F120EE9F3A86764247C"},
+						{KeyTag: 31589, Algorithm: 5, AlgorithmName: "RSASHA1", DigestType: 1, DigestTypeName: "SHA-1", Digest: "3490A6806D47F17A34C29E2CE80E8A999FFBE4BE", Deprecated: true},
+					},
+					Keys:       []domain.DNSKey{{Flags: 257, Protocol: 3, Algorithm: 99, PublicKey: "AwEAAQ=="}},
+					MaxSigLife: 604800,
+					Issues:     []string{"DS record 31589 uses deprecated algorithm RSASHA1 (5)"},
 				},
 				Registrar: domain.RegistrarInfo{
 					Name: "Test Registrar",
 					ID:   "123",
 				},
+				Contacts: []domain.Contact{
+					{Role: "registrant", Organization: "Example Org", Country: "GB", Redacted: []string{"name", "email"}},
+					{Role: "abuse", Email: "abuse@example.com", Phone: "+1.5555551234"},
+					{Role: "technical", Redacted: []string{"name"}},
+				},
+				Redactions: []domain.Redaction{
+					{Field: "Registrant Name", Method: "removal", Reason: "Server policy"},
+				},
+				Notices: []domain.Notice{
+					{Title: "Terms of Use", Description: []string{"Service subject to Terms of Use."}, Links: []string{"https://example.com/terms"}},
+					{Description: []string{"Untitled notice"}},
+				},
 				StatusDetails: []string{"active", "clientTransferProhibited"},
+				Transfer: &domain.TransferInfo{
+					Message:       "Not transferable until the locks below are removed",
+					RegistrarLock: true,
+					Reasons:       []string{"Registrar lock (clientTransferProhibited): ask the current registrar to unlock the domain"},
+				},
 			},
 		},
 		{
@@ -126,6 +157,212 @@ func TestOutputSummary_Structure(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "TLD summary",
+			summary: domain.Summary{
+				Domain:    "com",
+				Status:    "delegated",
+				Protocol:  "IANA",
+				QueryType: "tld",
+				DNSSEC:    domain.DNSSECInfo{Enabled: true},
+				TLD: &domain.TLDInfo{
+					Name:        "com",
+					Type:        "generic",
+					Manager:     "VeriSign Global Registry Services",
+					WhoisServer: "whois.verisign-grs.com",
+					HasRDAP:     true,
+					RDAPServers: []string{"https://rdap.verisign.com/com/v1/"},
+					Lifecycle:   &domain.LifecyclePolicy{RenewalGraceDays: 30, RedemptionDays: 45, PendingDeleteDays: 5},
+				},
+			},
+		},
+		{
+			name: "Approximate date and warnings",
+			summary: domain.Summary{
+				Domain:   "example.co.uk",
+				Status:   "active",
+				Protocol: "WHOIS",
+				Timeline: domain.Timeline{
+					Registration: &domain.TimelineEvent{
+						Date:          time.Date(1996, 8, 1, 0, 0, 0, 0, time.UTC),
+						HumanReadable: "30 years ago",
+						Approximate:   true,
+					},
+				},
+				Warnings: []string{`Could not parse expiration date "sometime soon"`},
+			},
+		},
+		{
+			name: "Registry and registrar expiration",
+			summary: domain.Summary{
+				Domain:   "renewed.example",
+				Status:   "active",
+				Protocol: "WHOIS",
+				Timeline: domain.Timeline{
+					Expiration: &domain.TimelineEvent{
+						Date:          now.Add(360 * 24 * time.Hour),
+						HumanReadable: "in 11 months",
+					},
+					RegistrarExpiration: &domain.TimelineEvent{
+						Date:          now.Add(-5 * 24 * time.Hour),
+						HumanReadable: "5 days ago",
+					},
+				},
+				Renewal: &domain.RenewalInfo{
+					AutoRenew:      true,
+					Reason:         "Registry has auto-renewed the domain",
+					DivergenceDays: 365,
+				},
+				Warnings: []string{"Registry and registrar expiration dates differ by 365 days"},
+			},
+		},
+		{
+			name: "RDAP lifecycle events",
+			summary: domain.Summary{
+				Domain:   "example.com",
+				Status:   "active",
+				Protocol: "RDAP",
+				Timeline: domain.Timeline{
+					Transfer:        &domain.TimelineEvent{Date: now.Add(-90 * 24 * time.Hour), HumanReadable: "3 months ago", Actor: "New Registrar"},
+					Locked:          &domain.TimelineEvent{Date: now.Add(-89 * 24 * time.Hour), HumanReadable: "3 months ago"},
+					DatabaseUpdated: &domain.TimelineEvent{Date: now, HumanReadable: "today"},
+					Events: []domain.TimelineEvent{
+						{Date: now.Add(-90 * 24 * time.Hour), HumanReadable: "3 months ago", Action: "transfer", Actor: "New Registrar"},
+						{Date: now.Add(-89 * 24 * time.Hour), HumanReadable: "3 months ago", Action: "locked"},
+						{Date: now.Add(30 * 24 * time.Hour), HumanReadable: "in 1 month", Action: "enum validation expiration"},
+						{Date: now, HumanReadable: "today", Action: "last update of RDAP database"},
+					},
+				},
+			},
+		},
+		{
+			name: "Live DNS check",
+			summary: domain.Summary{
+				Domain:      "example.com",
+				Status:      "active",
+				Protocol:    "RDAP",
+				QueryType:   "domain",
+				Nameservers: []domain.Nameserver{{Name: "ns1.example.com"}, {Name: "ns2.example.com"}},
+				DNSCheck: &domain.DNSCheck{
+					Resolver: "127.0.0.1:53",
+					Nameservers: []domain.LiveNameserver{
+						{Name: "ns1.example.com", Addresses: []string{"192.0.2.1"}, InRegistry: true, InDNS: true},
+						{Name: "ns2.example.com", InRegistry: true, Lame: true},
+						{Name: "ns3.example.com", Addresses: []string{"192.0.2.3"}, InDNS: true},
+					},
+					DNSKeys:    []domain.DNSKey{{KeyTag: 370, Flags: 257, Protocol: 3, Algorithm: 13, AlgorithmName: "ECDSAP256SHA256"}},
+					Mismatches: []string{"ns2.example.com does not resolve to an address"},
+				},
+			},
+		},
+		{
+			name: "RDAP ASN block",
+			summary: domain.Summary{
+				Domain:    "AS64512",
+				Status:    "active",
+				Protocol:  "RDAP",
+				QueryType: "asn",
+				Timeline: domain.Timeline{
+					Registration: &domain.TimelineEvent{Date: now.AddDate(-10, 0, 0), HumanReadable: "10 years ago"},
+				},
+				ASN: &domain.ASNInfo{
+					Number:       "AS64512",
+					Handle:       "AS64512",
+					StartAutnum:  64512,
+					EndAutnum:    65534,
+					Name:         "IANA-ASBLOCK",
+					Type:         "RESERVED",
+					Organization: "Internet Assigned Numbers Authority",
+				},
+			},
+		},
+		{
+			name: "ASN with announced prefixes",
+			summary: domain.Summary{
+				Domain:    "AS15169",
+				Status:    "active",
+				Protocol:  "RDAP",
+				QueryType: "asn",
+				ASN: &domain.ASNInfo{
+					Number:       "AS15169",
+					Name:         "GOOGLE",
+					Organization: "Google LLC",
+					Prefixes:     []string{"8.8.4.0/24", "8.8.8.0/24", "2001:4860::/32"},
+					RoutingTable: "rib.20251014.0000.bz2",
+				},
+			},
+		},
+		{
+			name: "ASN with expanded as-sets",
+			summary: domain.Summary{
+				Domain:    "AS64500",
+				Status:    "active",
+				Protocol:  "RDAP",
+				QueryType: "asn",
+				ASN: &domain.ASNInfo{
+					Number:       "AS64500",
+					Name:         "EXAMPLE-AS",
+					Organization: "Example Networks",
+					Peers:        []string{"AS64501"},
+					ASSets:       []string{"AS-EXAMPLE", "AS64500:AS-CUSTOMERS"},
+					IRRServer:    "whois.radb.net",
+					IRRRoutes:    []string{"192.0.2.0/24", "2001:db8::/32"},
+					ASSetExpansions: []domain.ASSetExpansion{
+						{Name: "AS-EXAMPLE", ASNs: []string{"AS64500", "AS64501"}, Sets: []string{"AS-A"}, Cycles: [][]string{{"AS-EXAMPLE", "AS-A", "AS-EXAMPLE"}}},
+					},
+				},
+			},
+		},
+		{
+			name: "ASN with validated prefixes",
+			summary: domain.Summary{
+				Domain:    "AS15169",
+				Status:    "active",
+				Protocol:  "RDAP",
+				QueryType: "asn",
+				ASN: &domain.ASNInfo{
+					Number:       "AS15169",
+					Name:         "GOOGLE",
+					Organization: "Google LLC",
+					Prefixes:     []string{"8.8.8.0/24", "8.8.8.128/25"},
+					RoutingTable: "rib.20251014.0000.bz2",
+					RPKI: &domain.RouteValidity{Source: "vrps.json", Routes: []rpki.Validation{
+						{Prefix: netip.MustParsePrefix("8.8.8.0/24"), Origin: 15169, State: rpki.Valid,
+							VRPs: []rpki.VRP{{Prefix: netip.MustParsePrefix("8.8.8.0/24"), MaxLength: 24, ASN: 15169}}},
+						{Prefix: netip.MustParsePrefix("8.8.8.128/25"), Origin: 15169, State: rpki.Invalid, Reason: rpki.ReasonMaxLength,
+							VRPs: []rpki.VRP{{Prefix: netip.MustParsePrefix("8.8.8.0/24"), MaxLength: 24, ASN: 15169}}},
+					}},
+				},
+			},
+		},
+		{
+			name: "ASN with a prefix validation skipped",
+			summary: domain.Summary{
+				Domain:    "AS15169",
+				Status:    "active",
+				Protocol:  "RDAP",
+				QueryType: "asn",
+				ASN: &domain.ASNInfo{
+					Number:       "AS15169",
+					Prefixes:     []string{"8.8.8.0/33", "8.8.4.0/24"},
+					RoutingTable: "rib.20251014.0000.bz2",
+					RPKI: &domain.RouteValidity{Source: "vrps.json", Routes: []rpki.Validation{
+						{Prefix: netip.MustParsePrefix("8.8.4.0/24"), Origin: 15169, State: rpki.NotFound},
+					}},
+				},
+			},
+		},
+		{
+			name: "Reverse zone",
+			summary: domain.Summary{
+				Domain:      "2.0.192.in-addr.arpa",
+				Status:      "delegated",
+				Protocol:    "RDAP",
+				QueryType:   "reverse",
+				Nameservers: []domain.Nameserver{{Name: "ns1.example.net"}},
+				ReverseZone: &domain.ReverseZone{Zone: "2.0.192.in-addr.arpa", Prefix: "192.0.2.0/24", Network: "TEST-NET-1 (192.0.2.0 - 192.0.2.255)"},
+			},
+		},
 		{
 			name: "Minimal domain info",
 			summary: domain.Summary{
@@ -147,8 +384,8 @@ func TestOutputSummary_Structure(t *testing.T) {
 			}()
 
 			// Test both with and without color
-			OutputSummary(tt.summary, true)
-			OutputSummary(tt.summary, false)
+			OutputSummary(tt.summary, true, true)
+			OutputSummary(tt.summary, false, false)
 		})
 	}
 }
@@ -169,5 +406,102 @@ func TestOutputSummary_AvailableDomain(t *testing.T) {
 		}
 	}()
 
-	OutputSummary(summary, false) // No color for predictable output
+	OutputSummary(summary, false, false) // No color for predictable output
+}
+
+func TestOutputIPSummary(t *testing.T) {
+	summary := domain.IPSummary{
+		Query:        "8.8.8.8",
+		Protocol:     "RDAP",
+		Handle:       "NET-8-8-8-0-2",
+		StartAddress: "8.8.8.0",
+		EndAddress:   "8.8.8.255",
+		CIDRs:        []string{"8.8.8.0/24"},
+		Name:         "GOGL",
+		Type:         "DIRECT ALLOCATION",
+		Country:      "US",
+		ParentHandle: "NET-8-0-0-0-1",
+		Organization: "Google LLC",
+		AbuseContact: "network-abuse@google.com",
+		Contacts:     []domain.Contact{{Role: "abuse", Handle: "ABUSE5250-ARIN", Email: "network-abuse@google.com"}},
+		Registration: &domain.TimelineEvent{Date: time.Now().AddDate(-1, 0, 0), HumanReadable: "1 year ago"},
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			t.Errorf("OutputIPSummary panicked: %v", r)
+		}
+	}()
+
+	OutputIPSummary(summary, true)
+	OutputIPSummary(domain.IPSummary{Query: "192.0.2.1", Protocol: "WHOIS"}, false)
+
+	latitude, longitude := 37.751, -97.822
+	summary.Geolocation = &geo.Record{
+		Country: "US", CountryName: "United States", Latitude: &latitude, Longitude: &longitude,
+		AccuracyRadius: 1000, ASN: 15169, ASOrganization: "GOOGLE", Network: "8.8.8.0/23",
+		Sources: []string{"GeoLite2-City (2025-10-14)", "GeoLite2-ASN (2025-10-14)"},
+	}
+	OutputIPSummary(summary, false)
+	summary.Geolocation = &geo.Record{ASN: 15169, Sources: []string{"GeoLite2-ASN (2025-10-14)"}}
+	OutputIPSummary(summary, true)
+
+	summary.Routing = &routing.Route{Prefix: netip.MustParsePrefix("8.8.8.0/24"), Origins: []uint32{15169}, Source: "pfx2as"}
+	OutputIPSummary(summary, false)
+	summary.Routing.Origins = []uint32{64500, 64501}
+	OutputIPSummary(summary, true)
+
+	vrp := rpki.VRP{Prefix: netip.MustParsePrefix("8.8.8.0/24"), MaxLength: 24, ASN: 64500, TrustAnchor: "arin"}
+	summary.RPKI = &domain.RouteValidity{Source: "vrps.json", Routes: []rpki.Validation{
+		{Prefix: vrp.Prefix, Origin: 64500, State: rpki.Valid, VRPs: []rpki.VRP{vrp}},
+		{Prefix: vrp.Prefix, Origin: 64501, State: rpki.Invalid, Reason: rpki.ReasonOrigin, VRPs: []rpki.VRP{vrp}},
+		{Prefix: vrp.Prefix, Origin: 64502, State: rpki.NotFound},
+	}}
+	OutputIPSummary(summary, true)
+	OutputIPSummary(summary, false)
+
+	summary.ReverseDNS = &domain.ReverseDNS{Name: "8.8.8.8.in-addr.arpa", PTR: []string{"dns.google"}, Resolver: "8.8.8.8:53"}
+	OutputIPSummary(summary, true)
+	summary.ReverseDNS = &domain.ReverseDNS{Name: "8.8.8.in-addr.arpa", Resolver: "8.8.8.8:53", Error: "8.8.8.in-addr.arpa NS: NXDOMAIN"}
+	OutputIPSummary(summary, false)
+}
+
+func TestOutputNetworkTree(t *testing.T) {
+	tree := &domain.NetworkTree{
+		Query:    "203.0.113.5",
+		Protocol: "RDAP",
+		Levels: []domain.IPSummary{
+			{StartAddress: "203.0.0.0", EndAddress: "203.0.255.255", CIDRs: []string{"203.0.0.0/16"}, Name: "RIR-BLOCK", Type: "ALLOCATION"},
+			{StartAddress: "203.0.112.0", EndAddress: "203.0.115.255", Organization: "Example ISP", Name: "ISP-NET", Country: "AU"},
+			{StartAddress: "203.0.113.0", EndAddress: "203.0.113.255", Handle: "CUST"},
+		},
+		Warnings: []string{"Could not fetch the parent of RIR-BLOCK: 404"},
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			t.Errorf("OutputNetworkTree panicked: %v", r)
+		}
+	}()
+
+	OutputNetworkTree(tree, true)
+	OutputNetworkTree(&domain.NetworkTree{Query: "192.0.2.1", Protocol: "WHOIS"}, false)
+}
+
+func TestOutputSpecialPurpose(t *testing.T) {
+	reachable := false
+	defer func() {
+		if r := recover(); r != nil {
+			t.Errorf("OutputSpecialPurpose panicked: %v", r)
+		}
+	}()
+
+	OutputSpecialPurpose(&domain.SpecialPurpose{
+		Query: "192.168.1.1", QueryType: "ip", Protocol: "IANA", Block: "192.168.0.0/16",
+		Name: "Private-Use", RFC: "RFC 1918", Allocated: "1996-02", GloballyReachable: &reachable,
+	}, true)
+	OutputSpecialPurpose(&domain.SpecialPurpose{
+		Query: "AS64512", QueryType: "asn", Protocol: "IANA", Block: "AS64512 - AS65534",
+		Name: "For private use", RFC: "RFC 6996", GloballyReachable: &reachable,
+	}, false)
 }
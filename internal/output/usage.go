@@ -7,7 +7,10 @@ func PrintUsage() {
 	fmt.Printf(`regard - domain research and discovery tool
 
 USAGE:
-    regard [OPTIONS] <domain|ip|asn>
+    regard [OPTIONS] <domain|ip|asn|as-set|arpa>
+    regard [OPTIONS] registrar [--refresh] <id|name>
+    regard [OPTIONS] tld [--refresh] <tld>
+    regard [OPTIONS] nameserver <host>
 
 EXAMPLES:
     regard example.com          # Human-readable domain summary
@@ -15,17 +18,44 @@ EXAMPLES:
     regard --json example.com   # Summary in JSON format
     regard --whois example.com  # Force WHOIS query
     regard --rdap example.com   # Force RDAP query only
+    regard --whois --whois-server localhost:4343 example.test  # Query a local WHOIS server
     regard 8.8.8.8              # Query IP address
+    regard --tree 193.0.6.139   # RIR block, ISP allocation and customer assignment
+    regard --ptr 8.8.8.8        # Add the address's PTR records from live DNS
+    regard 2.0.192.in-addr.arpa # Reverse zone delegation for 192.0.2.0/24
     regard AS15169              # Query ASN
+    regard --expand AS15169     # Add IRR route objects and expand the as-sets in its policy
+    regard AS-EXAMPLE           # Expand an as-set to its member ASes over IRR WHOIS
+    regard 192.168.1.1          # Special-purpose address, answered offline
+    regard --lookup AS64512     # Query the registries even for a private ASN
     regard --raw example.com    # Raw output without formatting
+    regard --notices example.com # Show terms of use and redacted fields
+    regard --dns-check example.com  # Check the delegation against live DNS
+    regard registrar 292        # Look up a registrar by IANA ID (offline)
+    regard registrar namecheap  # Search registrars by name
+    regard registrar --refresh  # Update the bundled IANA registrar registry
+    regard tld io               # TLD operator, WHOIS/RDAP servers and lifecycle
+    regard tld --refresh        # Update the bundled IANA root zone and RDAP bootstrap data
+    regard nameserver ns1.example.com  # Nameserver host, addresses and status over RDAP
 
 OPTIONS:
     --whois        Force use of WHOIS protocol
     --rdap         Force use of RDAP protocol only
+    --rdap-server URL  Force a specific RDAP base URL
+    --whois-server HOST[:PORT]  Force a specific WHOIS server
+    --dns-check    Compare nameservers and DS records with live DNS
+    --resolver HOST[:PORT]  DNS resolver for --dns-check and --ptr (default: system resolver)
+    --tree         Show the netblock allocation chain for an IP address
+    --ptr          Resolve PTR records, or a block's reverse zone nameservers, for IP queries
+    --lookup       Query the registries even for special-purpose addresses and ASNs
+    --expand       Resolve as-sets and route objects over IRR WHOIS for ASN queries
+    --config PATH  Configuration file (default: $REGARD_CONFIG or <config dir>/regard/config.json)
     -v             Verbose output (full details)
     --json         Output summary in JSON format
     --raw          Output raw response without JSON formatting
+    --raw-original Output WHOIS responses in their original character set
     --no-color     Disable syntax highlighting
+    --notices      Show registry notices, remarks and redacted fields
     --help         Show this help message
 
 By default, regard shows a human-readable summary and attempts RDAP first with WHOIS fallback.
@@ -0,0 +1,127 @@
+package query
+
+import (
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// Reverse DNS zones for IPv4 (RFC 1035) and IPv6 (RFC 3596) addresses
+const (
+	ipv4ReverseZone = "in-addr.arpa"
+	ipv6ReverseZone = "ip6.arpa"
+)
+
+// Bounds of the enclosing zones tried for a reverse name the RIR doesn't hold.
+// RIRs register in-addr.arpa zones down to /24 and ip6.arpa zones at /64 or
+// shorter, and a /16 or /32 is the shortest worth asking for.
+const (
+	ipv4ReverseFloor   = 16
+	ipv6ReverseFloor   = 32
+	ipv6ReverseCeiling = 64
+)
+
+// ParseReverseName maps an in-addr.arpa or ip6.arpa name to the address block
+// it covers: "2.0.192.in-addr.arpa" is 192.0.2.0/24 and a full-length name is
+// a single address
+func ParseReverseName(name string) (netip.Prefix, bool) {
+	name = strings.ToLower(strings.Trim(strings.TrimSpace(name), "."))
+
+	if labels, ok := strings.CutSuffix(name, "."+ipv4ReverseZone); ok {
+		octets := strings.Split(labels, ".")
+		if len(octets) > 4 {
+			return netip.Prefix{}, false
+		}
+		var addr [4]byte
+		for i, label := range octets {
+			value, err := strconv.ParseUint(label, 10, 8)
+			if err != nil || (len(label) > 1 && label[0] == '0') {
+				return netip.Prefix{}, false
+			}
+			addr[len(octets)-1-i] = byte(value)
+		}
+		return netip.PrefixFrom(netip.AddrFrom4(addr), 8*len(octets)), true
+	}
+
+	if labels, ok := strings.CutSuffix(name, "."+ipv6ReverseZone); ok {
+		nibbles := strings.Split(labels, ".")
+		if len(nibbles) > 32 {
+			return netip.Prefix{}, false
+		}
+		var addr [16]byte
+		for i, label := range nibbles {
+			value, err := strconv.ParseUint(label, 16, 4)
+			if err != nil || len(label) != 1 {
+				return netip.Prefix{}, false
+			}
+			position := len(nibbles) - 1 - i
+			if position%2 == 0 {
+				addr[position/2] |= byte(value) << 4
+			} else {
+				addr[position/2] |= byte(value)
+			}
+		}
+		return netip.PrefixFrom(netip.AddrFrom16(addr), 4*len(nibbles)), true
+	}
+
+	return netip.Prefix{}, false
+}
+
+// ReverseName returns the in-addr.arpa or ip6.arpa name of an address block,
+// the PTR owner name for a single address. Blocks must end on an octet (IPv4)
+// or nibble (IPv6) boundary, as reverse zones are delegated on those.
+func ReverseName(prefix netip.Prefix) (string, bool) {
+	addr := prefix.Addr().WithZone("")
+	bits := prefix.Bits()
+	if addr.Is4In6() && bits >= 96 {
+		addr, bits = addr.Unmap(), bits-96
+	}
+
+	var labels []string
+	if addr.Is4() {
+		if bits%8 != 0 {
+			return "", false
+		}
+		octets := addr.As4()
+		for i := bits/8 - 1; i >= 0; i-- {
+			labels = append(labels, strconv.Itoa(int(octets[i])))
+		}
+		return strings.Join(append(labels, ipv4ReverseZone), "."), true
+	}
+
+	if bits%4 != 0 {
+		return "", false
+	}
+	bytes := addr.As16()
+	for i := bits/4 - 1; i >= 0; i-- {
+		nibble := bytes[i/2] >> 4
+		if i%2 == 1 {
+			nibble = bytes[i/2] & 0x0f
+		}
+		labels = append(labels, strconv.FormatUint(uint64(nibble), 16))
+	}
+	return strings.Join(append(labels, ipv6ReverseZone), "."), true
+}
+
+// EnclosingReverseNames returns the zones enclosing a reverse name, longest
+// first: octet steps down to a /16 for in-addr.arpa, and nibble steps from at
+// most a /64 down to a /32 for ip6.arpa
+func EnclosingReverseNames(name string) []string {
+	prefix, ok := ParseReverseName(name)
+	if !ok {
+		return nil
+	}
+
+	step, floor, bits := 8, ipv4ReverseFloor, prefix.Bits()-8
+	if prefix.Addr().Is6() {
+		step, floor, bits = 4, ipv6ReverseFloor, min(prefix.Bits()-4, ipv6ReverseCeiling)
+	}
+
+	var names []string
+	for ; bits >= floor; bits -= step {
+		if enclosing, ok := ReverseName(netip.PrefixFrom(prefix.Addr(), bits).Masked()); ok {
+			names = append(names, enclosing)
+		}
+	}
+	return names
+}
@@ -0,0 +1,89 @@
+package query
+
+import (
+	"net/netip"
+	"reflect"
+	"testing"
+)
+
+func TestParseReverseName(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"5.4.3.2.in-addr.arpa", "2.3.4.5/32"},
+		{"2.0.192.in-addr.arpa.", "192.0.2.0/24"},
+		{"193.IN-ADDR.ARPA", "193.0.0.0/8"},
+		{"8.b.d.0.1.0.0.2.ip6.arpa", "2001:db8::/32"},
+		{"b.a.9.8.7.6.5.0.4.0.0.0.3.0.0.0.2.0.0.0.1.0.0.0.0.0.0.0.1.2.3.4.ip6.arpa", "4321:0:1:2:3:4:567:89ab/128"},
+		{"256.0.192.in-addr.arpa", ""},
+		{"01.0.192.in-addr.arpa", ""},
+		{"1.2.3.4.5.in-addr.arpa", ""},
+		{"in-addr.arpa", ""},
+		{"10.8.b.d.0.1.0.0.2.ip6.arpa", ""},
+		{"example.com", ""},
+	}
+
+	for _, tt := range tests {
+		prefix, ok := ParseReverseName(tt.name)
+		if tt.expected == "" {
+			if ok {
+				t.Errorf("ParseReverseName(%q) = %s, want no match", tt.name, prefix)
+			}
+			continue
+		}
+		if !ok || prefix.String() != tt.expected {
+			t.Errorf("ParseReverseName(%q) = %s, %v; want %s", tt.name, prefix, ok, tt.expected)
+		}
+	}
+}
+
+func TestReverseName(t *testing.T) {
+	tests := []struct {
+		prefix   string
+		expected string
+	}{
+		{"2.3.4.5/32", "5.4.3.2.in-addr.arpa"},
+		{"192.0.2.0/24", "2.0.192.in-addr.arpa"},
+		{"2001:db8::/32", "8.b.d.0.1.0.0.2.ip6.arpa"},
+		{"::ffff:192.0.2.1/128", "1.2.0.192.in-addr.arpa"},
+		{"192.0.0.0/22", ""},
+		{"2001:db8::/33", ""},
+	}
+
+	for _, tt := range tests {
+		name, ok := ReverseName(netip.MustParsePrefix(tt.prefix))
+		if name != tt.expected || ok != (tt.expected != "") {
+			t.Errorf("ReverseName(%s) = %q, %v; want %q", tt.prefix, name, ok, tt.expected)
+		}
+	}
+}
+
+func TestEnclosingReverseNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected []string
+	}{
+		{"5.4.3.2.in-addr.arpa", []string{"4.3.2.in-addr.arpa", "3.2.in-addr.arpa"}},
+		{"2.0.192.in-addr.arpa", []string{"0.192.in-addr.arpa"}},
+		{"0.192.in-addr.arpa", nil},
+		{"8.b.d.0.1.0.0.2.ip6.arpa", nil},
+		{"0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa", []string{
+			"0.0.0.8.b.d.0.1.0.0.2.ip6.arpa", "0.0.8.b.d.0.1.0.0.2.ip6.arpa",
+			"0.8.b.d.0.1.0.0.2.ip6.arpa", "8.b.d.0.1.0.0.2.ip6.arpa",
+		}},
+		{"example.com", nil},
+	}
+
+	for _, tt := range tests {
+		if got := EnclosingReverseNames(tt.name); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("EnclosingReverseNames(%q) = %v, want %v", tt.name, got, tt.expected)
+		}
+	}
+
+	// A single IPv6 address steps up from its /64, nine zones in all
+	names := EnclosingReverseNames("b.a.9.8.7.6.5.0.4.0.0.0.3.0.0.0.2.0.0.0.1.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa")
+	if len(names) != 9 || names[0] != "2.0.0.0.1.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa" || names[8] != "8.b.d.0.1.0.0.2.ip6.arpa" {
+		t.Errorf("EnclosingReverseNames(/128) = %v", names)
+	}
+}
@@ -0,0 +1,320 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openrdap/rdap/bootstrap"
+	"github.com/openrdap/rdap/bootstrap/cache"
+
+	"regard/internal/iana"
+)
+
+// Bootstrap registry names, matching the IANA bootstrap file names
+const (
+	RegistryDNS  = "dns"
+	RegistryIPv4 = "ipv4"
+	RegistryIPv6 = "ipv6"
+	RegistryASN  = "asn"
+)
+
+// DefaultBootstrapRefresh is how long a cached bootstrap registry is used
+// before it is downloaded again
+const DefaultBootstrapRefresh = 24 * time.Hour
+
+// RDAPOptions configures how RDAP servers are located for a query
+type RDAPOptions struct {
+	Server           string                   // Force this RDAP base URL, bypassing bootstrap
+	BootstrapURL     string                   // Base URL of the bootstrap registries (default IANA)
+	CacheDir         string                   // Directory for cached bootstrap registries; empty keeps them in memory
+	RefreshIntervals map[string]time.Duration // Per-registry cache lifetime, keyed by dns/ipv4/ipv6/asn
+	Overrides        []RDAPOverride           // Local entries consulted before the IANA registries
+}
+
+// RDAPOverride maps a bootstrap entry to custom RDAP servers
+type RDAPOverride struct {
+	Registry string   // dns, ipv4, ipv6 or asn
+	Entry    string   // TLD or zone, CIDR prefix, or ASN range such as "64512-65534"
+	URLs     []string // RDAP base URLs, in order of preference
+}
+
+// LoadRDAPOverrideFile reads a bootstrap-format file (RFC 9224) of local
+// overrides for the given registry
+func LoadRDAPOverrideFile(registry, path string) ([]RDAPOverride, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading RDAP override file: %w", err)
+	}
+
+	file, err := iana.ParseBootstrapFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	var overrides []RDAPOverride
+	for _, service := range file.Services {
+		if len(service) < 2 {
+			continue
+		}
+		for _, entry := range service[0] {
+			overrides = append(overrides, RDAPOverride{Registry: registry, Entry: entry, URLs: service[1]})
+		}
+	}
+	return overrides, nil
+}
+
+// RegistryForEntry infers the bootstrap registry an override entry belongs to
+func RegistryForEntry(entry string) string {
+	if prefix, err := netip.ParsePrefix(entry); err == nil {
+		if prefix.Addr().Is4() {
+			return RegistryIPv4
+		}
+		return RegistryIPv6
+	}
+	if _, _, ok := parseASNRange(entry); ok {
+		return RegistryASN
+	}
+	return RegistryDNS
+}
+
+// bootstrapRegistryFor returns the bootstrap registry used for a query type
+func bootstrapRegistryFor(queryType QueryType, query string) string {
+	switch queryType {
+	case QueryTypeIP:
+		if strings.Contains(query, ":") {
+			return RegistryIPv6
+		}
+		return RegistryIPv4
+	case QueryTypeASN:
+		return RegistryASN
+	case QueryTypeReverse:
+		// The RIRs serve reverse zones for the address space they hold
+		if prefix, ok := ParseReverseName(query); ok && prefix.Addr().Is6() {
+			return RegistryIPv6
+		}
+		return RegistryIPv4
+	default:
+		return RegistryDNS
+	}
+}
+
+// matchRDAPOverride returns the URLs of the most specific override covering the query
+func matchRDAPOverride(overrides []RDAPOverride, registry, query string) []string {
+	var best []string
+	bestSpecificity := -1
+
+	for _, override := range overrides {
+		if override.Registry != registry {
+			continue
+		}
+
+		specificity := -1
+		switch registry {
+		case RegistryDNS:
+			name := strings.ToLower(strings.TrimSuffix(query, "."))
+			entry := strings.ToLower(strings.Trim(override.Entry, "."))
+			if name == entry || strings.HasSuffix(name, "."+entry) {
+				specificity = strings.Count(entry, ".") + 1
+			}
+		case RegistryIPv4, RegistryIPv6:
+			prefix, err := netip.ParsePrefix(override.Entry)
+			queryPrefix, queryErr := parseQueryPrefix(query)
+			if err == nil && queryErr == nil && prefix.Contains(queryPrefix.Addr()) && prefix.Bits() <= queryPrefix.Bits() {
+				specificity = prefix.Bits()
+			}
+		case RegistryASN:
+			low, high, ok := parseASNRange(override.Entry)
+			asn, err := strconv.ParseUint(strings.TrimPrefix(strings.ToUpper(query), "AS"), 10, 32)
+			if ok && err == nil && uint32(asn) >= low && uint32(asn) <= high {
+				// Narrower ranges are more specific
+				specificity = int(^uint32(0) - (high - low))
+			}
+		}
+
+		if specificity > bestSpecificity {
+			best = override.URLs
+			bestSpecificity = specificity
+		}
+	}
+
+	return best
+}
+
+// parseQueryPrefix reads an address or CIDR prefix, treating an address as a host prefix
+func parseQueryPrefix(query string) (netip.Prefix, error) {
+	if strings.Contains(query, "/") {
+		return netip.ParsePrefix(query)
+	}
+	addr, err := netip.ParseAddr(query)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// parseASNRange parses "64512-65534", "AS64512" or "64512"
+func parseASNRange(entry string) (uint32, uint32, bool) {
+	parse := func(s string) (uint32, bool) {
+		n, err := strconv.ParseUint(strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(s)), "AS"), 10, 32)
+		return uint32(n), err == nil
+	}
+
+	if first, last, found := strings.Cut(entry, "-"); found {
+		low, okLow := parse(first)
+		high, okHigh := parse(last)
+		return low, high, okLow && okHigh && low <= high
+	}
+
+	n, ok := parse(entry)
+	return n, n, ok
+}
+
+// bootstrapCache stores bootstrap registries on disk with a refresh interval
+// per registry. When allowStale is set, expired files are still used, so that
+// lookups keep working while the registries can't be downloaded.
+type bootstrapCache struct {
+	disk       *cache.DiskCache
+	timeouts   map[string]time.Duration
+	allowStale bool
+}
+
+func newBootstrapCache(dir string, intervals map[string]time.Duration) *bootstrapCache {
+	disk := cache.NewDiskCache()
+	disk.Dir = dir
+
+	timeouts := map[string]time.Duration{}
+	for registry, interval := range intervals {
+		timeouts[registry+".json"] = interval
+	}
+
+	return &bootstrapCache{disk: disk, timeouts: timeouts}
+}
+
+func (c *bootstrapCache) Load(filename string) ([]byte, error) {
+	return c.disk.Load(filename)
+}
+
+func (c *bootstrapCache) Save(filename string, data []byte) error {
+	if err := os.MkdirAll(c.disk.Dir, 0o755); err != nil {
+		return err
+	}
+	return c.disk.Save(filename, data)
+}
+
+func (c *bootstrapCache) State(filename string) cache.FileState {
+	// Files from custom bootstrap services carry a hash prefix, e.g. 012def_dns.json
+	registryFile := filename
+	if i := strings.LastIndex(filename, "_"); i >= 0 {
+		registryFile = filename[i+1:]
+	}
+
+	c.disk.Timeout = DefaultBootstrapRefresh
+	if timeout, ok := c.timeouts[registryFile]; ok && timeout > 0 {
+		c.disk.Timeout = timeout
+	}
+
+	state := c.disk.State(filename)
+	if state == cache.Expired && c.allowStale {
+		return cache.ShouldReload
+	}
+	return state
+}
+
+// SetTimeout is a no-op; refresh intervals are configured per registry
+func (c *bootstrapCache) SetTimeout(time.Duration) {}
+
+// resolveRDAPServers determines the RDAP base URLs to query, in order of
+// preference: a forced server, local overrides, the IANA bootstrap registries
+// (cached on disk when configured) and finally the bundled DNS bootstrap copy
+func resolveRDAPServers(ctx context.Context, queryType QueryType, query string, opts RDAPOptions) ([]*url.URL, error) {
+	if opts.Server != "" {
+		return parseServerURLs([]string{opts.Server})
+	}
+
+	registry := bootstrapRegistryFor(queryType, query)
+	if queryType == QueryTypeReverse {
+		// An override for the zone itself wins over one for the addresses
+		if urls := matchRDAPOverride(opts.Overrides, RegistryDNS, query); len(urls) > 0 {
+			return parseServerURLs(urls)
+		}
+		prefix, _ := ParseReverseName(query)
+		query = prefix.String()
+	}
+	if urls := matchRDAPOverride(opts.Overrides, registry, query); len(urls) > 0 {
+		return parseServerURLs(urls)
+	}
+
+	client := &bootstrap.Client{}
+	var diskCache *bootstrapCache
+	if opts.CacheDir != "" {
+		diskCache = newBootstrapCache(filepath.Join(opts.CacheDir, "bootstrap"), opts.RefreshIntervals)
+		client.Cache = diskCache
+	}
+	if opts.BootstrapURL != "" {
+		baseURL, err := url.Parse(opts.BootstrapURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bootstrap URL %q: %w", opts.BootstrapURL, err)
+		}
+		client.BaseURL = baseURL
+	}
+
+	question := &bootstrap.Question{
+		RegistryType: bootstrapRegistryType(registry),
+		Query:        query,
+	}
+	question = question.WithContext(ctx)
+
+	answer, err := client.Lookup(question)
+	if err != nil && diskCache != nil {
+		// Fall back to an expired cached copy if the registry can't be refreshed
+		diskCache.allowStale = true
+		answer, err = client.Lookup(question)
+	}
+	if err == nil && len(answer.URLs) > 0 {
+		return answer.URLs, nil
+	}
+
+	if registry == RegistryDNS {
+		labels := strings.Split(strings.TrimSuffix(query, "."), ".")
+		if urls := iana.RDAPServersForTLD(labels[len(labels)-1]); len(urls) > 0 {
+			return parseServerURLs(urls)
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("no RDAP servers found for '%s'", query)
+}
+
+func bootstrapRegistryType(registry string) bootstrap.RegistryType {
+	switch registry {
+	case RegistryIPv4:
+		return bootstrap.IPv4
+	case RegistryIPv6:
+		return bootstrap.IPv6
+	case RegistryASN:
+		return bootstrap.ASN
+	default:
+		return bootstrap.DNS
+	}
+}
+
+func parseServerURLs(servers []string) ([]*url.URL, error) {
+	var urls []*url.URL
+	for _, server := range servers {
+		u, err := url.Parse(server)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("invalid RDAP server URL %q", server)
+		}
+		urls = append(urls, u)
+	}
+	return urls, nil
+}
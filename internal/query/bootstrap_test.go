@@ -0,0 +1,278 @@
+package query
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMatchRDAPOverride(t *testing.T) {
+	overrides := []RDAPOverride{
+		{Registry: RegistryDNS, Entry: "test", URLs: []string{"http://tld.example/"}},
+		{Registry: RegistryDNS, Entry: "internal.test", URLs: []string{"http://zone.example/"}},
+		{Registry: RegistryIPv4, Entry: "10.0.0.0/8", URLs: []string{"http://ten.example/"}},
+		{Registry: RegistryIPv4, Entry: "10.1.0.0/16", URLs: []string{"http://ten-one.example/"}},
+		{Registry: RegistryIPv6, Entry: "2001:db8::/32", URLs: []string{"http://doc.example/"}},
+		{Registry: RegistryASN, Entry: "64512-65534", URLs: []string{"http://private.example/"}},
+		{Registry: RegistryASN, Entry: "AS64600", URLs: []string{"http://single.example/"}},
+	}
+
+	tests := []struct {
+		registry string
+		query    string
+		expected []string
+	}{
+		{RegistryDNS, "example.test", []string{"http://tld.example/"}},
+		{RegistryDNS, "host.internal.test.", []string{"http://zone.example/"}},
+		{RegistryDNS, "example.com", nil},
+		{RegistryDNS, "nottest", nil},
+		{RegistryIPv4, "10.2.3.4", []string{"http://ten.example/"}},
+		{RegistryIPv4, "10.1.3.4", []string{"http://ten-one.example/"}},
+		{RegistryIPv4, "192.0.2.1", nil},
+		{RegistryIPv4, "10.1.2.0/24", []string{"http://ten-one.example/"}},
+		{RegistryIPv4, "10.0.0.0/7", nil},
+		{RegistryIPv6, "2001:db8::1", []string{"http://doc.example/"}},
+		{RegistryASN, "AS64513", []string{"http://private.example/"}},
+		{RegistryASN, "as64600", []string{"http://single.example/"}},
+		{RegistryASN, "AS15169", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			result := matchRDAPOverride(overrides, tt.registry, tt.query)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("matchRDAPOverride(%s, %q) = %v, want %v", tt.registry, tt.query, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRegistryForEntry(t *testing.T) {
+	tests := map[string]string{
+		"test":          RegistryDNS,
+		"internal.test": RegistryDNS,
+		"10.0.0.0/8":    RegistryIPv4,
+		"2001:db8::/32": RegistryIPv6,
+		"64512-65534":   RegistryASN,
+		"AS64512":       RegistryASN,
+	}
+
+	for entry, expected := range tests {
+		if result := RegistryForEntry(entry); result != expected {
+			t.Errorf("RegistryForEntry(%q) = %q, want %q", entry, result, expected)
+		}
+	}
+}
+
+func TestLoadRDAPOverrideFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dns.json")
+	content := `{"version": "1.0", "services": [[["test", "example"], ["http://localhost:8080/rdap/"]]]}`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	overrides, err := LoadRDAPOverrideFile(RegistryDNS, path)
+	if err != nil {
+		t.Fatalf("LoadRDAPOverrideFile() unexpected error: %v", err)
+	}
+	if len(overrides) != 2 || overrides[1].Entry != "example" || overrides[1].URLs[0] != "http://localhost:8080/rdap/" {
+		t.Errorf("Unexpected overrides: %+v", overrides)
+	}
+
+	if _, err := LoadRDAPOverrideFile(RegistryDNS, filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected error for missing override file")
+	}
+}
+
+// newRDAPStandIn serves a minimal RDAP domain response for any domain query
+func newRDAPStandIn(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rdap+json")
+		fmt.Fprintf(w, `{"objectClassName": "domain", "ldhName": "%s", "status": ["active"], "redacted": []}`, filepath.Base(r.URL.Path))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestPerformRDAPQueryWithOptions_ForcedServer(t *testing.T) {
+	rdapServer := newRDAPStandIn(t)
+
+	result := PerformRDAPQueryWithOptions("example.test", RDAPOptions{Server: rdapServer.URL + "/rdap"})
+
+	if !result.Success {
+		t.Fatalf("Expected success, got error: %s", result.Error)
+	}
+	if result.Server != rdapServer.URL+"/rdap" {
+		t.Errorf("Expected Server = %q, got %q", rdapServer.URL+"/rdap", result.Server)
+	}
+	if result.RawData == "" || !reflect.DeepEqual(result.RawData[:1], "{") {
+		t.Errorf("Expected raw JSON body, got %q", result.RawData)
+	}
+}
+
+func TestPerformRDAPNameserverQuery(t *testing.T) {
+	var requestPath string
+	rdapServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/rdap+json")
+		fmt.Fprint(w, `{"objectClassName": "nameserver", "ldhName": "ns1.example.test", "ipAddresses": {"v4": ["192.0.2.1"]}, "status": ["active"]}`)
+	}))
+	t.Cleanup(rdapServer.Close)
+
+	result := PerformRDAPNameserverQuery("ns1.example.test.", RDAPOptions{Server: rdapServer.URL})
+
+	if !result.Success {
+		t.Fatalf("Expected success, got error: %s", result.Error)
+	}
+	if result.Type != string(QueryTypeNameserver) {
+		t.Errorf("Type = %q, want %q", result.Type, QueryTypeNameserver)
+	}
+	if requestPath != "/nameserver/ns1.example.test" {
+		t.Errorf("Request path = %q, want /nameserver/ns1.example.test", requestPath)
+	}
+}
+
+func TestPerformRDAPQueryWithOptions_ReverseZone(t *testing.T) {
+	var requestPath string
+	rdapServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/rdap+json")
+		fmt.Fprint(w, `{"objectClassName": "domain", "ldhName": "2.0.192.in-addr.arpa", "nameservers": [{"objectClassName": "nameserver", "ldhName": "ns1.example.net"}]}`)
+	}))
+	t.Cleanup(rdapServer.Close)
+
+	// The zone is routed by the addresses it covers, not the DNS bootstrap
+	result := PerformRDAPQueryWithOptions("2.0.192.in-addr.arpa", RDAPOptions{Overrides: []RDAPOverride{
+		{Registry: RegistryIPv4, Entry: "192.0.0.0/16", URLs: []string{rdapServer.URL}},
+		{Registry: RegistryIPv4, Entry: "198.51.100.0/24", URLs: []string{"http://unused.example/"}},
+	}})
+
+	if !result.Success {
+		t.Fatalf("Expected success, got error: %s", result.Error)
+	}
+	if result.Type != string(QueryTypeReverse) {
+		t.Errorf("Type = %q, want %q", result.Type, QueryTypeReverse)
+	}
+	if requestPath != "/domain/2.0.192.in-addr.arpa" {
+		t.Errorf("Request path = %q, want /domain/2.0.192.in-addr.arpa", requestPath)
+	}
+}
+
+func TestPerformRDAPQueryWithOptions_EnclosingReverseZone(t *testing.T) {
+	// The stand-in only knows the /24, as the RIRs do
+	var requestPaths []string
+	rdapServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestPaths = append(requestPaths, r.URL.Path)
+		if r.URL.Path != "/domain/4.3.2.in-addr.arpa" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/rdap+json")
+		fmt.Fprint(w, `{"objectClassName": "domain", "ldhName": "4.3.2.in-addr.arpa", "nameservers": [{"objectClassName": "nameserver", "ldhName": "ns1.example.net"}]}`)
+	}))
+	t.Cleanup(rdapServer.Close)
+	opts := RDAPOptions{Overrides: []RDAPOverride{{Registry: RegistryIPv4, Entry: "2.0.0.0/8", URLs: []string{rdapServer.URL}}}}
+
+	result := PerformRDAPQueryWithOptions("5.4.3.2.in-addr.arpa", opts)
+	if !result.Success {
+		t.Fatalf("Expected success, got error: %s", result.Error)
+	}
+	if !reflect.DeepEqual(requestPaths, []string{"/domain/5.4.3.2.in-addr.arpa", "/domain/4.3.2.in-addr.arpa"}) {
+		t.Errorf("Request paths = %v, want the name then its /24", requestPaths)
+	}
+	if result.Query != "5.4.3.2.in-addr.arpa" || !strings.Contains(result.RawData, `"4.3.2.in-addr.arpa"`) {
+		t.Errorf("Result = %s for %s, want the /24 for the queried name", result.RawData, result.Query)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "4.3.2.in-addr.arpa") {
+		t.Errorf("Warnings = %v, want one naming the enclosing zone", result.Warnings)
+	}
+
+	// Nothing registered at any level keeps the original not-found error
+	requestPaths = nil
+	result = PerformRDAPQueryWithOptions("5.4.9.2.in-addr.arpa", opts)
+	if result.Success || len(requestPaths) != 3 {
+		t.Errorf("Expected failure after trying 3 zones, got success %v after %v", result.Success, requestPaths)
+	}
+}
+
+func TestPerformRDAPLinkQuery(t *testing.T) {
+	rdapServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rdap+json")
+		fmt.Fprint(w, `{"objectClassName": "ip network", "handle": "NET-192-0-0-0-1", "startAddress": "192.0.0.0", "endAddress": "192.0.255.255"}`)
+	}))
+	t.Cleanup(rdapServer.Close)
+
+	result := PerformRDAPLinkQuery(rdapServer.URL + "/ip/192.0.0.0/16")
+
+	if !result.Success {
+		t.Fatalf("Expected success, got error: %s", result.Error)
+	}
+	if result.Type != string(QueryTypeIP) {
+		t.Errorf("Type = %q, want %q", result.Type, QueryTypeIP)
+	}
+	if result.Server != rdapServer.URL {
+		t.Errorf("Server = %q, want %q", result.Server, rdapServer.URL)
+	}
+
+	if result := PerformRDAPLinkQuery("/ip/192.0.0.0/16"); result.Success {
+		t.Error("Expected a relative link to be rejected")
+	}
+}
+
+func TestPerformRDAPQueryWithOptions_CachedBootstrap(t *testing.T) {
+	rdapServer := newRDAPStandIn(t)
+
+	downloads := 0
+	bootstrapServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/dns.json" {
+			http.NotFound(w, r)
+			return
+		}
+		downloads++
+		fmt.Fprintf(w, `{"version": "1.0", "services": [[["test"], ["%s/"]]]}`, rdapServer.URL)
+	}))
+	defer bootstrapServer.Close()
+
+	cacheDir := t.TempDir()
+	opts := RDAPOptions{
+		BootstrapURL:     bootstrapServer.URL + "/",
+		CacheDir:         cacheDir,
+		RefreshIntervals: map[string]time.Duration{RegistryDNS: time.Hour},
+	}
+
+	for i := 0; i < 2; i++ {
+		result := PerformRDAPQueryWithOptions("example.test", opts)
+		if !result.Success {
+			t.Fatalf("Query %d failed: %s", i, result.Error)
+		}
+	}
+
+	if downloads != 1 {
+		t.Errorf("Expected bootstrap file to be downloaded once, got %d downloads", downloads)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(cacheDir, "bootstrap", "*dns.json")); len(matches) != 1 {
+		t.Errorf("Expected bootstrap file to be cached on disk, found %v", matches)
+	}
+
+	// An expired cache is still used when the registry can't be refreshed
+	bootstrapServer.Close()
+	opts.RefreshIntervals[RegistryDNS] = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	result := PerformRDAPQueryWithOptions("example.test", opts)
+	if !result.Success {
+		t.Errorf("Expected stale bootstrap cache to be used, got error: %s", result.Error)
+	}
+}
+
+func TestPerformRDAPQueryWithOptions_InvalidServer(t *testing.T) {
+	result := PerformRDAPQueryWithOptions("example.test", RDAPOptions{Server: "not a url"})
+	if result.Success || result.Error == "" {
+		t.Errorf("Expected failure for invalid server URL, got %+v", result)
+	}
+}
@@ -0,0 +1,157 @@
+package query
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/ianaindex"
+)
+
+// Charset names, as registered with IANA
+const (
+	CharsetUTF8      = "UTF-8"
+	CharsetISO88591  = "ISO-8859-1"
+	CharsetShiftJIS  = "Shift_JIS"
+	CharsetEUCJP     = "EUC-JP"
+	CharsetISO2022JP = "ISO-2022-JP"
+	CharsetEUCKR     = "EUC-KR"
+	CharsetKOI8R     = "KOI8-R"
+	CharsetCP1251    = "windows-1251"
+)
+
+// charsetHints lists the legacy character sets likely for a TLD's registry,
+// tried before the general candidates
+var charsetHints = map[string][]string{
+	"jp": {CharsetShiftJIS, CharsetEUCJP},
+	"kr": {CharsetEUCKR},
+	"ru": {CharsetKOI8R, CharsetCP1251},
+	"su": {CharsetKOI8R, CharsetCP1251},
+	"by": {CharsetCP1251, CharsetKOI8R},
+	"ua": {CharsetKOI8R, CharsetCP1251},
+}
+
+// charsetCandidates are tried, in order, for responses that aren't UTF-8.
+// ISO-8859-1 is the fallback, as any byte sequence is valid in it.
+var charsetCandidates = []string{CharsetShiftJIS, CharsetKOI8R, CharsetEUCKR}
+
+// ValidateWhoisCharset reports whether a configured character set is supported
+func ValidateWhoisCharset(name string) error {
+	_, err := lookupCharset(name)
+	return err
+}
+
+// lookupCharset returns the decoder for an IANA character set name or alias,
+// also accepting the labels browsers use such as "shift-jis"
+func lookupCharset(name string) (encoding.Encoding, error) {
+	if enc, err := ianaindex.IANA.Encoding(name); err == nil && enc != nil {
+		return enc, nil
+	}
+	if enc, err := htmlindex.Get(name); err == nil {
+		return enc, nil
+	}
+	return nil, fmt.Errorf("unsupported character set %q", name)
+}
+
+// decodeCharset transcodes data from the named character set to UTF-8
+func decodeCharset(data []byte, name string) (string, error) {
+	enc, err := lookupCharset(name)
+	if err != nil {
+		return "", err
+	}
+	decoded, err := enc.NewDecoder().Bytes(data)
+	if err != nil {
+		return "", fmt.Errorf("decoding %s response: %w", name, err)
+	}
+	return string(decoded), nil
+}
+
+// detectCharset guesses the character set of a WHOIS response, using the
+// queried TLD as a hint
+func detectCharset(data []byte, tld string) string {
+	// ISO-2022-JP is 7-bit, so it must be checked before UTF-8
+	if bytes.Contains(data, []byte("\x1b$B")) || bytes.Contains(data, []byte("\x1b$@")) {
+		return CharsetISO2022JP
+	}
+	if utf8.Valid(data) {
+		return CharsetUTF8
+	}
+
+	candidates := append(append([]string{}, charsetHints[strings.ToLower(tld)]...), charsetCandidates...)
+	for _, name := range candidates {
+		decoded, err := decodeCharset(data, name)
+		if err != nil || strings.ContainsRune(decoded, utf8.RuneError) {
+			continue
+		}
+		if charsetPlausible(name, data, decoded) {
+			return name
+		}
+	}
+
+	return CharsetISO88591
+}
+
+// charsetPlausible reports whether nearly all non-ASCII text decoded with a
+// charset is in the script the charset is used for
+func charsetPlausible(name string, data []byte, decoded string) bool {
+	var expected func(rune) bool
+	switch name {
+	case CharsetShiftJIS, CharsetEUCJP:
+		expected = func(r rune) bool {
+			// Half-width katakana are what other legacy encodings decode to
+			halfWidth := r >= 0xFF61 && r <= 0xFF9F
+			return !halfWidth && (unicode.In(r, unicode.Hiragana, unicode.Katakana, unicode.Han) || isFullWidthPunct(r))
+		}
+	case CharsetEUCKR:
+		expected = func(r rune) bool {
+			return unicode.In(r, unicode.Hangul, unicode.Han) || isFullWidthPunct(r)
+		}
+	case CharsetKOI8R, CharsetCP1251:
+		// Single isolated high bytes are typical of accented Latin text
+		if !highBytesAdjacent(data) {
+			return false
+		}
+		expected = func(r rune) bool {
+			return unicode.Is(unicode.Cyrillic, r)
+		}
+	default:
+		return true
+	}
+
+	total, matched := 0, 0
+	for _, r := range decoded {
+		if r < utf8.RuneSelf {
+			continue
+		}
+		total++
+		if expected(r) {
+			matched++
+		}
+	}
+	return total > 0 && matched*10 >= total*9
+}
+
+// isFullWidthPunct matches CJK punctuation and full-width forms
+func isFullWidthPunct(r rune) bool {
+	return (r >= 0x3000 && r <= 0x303F) || (r >= 0xFF01 && r <= 0xFF60)
+}
+
+// highBytesAdjacent reports whether most non-ASCII bytes appear in runs, as
+// in words written in a single-byte non-Latin script
+func highBytesAdjacent(data []byte) bool {
+	total, adjacent := 0, 0
+	for i, b := range data {
+		if b < utf8.RuneSelf {
+			continue
+		}
+		total++
+		if (i > 0 && data[i-1] >= utf8.RuneSelf) || (i+1 < len(data) && data[i+1] >= utf8.RuneSelf) {
+			adjacent++
+		}
+	}
+	return total > 0 && adjacent*2 > total
+}
@@ -0,0 +1,87 @@
+package query
+
+import (
+	"testing"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+)
+
+func encodeTestResponse(t *testing.T, enc encoding.Encoding, text string) []byte {
+	t.Helper()
+	data, err := enc.NewEncoder().Bytes([]byte(text))
+	if err != nil {
+		t.Fatalf("encoding test response: %v", err)
+	}
+	return data
+}
+
+func TestDetectCharset(t *testing.T) {
+	japaneseText := "[ドメイン名]                    EXAMPLE.JP\n[登録者名]                      株式会社日本レジストリサービス\n"
+	koreanText := "도메인이름                  : example.kr\n등록인                      : 한국인터넷진흥원\n"
+	russianText := "domain:        EXAMPLE.RU\norg:           ООО \"Пример\"\nperson:        Иван Иванов\n"
+	frenchText := "domain:        example.fr\nholder:        Société Générale\naddress:       75009 Paris\n"
+
+	tests := []struct {
+		name     string
+		data     []byte
+		tld      string
+		expected string
+		text     string
+	}{
+		{"UTF-8", []byte(japaneseText), "jp", CharsetUTF8, japaneseText},
+		{"ASCII", []byte("Domain Name: EXAMPLE.COM\n"), "com", CharsetUTF8, "Domain Name: EXAMPLE.COM\n"},
+		{"Shift_JIS", encodeTestResponse(t, japanese.ShiftJIS, japaneseText), "jp", CharsetShiftJIS, japaneseText},
+		{"Shift_JIS without hint", encodeTestResponse(t, japanese.ShiftJIS, japaneseText), "com", CharsetShiftJIS, japaneseText},
+		{"EUC-JP", encodeTestResponse(t, japanese.EUCJP, japaneseText), "jp", CharsetEUCJP, japaneseText},
+		{"ISO-2022-JP", encodeTestResponse(t, japanese.ISO2022JP, japaneseText), "jp", CharsetISO2022JP, japaneseText},
+		{"EUC-KR", encodeTestResponse(t, korean.EUCKR, koreanText), "kr", CharsetEUCKR, koreanText},
+		{"EUC-KR without hint", encodeTestResponse(t, korean.EUCKR, koreanText), "com", CharsetEUCKR, koreanText},
+		{"KOI8-R", encodeTestResponse(t, charmap.KOI8R, russianText), "ru", CharsetKOI8R, russianText},
+		{"KOI8-R without hint", encodeTestResponse(t, charmap.KOI8R, russianText), "com", CharsetKOI8R, russianText},
+		{"ISO-8859-1", encodeTestResponse(t, charmap.ISO8859_1, frenchText), "fr", CharsetISO88591, frenchText},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			charset := detectCharset(tt.data, tt.tld)
+			if charset != tt.expected {
+				t.Fatalf("detectCharset() = %q, want %q", charset, tt.expected)
+			}
+
+			text, err := decodeCharset(tt.data, charset)
+			if err != nil {
+				t.Fatalf("decodeCharset(%q) unexpected error: %v", charset, err)
+			}
+			if text != tt.text {
+				t.Errorf("decodeCharset(%q) = %q, want %q", charset, text, tt.text)
+			}
+		})
+	}
+}
+
+func TestValidateWhoisCharset(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{"Shift_JIS", false},
+		{"shift-jis", false},
+		{"EUC-KR", false},
+		{"koi8-r", false},
+		{"latin1", false},
+		{"UTF-8", false},
+		{"klingon", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateWhoisCharset(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateWhoisCharset(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,9 +1,26 @@
 package query
 
-import "strings"
+import (
+	"net/netip"
+	"strings"
+
+	"regard/internal/rpsl"
+)
 
 // DetectQueryType determines the type of query based on the input string
 func DetectQueryType(query string) QueryType {
+	// CIDR prefixes, e.g. for netblock lookups
+	if strings.Contains(query, "/") {
+		if _, err := netip.ParsePrefix(query); err == nil {
+			return QueryTypeIP
+		}
+	}
+
+	// Reverse DNS names, e.g. "2.0.192.in-addr.arpa"
+	if _, ok := ParseReverseName(query); ok {
+		return QueryTypeReverse
+	}
+
 	// Simple heuristics to detect query type
 	if strings.Contains(query, ".") {
 		// Could be domain or IP
@@ -20,6 +37,11 @@ func DetectQueryType(query string) QueryType {
 		return QueryTypeDomain
 	}
 
+	// As-set names such as "AS-GOOGLE" or "AS15169:AS-GOOGLE"
+	if rpsl.IsASSetName(query) {
+		return QueryTypeASSet
+	}
+
 	// Check for ASN pattern (AS followed by numbers)
 	if strings.HasPrefix(strings.ToUpper(query), "AS") && len(query) > 2 {
 		return QueryTypeASN
@@ -27,12 +27,26 @@ func TestDetectQueryType(t *testing.T) {
 		{"fe80::1", QueryTypeIP},
 		{"2001:db8::1", QueryTypeIP},
 
+		// CIDR prefixes
+		{"8.8.8.0/24", QueryTypeIP},
+		{"2001:db8::/32", QueryTypeIP},
+
 		// ASN tests
 		{"AS15169", QueryTypeASN},
 		{"as13335", QueryTypeASN},
 		{"AS1", QueryTypeASN},
 		{"AS999999", QueryTypeASN},
 
+		// Reverse DNS tests
+		{"5.4.3.2.in-addr.arpa", QueryTypeReverse},
+		{"2.0.192.in-addr.arpa.", QueryTypeReverse},
+		{"8.b.d.0.1.0.0.2.ip6.arpa", QueryTypeReverse},
+		{"in-addr.arpa", QueryTypeDomain},
+
+		// As-set tests
+		{"AS-GOOGLE", QueryTypeASSet},
+		{"AS15169:AS-GOOGLE", QueryTypeASSet},
+
 		// Edge cases - domains that look like IPs but have long segments
 		{"192.168.1.reallylong", QueryTypeDomain},
 		{"8.8.8.example", QueryTypeDomain},
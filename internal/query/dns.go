@@ -0,0 +1,369 @@
+package query
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	// dnsTimeout bounds each DNS exchange
+	dnsTimeout = 5 * time.Second
+	// dnsUDPSize is the EDNS0 payload size advertised, large enough for most DNSKEY sets
+	dnsUDPSize = 1232
+	// typeDNSKEY is the DNSKEY resource record type, which dnsmessage doesn't name
+	typeDNSKEY dnsmessage.Type = 48
+)
+
+// ErrUnreachable means this host has no route to a nameserver address, as
+// with IPv6 glue on a host without IPv6, so the address says nothing about
+// the nameserver
+var ErrUnreachable = errors.New("no route from this host")
+
+// DNSOptions configures the resolver used for live DNS checks
+type DNSOptions struct {
+	Resolver       string        // Recursive resolver host[:port]; the system resolver when empty
+	NameserverPort string        // Port for direct queries to a domain's nameservers (default 53)
+	Timeout        time.Duration // Per-exchange timeout (default 5s)
+}
+
+// DNSClient resolves records through a recursive resolver and queries
+// nameservers directly
+type DNSClient struct {
+	resolver       string
+	nameserverPort string
+	timeout        time.Duration
+}
+
+// DNSKEYRecord is a DNSKEY resource record
+type DNSKEYRecord struct {
+	Flags     uint16
+	Protocol  uint8
+	Algorithm uint8
+	PublicKey []byte
+}
+
+// NewDNSClient creates a DNS client from opts
+func NewDNSClient(opts DNSOptions) *DNSClient {
+	client := &DNSClient{
+		resolver:       opts.Resolver,
+		nameserverPort: opts.NameserverPort,
+		timeout:        opts.Timeout,
+	}
+	if client.resolver == "" {
+		client.resolver = systemResolver()
+	}
+	if _, _, err := net.SplitHostPort(client.resolver); err != nil {
+		client.resolver = net.JoinHostPort(strings.Trim(client.resolver, "[]"), "53")
+	}
+	if client.nameserverPort == "" {
+		client.nameserverPort = "53"
+	}
+	if client.timeout == 0 {
+		client.timeout = dnsTimeout
+	}
+	return client
+}
+
+// Server returns the resolver address queries are sent to
+func (c *DNSClient) Server() string {
+	return c.resolver
+}
+
+// LookupNS returns the nameservers of a zone, without trailing dots
+func (c *DNSClient) LookupNS(name string) ([]string, error) {
+	msg, err := c.resolve(name, dnsmessage.TypeNS)
+	if err != nil {
+		return nil, err
+	}
+	var nameservers []string
+	for _, answer := range msg.Answers {
+		if ns, ok := answer.Body.(*dnsmessage.NSResource); ok {
+			nameservers = append(nameservers, strings.TrimSuffix(ns.NS.String(), "."))
+		}
+	}
+	return nameservers, nil
+}
+
+// LookupAddrs returns the IPv4 and IPv6 addresses of a host
+func (c *DNSClient) LookupAddrs(host string) ([]string, error) {
+	var addrs []string
+	for _, qtype := range []dnsmessage.Type{dnsmessage.TypeA, dnsmessage.TypeAAAA} {
+		msg, err := c.resolve(host, qtype)
+		if err != nil {
+			return addrs, err
+		}
+		for _, answer := range msg.Answers {
+			switch body := answer.Body.(type) {
+			case *dnsmessage.AResource:
+				addrs = append(addrs, net.IP(body.A[:]).String())
+			case *dnsmessage.AAAAResource:
+				addrs = append(addrs, net.IP(body.AAAA[:]).String())
+			}
+		}
+	}
+	return addrs, nil
+}
+
+// LookupPTR returns the host names a reverse DNS name points to, without trailing dots
+func (c *DNSClient) LookupPTR(name string) ([]string, error) {
+	msg, err := c.resolve(name, dnsmessage.TypePTR)
+	if err != nil {
+		return nil, err
+	}
+	var hosts []string
+	for _, answer := range msg.Answers {
+		if ptr, ok := answer.Body.(*dnsmessage.PTRResource); ok {
+			hosts = append(hosts, strings.TrimSuffix(ptr.PTR.String(), "."))
+		}
+	}
+	return hosts, nil
+}
+
+// LookupDNSKEY returns the DNSKEY records published at a zone apex
+func (c *DNSClient) LookupDNSKEY(name string) ([]DNSKEYRecord, error) {
+	msg, err := c.resolve(name, typeDNSKEY)
+	if err != nil {
+		return nil, err
+	}
+	var keys []DNSKEYRecord
+	for _, answer := range msg.Answers {
+		body, ok := answer.Body.(*dnsmessage.UnknownResource)
+		if !ok || answer.Header.Type != typeDNSKEY || len(body.Data) < 4 {
+			continue
+		}
+		keys = append(keys, DNSKEYRecord{
+			Flags:     binary.BigEndian.Uint16(body.Data[0:2]),
+			Protocol:  body.Data[2],
+			Algorithm: body.Data[3],
+			PublicKey: append([]byte(nil), body.Data[4:]...),
+		})
+	}
+	return keys, nil
+}
+
+// CheckAuthoritative asks a nameserver address directly for a zone's SOA,
+// returning an error unless it answers authoritatively, wrapping
+// ErrUnreachable when the address can't be reached from this host at all
+func (c *DNSClient) CheckAuthoritative(addr, zone string) error {
+	msg, err := c.exchange(net.JoinHostPort(addr, c.nameserverPort), zone, dnsmessage.TypeSOA, false)
+	if err != nil {
+		if isNoRoute(err) {
+			return fmt.Errorf("%w: %v", ErrUnreachable, err)
+		}
+		return err
+	}
+	if msg.RCode != dnsmessage.RCodeSuccess {
+		return fmt.Errorf("answered %s", rcodeName(msg.RCode))
+	}
+	if !msg.Authoritative {
+		return fmt.Errorf("answered without authority")
+	}
+	return nil
+}
+
+// isNoRoute reports whether a query failed locally because the host has no
+// route to, or no support for, the address family rather than for want of
+// an answer
+func isNoRoute(err error) bool {
+	return errors.Is(err, syscall.ENETUNREACH) || errors.Is(err, syscall.EHOSTUNREACH) ||
+		errors.Is(err, syscall.EADDRNOTAVAIL) || errors.Is(err, syscall.EAFNOSUPPORT)
+}
+
+// resolve sends a recursive query to the resolver
+func (c *DNSClient) resolve(name string, qtype dnsmessage.Type) (*dnsmessage.Message, error) {
+	msg, err := c.exchange(c.resolver, name, qtype, true)
+	if err != nil {
+		return nil, err
+	}
+	if msg.RCode != dnsmessage.RCodeSuccess {
+		return nil, fmt.Errorf("%s %s: %s", name, qtype, rcodeName(msg.RCode))
+	}
+	return msg, nil
+}
+
+// exchange sends a query over UDP, retrying over TCP if the answer is truncated
+func (c *DNSClient) exchange(server, name string, qtype dnsmessage.Type, recursive bool) (*dnsmessage.Message, error) {
+	qname, err := dnsmessage.NewName(strings.TrimSuffix(name, ".") + ".")
+	if err != nil {
+		return nil, fmt.Errorf("invalid name %q: %w", name, err)
+	}
+
+	var id [2]byte
+	_, _ = rand.Read(id[:])
+	query := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: binary.BigEndian.Uint16(id[:]), RecursionDesired: recursive},
+		Questions: []dnsmessage.Question{{Name: qname, Type: qtype, Class: dnsmessage.ClassINET}},
+	}
+	var opt dnsmessage.ResourceHeader
+	if err := opt.SetEDNS0(dnsUDPSize, dnsmessage.RCodeSuccess, true); err != nil {
+		return nil, err
+	}
+	query.Additionals = []dnsmessage.Resource{{Header: opt, Body: &dnsmessage.OPTResource{}}}
+
+	packed, err := query.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.exchangeUDP(server, packed)
+	if err == nil && response.Truncated {
+		response, err = c.exchangeTCP(server, packed)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("querying %s for %s %s: %w", server, name, qtype, err)
+	}
+	if response.ID != query.ID {
+		return nil, fmt.Errorf("querying %s for %s %s: mismatched response ID", server, name, qtype)
+	}
+	return response, nil
+}
+
+func (c *DNSClient) exchangeUDP(server string, packed []byte) (*dnsmessage.Message, error) {
+	conn, err := net.DialTimeout("udp", server, c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(c.timeout))
+
+	if _, err := conn.Write(packed); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 65535)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	var msg dnsmessage.Message
+	if err := msg.Unpack(buf[:n]); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+func (c *DNSClient) exchangeTCP(server string, packed []byte) (*dnsmessage.Message, error) {
+	conn, err := net.DialTimeout("tcp", server, c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(c.timeout))
+
+	// DNS over TCP prefixes each message with its length
+	framed := binary.BigEndian.AppendUint16(nil, uint16(len(packed)))
+	if _, err := conn.Write(append(framed, packed...)); err != nil {
+		return nil, err
+	}
+	var length [2]byte
+	if _, err := io.ReadFull(conn, length[:]); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, binary.BigEndian.Uint16(length[:]))
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		return nil, err
+	}
+
+	var msg dnsmessage.Message
+	if err := msg.Unpack(buf); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// rdata returns the DNSKEY RDATA in wire format
+func (k DNSKEYRecord) rdata() []byte {
+	data := binary.BigEndian.AppendUint16(nil, k.Flags)
+	data = append(data, k.Protocol, k.Algorithm)
+	return append(data, k.PublicKey...)
+}
+
+// KeyTag computes the key tag DS records use to refer to this key (RFC 4034 Appendix B)
+func (k DNSKEYRecord) KeyTag() uint16 {
+	var ac uint32
+	for i, b := range k.rdata() {
+		if i&1 == 0 {
+			ac += uint32(b) << 8
+		} else {
+			ac += uint32(b)
+		}
+	}
+	ac += ac >> 16 & 0xFFFF
+	return uint16(ac & 0xFFFF)
+}
+
+// DSDigest computes the digest a DS record for this key at owner would carry
+func (k DNSKEYRecord) DSDigest(owner string, digestType uint8) ([]byte, error) {
+	data := append(canonicalName(owner), k.rdata()...)
+	switch digestType {
+	case 1:
+		sum := sha1.Sum(data)
+		return sum[:], nil
+	case 2:
+		sum := sha256.Sum256(data)
+		return sum[:], nil
+	case 4:
+		sum := sha512.Sum384(data)
+		return sum[:], nil
+	default:
+		return nil, fmt.Errorf("unsupported DS digest type %d", digestType)
+	}
+}
+
+// canonicalName encodes a domain name in lowercase wire format
+func canonicalName(name string) []byte {
+	var wire []byte
+	for _, label := range strings.Split(strings.ToLower(strings.Trim(name, ".")), ".") {
+		if label == "" {
+			continue
+		}
+		wire = append(wire, byte(len(label)))
+		wire = append(wire, label...)
+	}
+	return append(wire, 0)
+}
+
+func rcodeName(rcode dnsmessage.RCode) string {
+	switch rcode {
+	case dnsmessage.RCodeNameError:
+		return "NXDOMAIN"
+	case dnsmessage.RCodeServerFailure:
+		return "SERVFAIL"
+	case dnsmessage.RCodeRefused:
+		return "REFUSED"
+	default:
+		return rcode.String()
+	}
+}
+
+// systemResolver returns the first nameserver in /etc/resolv.conf
+func systemResolver() string {
+	f, err := os.Open("/etc/resolv.conf")
+	if err != nil {
+		return "127.0.0.1:53"
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			return net.JoinHostPort(fields[1], "53")
+		}
+	}
+	return "127.0.0.1:53"
+}
@@ -0,0 +1,232 @@
+package query
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// startDNSStandIn serves records over UDP on a local port, keyed by
+// "name/TYPE". Names without records get NXDOMAIN.
+func startDNSStandIn(t *testing.T, records map[string][]dnsmessage.ResourceBody, authoritative bool) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 65535)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var query dnsmessage.Message
+			if err := query.Unpack(buf[:n]); err != nil || len(query.Questions) != 1 {
+				continue
+			}
+			q := query.Questions[0]
+			name := strings.TrimSuffix(q.Name.String(), ".")
+
+			response := dnsmessage.Message{
+				Header:    dnsmessage.Header{ID: query.ID, Response: true, Authoritative: authoritative},
+				Questions: query.Questions,
+			}
+			bodies, known := records[name+"/"+q.Type.String()]
+			if !known {
+				response.RCode = dnsmessage.RCodeNameError
+				for key := range records {
+					if strings.HasPrefix(key, name+"/") {
+						response.RCode = dnsmessage.RCodeSuccess
+					}
+				}
+			}
+			for _, body := range bodies {
+				response.Answers = append(response.Answers, dnsmessage.Resource{
+					Header: dnsmessage.ResourceHeader{Name: q.Name, Type: q.Type, Class: dnsmessage.ClassINET, TTL: 300},
+					Body:   body,
+				})
+			}
+			packed, err := response.Pack()
+			if err != nil {
+				continue
+			}
+			conn.WriteTo(packed, addr)
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+// rfc4034Key is the DNSKEY from the DS example in RFC 4034 section 5.4
+func rfc4034Key(t *testing.T) DNSKEYRecord {
+	t.Helper()
+	publicKey, err := base64.StdEncoding.DecodeString("AQOeiiR0GOMYkDshWoSKz9XzfwJr1AYtsmx3TGkJaNXVbfi/2pHm822aJ5iI9BMzNXxeYCmZDRD99WYwYqUSdjMmmAphXdvxegXd/M5+X7OrzKBaMbCVdFLUUh6DhweJBjEVv5f2wwjM9XzcnOf+EPbtG9DMBmADjFDc2w/rljwvFw==")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return DNSKEYRecord{Flags: 256, Protocol: 3, Algorithm: 5, PublicKey: publicKey}
+}
+
+func TestDNSKEYRecord_KeyTagAndDigest(t *testing.T) {
+	key := rfc4034Key(t)
+
+	if tag := key.KeyTag(); tag != 60485 {
+		t.Errorf("KeyTag() = %d, want 60485", tag)
+	}
+
+	digest, err := key.DSDigest("dskey.example.com.", 1)
+	if err != nil {
+		t.Fatalf("DSDigest() error: %v", err)
+	}
+	if got := strings.ToUpper(hex.EncodeToString(digest)); got != "2BB183AF5F22588179A53B0A98631FAD1A292118" {
+		t.Errorf("DSDigest() = %s, want 2BB183AF5F22588179A53B0A98631FAD1A292118", got)
+	}
+
+	if _, err := key.DSDigest("dskey.example.com", 3); err == nil {
+		t.Error("DSDigest() with GOST digest type: expected error")
+	}
+}
+
+func TestDNSClient_StandIn(t *testing.T) {
+	key := rfc4034Key(t)
+	keyData := append([]byte{0x01, 0x00, key.Protocol, key.Algorithm}, key.PublicKey...)
+
+	records := map[string][]dnsmessage.ResourceBody{
+		"example.test/TypeNS": {
+			&dnsmessage.NSResource{NS: dnsmessage.MustNewName("ns1.example.test.")},
+			&dnsmessage.NSResource{NS: dnsmessage.MustNewName("ns2.example.test.")},
+		},
+		"example.test/TypeSOA": {
+			&dnsmessage.SOAResource{NS: dnsmessage.MustNewName("ns1.example.test."), MBox: dnsmessage.MustNewName("hostmaster.example.test."), Serial: 1},
+		},
+		"example.test/" + typeDNSKEY.String(): {
+			&dnsmessage.UnknownResource{Type: typeDNSKEY, Data: keyData},
+		},
+		"1.0.0.127.in-addr.arpa/TypePTR": {&dnsmessage.PTRResource{PTR: dnsmessage.MustNewName("ns1.example.test.")}},
+		"ns1.example.test/TypeA":         {&dnsmessage.AResource{A: [4]byte{127, 0, 0, 1}}},
+		"ns1.example.test/TypeAAAA":      {&dnsmessage.AAAAResource{AAAA: [16]byte{15: 1}}},
+	}
+	server := startDNSStandIn(t, records, true)
+	_, port, _ := net.SplitHostPort(server)
+
+	client := NewDNSClient(DNSOptions{Resolver: server, NameserverPort: port, Timeout: 2 * time.Second})
+	if client.Server() != server {
+		t.Errorf("Server() = %q, want %q", client.Server(), server)
+	}
+
+	nameservers, err := client.LookupNS("example.test")
+	if err != nil {
+		t.Fatalf("LookupNS() error: %v", err)
+	}
+	if strings.Join(nameservers, ",") != "ns1.example.test,ns2.example.test" {
+		t.Errorf("LookupNS() = %v", nameservers)
+	}
+
+	addrs, err := client.LookupAddrs("ns1.example.test")
+	if err != nil {
+		t.Fatalf("LookupAddrs() error: %v", err)
+	}
+	if strings.Join(addrs, ",") != "127.0.0.1,::1" {
+		t.Errorf("LookupAddrs() = %v", addrs)
+	}
+	if _, err := client.LookupAddrs("ns2.example.test"); err == nil || !strings.Contains(err.Error(), "NXDOMAIN") {
+		t.Errorf("LookupAddrs(ns2) error = %v, want NXDOMAIN", err)
+	}
+
+	hosts, err := client.LookupPTR("1.0.0.127.in-addr.arpa")
+	if err != nil || strings.Join(hosts, ",") != "ns1.example.test" {
+		t.Errorf("LookupPTR() = %v, %v", hosts, err)
+	}
+
+	keys, err := client.LookupDNSKEY("example.test")
+	if err != nil {
+		t.Fatalf("LookupDNSKEY() error: %v", err)
+	}
+	if len(keys) != 1 || keys[0].Flags != 256 || keys[0].KeyTag() != 60485 {
+		t.Errorf("LookupDNSKEY() = %+v", keys)
+	}
+
+	if err := client.CheckAuthoritative("127.0.0.1", "example.test"); err != nil {
+		t.Errorf("CheckAuthoritative() error: %v", err)
+	}
+}
+
+func TestDNSClient_CheckAuthoritative_Lame(t *testing.T) {
+	records := map[string][]dnsmessage.ResourceBody{
+		"example.test/TypeSOA": {
+			&dnsmessage.SOAResource{NS: dnsmessage.MustNewName("ns1.example.test."), MBox: dnsmessage.MustNewName("hostmaster.example.test."), Serial: 1},
+		},
+	}
+	server := startDNSStandIn(t, records, false)
+	_, port, _ := net.SplitHostPort(server)
+
+	client := NewDNSClient(DNSOptions{Resolver: server, NameserverPort: port, Timeout: 2 * time.Second})
+	err := client.CheckAuthoritative("127.0.0.1", "example.test")
+	if err == nil {
+		t.Error("CheckAuthoritative() on a non-authoritative server: expected error")
+	}
+	if errors.Is(err, ErrUnreachable) {
+		t.Errorf("CheckAuthoritative() on a reachable server = %v, want it not to wrap ErrUnreachable", err)
+	}
+}
+
+func TestIsNoRoute(t *testing.T) {
+	dialError := func(errno syscall.Errno) error {
+		opErr := &net.OpError{Op: "dial", Net: "udp", Err: os.NewSyscallError("connect", errno)}
+		return fmt.Errorf("querying [2001:db8::1]:53 for example.test SOA: %w", opErr)
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"Network unreachable", dialError(syscall.ENETUNREACH), true},
+		{"Host unreachable", dialError(syscall.EHOSTUNREACH), true},
+		{"No IPv6 support", dialError(syscall.EAFNOSUPPORT), true},
+		{"Connection refused", dialError(syscall.ECONNREFUSED), false},
+		{"Answered without authority", errors.New("answered without authority"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isNoRoute(tt.err); got != tt.expected {
+				t.Errorf("isNoRoute(%v) = %v, want %v", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewDNSClient_Defaults(t *testing.T) {
+	tests := []struct {
+		resolver string
+		expected string
+	}{
+		{"192.0.2.53", "192.0.2.53:53"},
+		{"192.0.2.53:5353", "192.0.2.53:5353"},
+		{"2001:db8::53", "[2001:db8::53]:53"},
+		{"[2001:db8::53]:5353", "[2001:db8::53]:5353"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.resolver, func(t *testing.T) {
+			client := NewDNSClient(DNSOptions{Resolver: tt.resolver})
+			if client.Server() != tt.expected {
+				t.Errorf("NewDNSClient(%q).Server() = %q, want %q", tt.resolver, client.Server(), tt.expected)
+			}
+		})
+	}
+}
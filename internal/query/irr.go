@@ -0,0 +1,153 @@
+package query
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultIRRServer answers as-set and route object queries when none is configured
+const defaultIRRServer = "whois.radb.net"
+
+// ErrIRRNotFound is returned when the IRR has no object with the queried key
+var ErrIRRNotFound = errors.New("not found in the IRR")
+
+// IRROptions configures which IRR server is queried
+type IRROptions struct {
+	Server  string   // host[:port], whois.radb.net by default
+	Sources []string // IRR databases to search, such as RADB or RIPE; all by default
+}
+
+// IRRClient sends IRRd "!" queries (as RADb and the RIRs' IRRs accept) over a
+// single connection kept open between queries
+type IRRClient struct {
+	Server string
+
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// DialIRR connects to the configured IRR server and restricts queries to the
+// configured sources
+func DialIRR(opts IRROptions) (*IRRClient, error) {
+	server := opts.Server
+	if server == "" {
+		server = defaultIRRServer
+	}
+	host, port := splitWhoisServer(server)
+
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, port), whoisTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to IRR server %s: %w", server, err)
+	}
+	client := &IRRClient{Server: server, conn: conn, reader: bufio.NewReader(conn)}
+
+	// "!!" keeps the connection open after each answer; it has no response
+	if err := client.send("!!"); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	if len(opts.Sources) > 0 {
+		if _, err := client.query("!s" + strings.Join(opts.Sources, ",")); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+	}
+	return client, nil
+}
+
+// SetMembers returns the direct members of an as-set: AS numbers and other sets
+func (c *IRRClient) SetMembers(name string) ([]string, error) {
+	data, err := c.query("!i" + name)
+	if err != nil {
+		return nil, fmt.Errorf("as-set %s: %w", name, err)
+	}
+	return strings.Fields(data), nil
+}
+
+// RoutePrefixes returns the prefixes of the route and route6 objects with the
+// given origin AS
+func (c *IRRClient) RoutePrefixes(asn string) ([]string, error) {
+	asn = "AS" + strings.TrimPrefix(strings.ToUpper(asn), "AS")
+
+	var prefixes []string
+	for _, command := range []string{"!g", "!6"} {
+		data, err := c.query(command + asn)
+		if err != nil && !errors.Is(err, ErrIRRNotFound) {
+			return nil, fmt.Errorf("route objects for %s: %w", asn, err)
+		}
+		prefixes = append(prefixes, strings.Fields(data)...)
+	}
+	return prefixes, nil
+}
+
+// Close ends the session
+func (c *IRRClient) Close() error {
+	_ = c.send("!q")
+	return c.conn.Close()
+}
+
+func (c *IRRClient) send(command string) error {
+	if err := c.conn.SetDeadline(time.Now().Add(whoisTimeout)); err != nil {
+		return err
+	}
+	_, err := io.WriteString(c.conn, command+"\n")
+	return err
+}
+
+// query sends a command and reads its answer: "A<length>" followed by that
+// many bytes of data and "C", or a bare "C" (no data), "D" (key not found)
+// or "F <message>"
+func (c *IRRClient) query(command string) (string, error) {
+	if err := c.send(command); err != nil {
+		return "", err
+	}
+
+	status, err := c.readLine()
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case strings.HasPrefix(status, "A"):
+		length, err := strconv.Atoi(status[1:])
+		if err != nil || length < 0 {
+			return "", fmt.Errorf("invalid IRR response %q", status)
+		}
+		data := make([]byte, length)
+		if _, err := io.ReadFull(c.reader, data); err != nil {
+			return "", err
+		}
+		for {
+			line, err := c.readLine()
+			if err != nil {
+				return "", err
+			}
+			if line == "C" {
+				return string(data), nil
+			}
+			if line != "" {
+				return "", fmt.Errorf("unexpected IRR response %q", line)
+			}
+		}
+	case status == "C":
+		return "", nil
+	case status == "D":
+		return "", ErrIRRNotFound
+	case strings.HasPrefix(status, "F"):
+		return "", fmt.Errorf("IRR server error: %s", strings.TrimSpace(status[1:]))
+	}
+	return "", fmt.Errorf("unexpected IRR response %q", status)
+}
+
+func (c *IRRClient) readLine() (string, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("reading IRR response: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
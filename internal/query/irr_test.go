@@ -0,0 +1,101 @@
+package query
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// startIRRStandIn answers IRRd "!" queries from a map of command to data on a
+// local port, reporting every command it receives. Commands missing from the
+// map are answered "D" (key not found).
+func startIRRStandIn(t *testing.T, answers map[string]string) (string, <-chan string) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan string, 32)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			command := strings.TrimSpace(line)
+			received <- command
+			switch {
+			case command == "!!":
+			case command == "!q":
+				return
+			case strings.HasPrefix(command, "!s"):
+				fmt.Fprint(conn, "C\n")
+			default:
+				data, ok := answers[command]
+				switch {
+				case !ok:
+					fmt.Fprint(conn, "D\n")
+				case data == "":
+					fmt.Fprint(conn, "C\n")
+				default:
+					fmt.Fprintf(conn, "A%d\n%s\nC\n", len(data)+1, data)
+				}
+			}
+		}
+	}()
+
+	return listener.Addr().String(), received
+}
+
+func TestIRRClient(t *testing.T) {
+	server, received := startIRRStandIn(t, map[string]string{
+		"!iAS-EXAMPLE": "AS64500 AS64501 AS-CUSTOMERS",
+		"!gAS64500":    "192.0.2.0/24 198.51.100.0/24",
+		"!6AS64500":    "2001:db8::/32",
+		"!gAS64501":    "",
+	})
+
+	client, err := DialIRR(IRROptions{Server: server, Sources: []string{"RADB", "RIPE"}})
+	if err != nil {
+		t.Fatalf("DialIRR() unexpected error: %v", err)
+	}
+
+	members, err := client.SetMembers("AS-EXAMPLE")
+	if err != nil || !reflect.DeepEqual(members, []string{"AS64500", "AS64501", "AS-CUSTOMERS"}) {
+		t.Errorf("SetMembers(AS-EXAMPLE) = %v, %v", members, err)
+	}
+	if _, err := client.SetMembers("AS-MISSING"); !errors.Is(err, ErrIRRNotFound) {
+		t.Errorf("SetMembers(AS-MISSING) error = %v, want ErrIRRNotFound", err)
+	}
+
+	prefixes, err := client.RoutePrefixes("as64500")
+	if err != nil || !reflect.DeepEqual(prefixes, []string{"192.0.2.0/24", "198.51.100.0/24", "2001:db8::/32"}) {
+		t.Errorf("RoutePrefixes(AS64500) = %v, %v", prefixes, err)
+	}
+	if prefixes, err := client.RoutePrefixes("AS64501"); err != nil || len(prefixes) != 0 {
+		t.Errorf("RoutePrefixes(AS64501) = %v, %v; want none", prefixes, err)
+	}
+	client.Close()
+
+	var commands []string
+	for len(received) > 0 {
+		commands = append(commands, <-received)
+	}
+	expected := []string{"!!", "!sRADB,RIPE", "!iAS-EXAMPLE", "!iAS-MISSING", "!gAS64500", "!6AS64500", "!gAS64501", "!6AS64501"}
+	if len(commands) < len(expected) || !reflect.DeepEqual(commands[:len(expected)], expected) {
+		t.Errorf("Server received %v, want %v", commands, expected)
+	}
+}
@@ -1,37 +1,185 @@
 package query
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/openrdap/rdap"
 )
 
-// PerformRDAPQuery executes an RDAP query for the given input
+// rdapTimeout bounds a single RDAP lookup, including bootstrapping
+const rdapTimeout = 30 * time.Second
+
+// PerformRDAPQuery executes an RDAP query for the given input using the IANA
+// bootstrap registries
 func PerformRDAPQuery(query string) QueryResult {
+	return PerformRDAPQueryWithOptions(query, RDAPOptions{})
+}
+
+// PerformRDAPQueryWithOptions executes an RDAP query, locating the server as
+// configured by opts
+func PerformRDAPQueryWithOptions(query string, opts RDAPOptions) QueryResult {
+	return performRDAPQuery(DetectQueryType(query), query, opts)
+}
+
+// PerformRDAPNameserverQuery looks a nameserver host up at the RDAP server
+// for its TLD
+func PerformRDAPNameserverQuery(host string, opts RDAPOptions) QueryResult {
+	return performRDAPQuery(QueryTypeNameserver, strings.TrimSuffix(host, "."), opts)
+}
+
+func performRDAPQuery(queryType QueryType, query string, opts RDAPOptions) QueryResult {
 	result := QueryResult{
 		Query:     query,
-		Type:      string(DetectQueryType(query)),
+		Type:      string(queryType),
 		Protocol:  "RDAP",
 		Timestamp: time.Now(),
 	}
 
 	client := &rdap.Client{}
 
-	var response interface{}
-	var err error
-
+	req := &rdap.Request{Query: query}
 	switch result.Type {
 	case string(QueryTypeDomain):
-		response, err = client.QueryDomain(query)
+		req.Type = rdap.DomainRequest
 	case string(QueryTypeIP):
-		response, err = client.QueryIP(query)
+		req.Type = rdap.IPRequest
+	case string(QueryTypeNameserver):
+		req.Type = rdap.NameserverRequest
 	case string(QueryTypeASN):
-		response, err = client.QueryAutnum(query)
+		req.Type = rdap.AutnumRequest
+		// RDAP autnum paths take the bare number
+		req.Query = strings.TrimPrefix(strings.ToUpper(query), "AS")
 	default:
-		response, err = client.QueryDomain(query)
+		req.Type = rdap.DomainRequest
 	}
 
+	ctx, cancel := context.WithTimeout(context.Background(), rdapTimeout)
+	defer cancel()
+
+	servers, err := resolveRDAPServers(ctx, QueryType(result.Type), query, opts)
+	if err != nil {
+		result.Success = false
+		result.Error = err.Error()
+		return result
+	}
+
+	response, err := lookupRDAP(ctx, client, req, servers, &result)
+
+	// RIRs register reverse zones, usually down to a /24, not single
+	// addresses, so a name they don't hold is retried as its enclosing zones
+	if queryType == QueryTypeReverse && isRDAPNotFound(err) {
+		for _, zone := range EnclosingReverseNames(query) {
+			req.Query = zone
+			enclosingResponse, enclosingErr := lookupRDAP(ctx, client, req, servers, &result)
+			if isRDAPNotFound(enclosingErr) {
+				continue
+			}
+			if enclosingErr == nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("%s is not registered; showing the enclosing zone %s", query, zone))
+				response, err = enclosingResponse, enclosingErr
+			}
+			break
+		}
+	}
+
+	return rdapResult(result, response, err)
+}
+
+// lookupRDAP sends the request to each server in turn until one answers,
+// recording the server asked last in result
+func lookupRDAP(ctx context.Context, client *rdap.Client, req *rdap.Request, servers []*url.URL, result *QueryResult) (*rdap.Response, error) {
+	var response *rdap.Response
+	var err error
+	for _, server := range servers {
+		result.Server = server.String()
+		response, err = client.Do(req.WithServer(server).WithContext(ctx))
+		if err == nil {
+			if rdapErr, ok := response.Object.(*rdap.Error); ok {
+				err = fmt.Errorf("RDAP server returned an error: %s", rdapErrorText(rdapErr))
+			}
+			break
+		}
+
+		// Only try the next server if this one couldn't be reached
+		if isRDAPNotFound(err) {
+			break
+		}
+	}
+	return response, err
+}
+
+// isRDAPNotFound reports whether the server answered that the object doesn't exist
+func isRDAPNotFound(err error) bool {
+	var clientErr *rdap.ClientError
+	return errors.As(err, &clientErr) && clientErr.Type == rdap.ObjectDoesNotExist
+}
+
+// RDAPClient performs RDAP lookups with a fixed configuration
+type RDAPClient struct {
+	Options RDAPOptions
+}
+
+// Lookup queries RDAP for a domain, address, CIDR prefix or ASN
+func (c RDAPClient) Lookup(query string) QueryResult {
+	return PerformRDAPQueryWithOptions(query, c.Options)
+}
+
+// Follow fetches the object an RDAP link points to
+func (c RDAPClient) Follow(href string) QueryResult {
+	return PerformRDAPLinkQuery(href)
+}
+
+// PerformRDAPLinkQuery fetches an RDAP object from a link in another response,
+// such as an IP network's "up" link to its parent
+func PerformRDAPLinkQuery(href string) QueryResult {
+	result := QueryResult{
+		Query:     href,
+		Protocol:  "RDAP",
+		Timestamp: time.Now(),
+	}
+
+	link, err := url.Parse(href)
+	if err != nil || !link.IsAbs() {
+		result.Error = fmt.Sprintf("invalid RDAP link %q", href)
+		return result
+	}
+	result.Server = link.Scheme + "://" + link.Host
+
+	ctx, cancel := context.WithTimeout(context.Background(), rdapTimeout)
+	defer cancel()
+
+	client := &rdap.Client{}
+	req := &rdap.Request{Type: rdap.RawRequest, Server: link}
+	response, err := client.Do(req.WithContext(ctx))
+	if err == nil {
+		if rdapErr, ok := response.Object.(*rdap.Error); ok {
+			err = fmt.Errorf("RDAP server returned an error: %s", rdapErrorText(rdapErr))
+		}
+	}
+	if err == nil {
+		switch response.Object.(type) {
+		case *rdap.IPNetwork:
+			result.Type = string(QueryTypeIP)
+		case *rdap.Autnum:
+			result.Type = string(QueryTypeASN)
+		case *rdap.Nameserver:
+			result.Type = string(QueryTypeNameserver)
+		default:
+			result.Type = string(QueryTypeDomain)
+		}
+	}
+
+	return rdapResult(result, response, err)
+}
+
+// rdapResult completes a result with the decoded object and response body
+func rdapResult(result QueryResult, response *rdap.Response, err error) QueryResult {
 	if err != nil {
 		result.Success = false
 		result.Error = err.Error()
@@ -39,12 +187,43 @@ func PerformRDAPQuery(query string) QueryResult {
 	}
 
 	result.Success = true
-	result.Data = response
+	result.Data = response.Object
 
-	// Also store raw JSON for fallback
-	if rawBytes, err := json.Marshal(response); err == nil {
-		result.RawData = string(rawBytes)
+	// Keep the server's JSON body so members the RDAP library doesn't model
+	// (e.g. RFC 9537 redactions) remain available. Fall back to re-encoding
+	// the decoded object if no body was captured.
+	result.RawData = rdapResponseBody(response)
+	if result.RawData == "" {
+		if rawBytes, err := json.Marshal(response.Object); err == nil {
+			result.RawData = string(rawBytes)
+		}
 	}
 
 	return result
 }
+
+// rdapResponseBody returns the body of the HTTP response the object was decoded from
+func rdapResponseBody(response *rdap.Response) string {
+	for i := len(response.HTTP) - 1; i >= 0; i-- {
+		httpResponse := response.HTTP[i]
+		if httpResponse.Error == nil && len(httpResponse.Body) > 0 {
+			return string(httpResponse.Body)
+		}
+	}
+	return ""
+}
+
+func rdapErrorText(rdapErr *rdap.Error) string {
+	parts := []string{}
+	if rdapErr.ErrorCode != nil {
+		parts = append(parts, fmt.Sprintf("%d", *rdapErr.ErrorCode))
+	}
+	if rdapErr.Title != "" {
+		parts = append(parts, rdapErr.Title)
+	}
+	parts = append(parts, rdapErr.Description...)
+	if len(parts) == 0 {
+		return "unknown error"
+	}
+	return strings.Join(parts, " ")
+}
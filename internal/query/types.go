@@ -7,11 +7,17 @@ type QueryResult struct {
 	Query     string      `json:"query"`
 	Type      string      `json:"type"`
 	Protocol  string      `json:"protocol"`
+	Server    string      `json:"server,omitempty"`
 	Timestamp time.Time   `json:"timestamp"`
 	Success   bool        `json:"success"`
 	Data      interface{} `json:"data,omitempty"`
 	RawData   string      `json:"raw_data,omitempty"`
+	Charset   string      `json:"charset,omitempty"` // Character set the response was transcoded from
 	Error     string      `json:"error,omitempty"`
+	Warnings  []string    `json:"warnings,omitempty"` // Problems that didn't stop the lookup
+
+	// OriginalData holds the response bytes as received, before transcoding
+	OriginalData []byte `json:"-"`
 }
 
 // QueryType represents the type of query being performed
@@ -21,4 +27,11 @@ const (
 	QueryTypeDomain QueryType = "domain"
 	QueryTypeIP     QueryType = "ip"
 	QueryTypeASN    QueryType = "asn"
+	// QueryTypeNameserver is a nameserver host, looked up with RDAP /nameserver/
+	QueryTypeNameserver QueryType = "nameserver"
+	// QueryTypeASSet is an RPSL as-set name, resolved over IRR WHOIS
+	QueryTypeASSet QueryType = "as-set"
+	// QueryTypeReverse is an in-addr.arpa or ip6.arpa reverse DNS zone, looked
+	// up with RDAP /domain/ at the RIR holding the addresses it covers
+	QueryTypeReverse QueryType = "reverse"
 )
@@ -1,14 +1,54 @@
 package query
 
 import (
+	"fmt"
+	"io"
+	"net"
 	"strings"
 	"time"
 
-	"github.com/likexian/whois"
+	"regard/internal/iana"
 )
 
+const (
+	// ianaWhoisServer is asked for the authoritative server when none is configured
+	ianaWhoisServer = "whois.iana.org"
+	// arinWhoisServer needs an object type prefix to search networks or AS numbers only
+	arinWhoisServer = "whois.arin.net"
+	// whoisTimeout bounds each port-43 exchange
+	whoisTimeout = 30 * time.Second
+)
+
+// WhoisOptions configures which WHOIS server is queried and how
+type WhoisOptions struct {
+	Server    string            // Force this host[:port], bypassing discovery
+	Servers   map[string]string // TLD or zone to host[:port]
+	Templates map[string]string // Server host to query template, with %s replaced by the query
+	Charsets  map[string]string // Server host to response character set; detected when unset
+}
+
+// defaultWhoisTemplates holds the query syntax of servers that don't accept a
+// bare query, keyed by server host
+var defaultWhoisTemplates = map[string]string{
+	"whois.denic.de": "-T dn,ace %s",
+}
+
+// lessSpecificTemplates ask RIPE Database style servers for every network
+// enclosing an address. ARIN lists them by default; LACNIC has no equivalent.
+var lessSpecificTemplates = map[string]string{
+	"whois.ripe.net":    "-L %s",
+	"whois.apnic.net":   "-L %s",
+	"whois.afrinic.net": "-L %s",
+}
+
 // PerformWhoisQuery executes a WHOIS query for the given input
 func PerformWhoisQuery(query string) QueryResult {
+	return PerformWhoisQueryWithOptions(query, WhoisOptions{})
+}
+
+// PerformWhoisQueryWithOptions executes a WHOIS query, selecting the server
+// and query syntax as configured by opts
+func PerformWhoisQueryWithOptions(query string, opts WhoisOptions) QueryResult {
 	result := QueryResult{
 		Query:     query,
 		Type:      string(DetectQueryType(query)),
@@ -16,22 +56,248 @@ func PerformWhoisQuery(query string) QueryResult {
 		Timestamp: time.Now(),
 	}
 
-	response, err := whois.Whois(query)
+	server, err := resolveWhoisServer(QueryType(result.Type), query, opts)
 	if err != nil {
 		result.Success = false
 		result.Error = err.Error()
 		return result
 	}
 
-	result.Success = true
+	host, port := splitWhoisServer(server)
+	original, err := whoisExchange(server, formatWhoisQuery(host, query, opts.Templates))
+	if err != nil {
+		result.Success = false
+		result.Error = err.Error()
+		return result
+	}
+	result.Server = server
+	result.OriginalData = original
+	response := decodeWhoisResponse(&result, server, query, original, opts)
 	result.RawData = response
+	result.Data = parseWhoisResponse(QueryType(result.Type), query, response)
 
-	// Parse WHOIS data into a structured format for JSON output
-	result.Data = parseWhoisData(response)
+	// Follow a registry's referral to the registrar or RIR holding the record,
+	// in that server's own query syntax. The referral's answer is parsed on its
+	// own, with the registry's fields filling the gaps.
+	if referral := parseReferralServer(response); referral != "" {
+		referralHost, referralPort := splitWhoisServer(referral)
+		if referralHost != host || referralPort != port {
+			if referralOriginal, err := whoisExchange(referral, formatWhoisQuery(referralHost, query, opts.Templates)); err == nil {
+				result.Server = referral
+				result.OriginalData = append(result.OriginalData, referralOriginal...)
+				referralResponse := decodeWhoisResponse(&result, referral, query, referralOriginal, opts)
+				result.RawData = response + "\n" + referralResponse
+				result.Data = mergeWhoisData(parseWhoisResponse(QueryType(result.Type), query, referralResponse), result.Data.(map[string]interface{}))
+			}
+		}
+	}
 
+	result.Success = true
 	return result
 }
 
+// decodeWhoisResponse transcodes one server's response to UTF-8, using the
+// charset configured for that server (as host:port or host) or else the one
+// detected, and records the charset in result
+func decodeWhoisResponse(result *QueryResult, server, query string, original []byte, opts WhoisOptions) string {
+	host, _ := splitWhoisServer(server)
+	charset, ok := opts.Charsets[server]
+	if !ok {
+		charset, ok = opts.Charsets[host]
+	}
+	if !ok {
+		labels := strings.Split(strings.Trim(strings.ToLower(query), "."), ".")
+		charset = detectCharset(original, labels[len(labels)-1])
+	}
+	response := string(original)
+	if !strings.EqualFold(charset, CharsetUTF8) {
+		// A response that arrived is still worth showing undecoded
+		if decoded, err := decodeCharset(original, charset); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Response from %s shown undecoded: %v", server, err))
+			charset = ""
+		} else {
+			response = decoded
+		}
+	}
+	result.Charset = charset
+	return response
+}
+
+// mergeWhoisData keeps a referral's parsed response, adding the fields only
+// the registry's response has
+func mergeWhoisData(referral, registry map[string]interface{}) map[string]interface{} {
+	registryFields, _ := registry["parsed_fields"].(map[string]interface{})
+	if len(registryFields) == 0 {
+		return referral
+	}
+	fields, ok := referral["parsed_fields"].(map[string]interface{})
+	if !ok {
+		fields = make(map[string]interface{}, len(registryFields))
+		referral["parsed_fields"] = fields
+	}
+	for key, value := range registryFields {
+		if _, ok := fields[key]; !ok {
+			fields[key] = value
+		}
+	}
+	return referral
+}
+
+// PerformWhoisLessSpecificQuery looks an address up at its RIR, asking for
+// every less specific network as well as the most specific one
+func PerformWhoisLessSpecificQuery(query string, opts WhoisOptions) QueryResult {
+	templates := make(map[string]string, len(opts.Templates)+len(lessSpecificTemplates))
+	for host, template := range opts.Templates {
+		templates[host] = template
+	}
+	for host, template := range lessSpecificTemplates {
+		templates[host] = template
+	}
+	opts.Templates = templates
+	return PerformWhoisQueryWithOptions(query, opts)
+}
+
+// resolveWhoisServer picks the WHOIS server for a query: a forced server, the
+// configured per-TLD servers, the bundled root zone database, then a referral
+// from IANA
+func resolveWhoisServer(queryType QueryType, query string, opts WhoisOptions) (string, error) {
+	if opts.Server != "" {
+		return opts.Server, nil
+	}
+
+	name := strings.ToLower(strings.Trim(strings.TrimSpace(query), "."))
+
+	if queryType == QueryTypeDomain {
+		bestZone, bestServer := "", ""
+		for zone, server := range opts.Servers {
+			zone = strings.ToLower(strings.Trim(zone, "."))
+			if (name == zone || strings.HasSuffix(name, "."+zone)) && len(zone) > len(bestZone) {
+				bestZone, bestServer = zone, server
+			}
+		}
+		if bestServer != "" {
+			return bestServer, nil
+		}
+
+		labels := strings.Split(name, ".")
+		if tld, ok := iana.LookupTLD(labels[len(labels)-1]); ok && tld.WhoisServer != "" {
+			return tld.WhoisServer, nil
+		}
+	}
+
+	// Ask IANA which server is authoritative for the TLD, address or ASN
+	referralQuery := name
+	if queryType == QueryTypeDomain {
+		labels := strings.Split(name, ".")
+		referralQuery = labels[len(labels)-1]
+	}
+	// Reverse zones are held by the RIR holding their addresses
+	if prefix, ok := ParseReverseName(name); ok && queryType == QueryTypeReverse {
+		referralQuery = prefix.Addr().String()
+	}
+	response, err := whoisExchange(ianaWhoisServer, referralQuery)
+	if err != nil {
+		return "", fmt.Errorf("whois: query for whois server failed: %w", err)
+	}
+	if server := parseWhoisReferral(string(response)); server != "" {
+		return server, nil
+	}
+
+	return "", fmt.Errorf("whois: no whois server known for %s", query)
+}
+
+// parseWhoisReferral extracts the server named by an IANA referral response
+func parseWhoisReferral(response string) string {
+	for _, key := range []string{"refer:", "whois:"} {
+		for _, line := range strings.Split(response, "\n") {
+			line = strings.TrimSpace(line)
+			if strings.HasPrefix(strings.ToLower(line), key) {
+				if server := strings.TrimSpace(line[len(key):]); server != "" {
+					return server
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// parseReferralServer extracts the server a registry response refers to: the
+// registrar's WHOIS server, or another RIR's from ARIN. Referrals to other
+// protocols, such as rwhois:// or web pages, are not followed.
+func parseReferralServer(response string) string {
+	for _, line := range strings.Split(response, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(key) {
+		case "registrar whois server", "referralserver":
+		default:
+			continue
+		}
+		server := strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(value), "whois://"), "/")
+		if server == "" || strings.Contains(server, "/") {
+			return ""
+		}
+		return server
+	}
+	return ""
+}
+
+// formatWhoisQuery applies the server's query template, if it has one. ARIN
+// is sent "n +" or "a +" so it searches networks or AS numbers only, unless a
+// template says otherwise.
+func formatWhoisQuery(host string, query string, templates map[string]string) string {
+	template, ok := templates[host]
+	if !ok {
+		template, ok = defaultWhoisTemplates[host]
+	}
+	if !ok && host == arinWhoisServer {
+		template, ok = "n + %s", true
+		if DetectQueryType(query) == QueryTypeASN {
+			template = "a + %s"
+		}
+	}
+	if !ok || !strings.Contains(template, "%s") {
+		return query
+	}
+	return strings.ReplaceAll(template, "%s", query)
+}
+
+// whoisExchange sends one request to a WHOIS server and returns the response
+// bytes as received, read until the server closes the connection
+func whoisExchange(server, request string) ([]byte, error) {
+	host, port := splitWhoisServer(server)
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, port), whoisTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("whois: connect to %s failed: %w", server, err)
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(whoisTimeout)); err != nil {
+		return nil, fmt.Errorf("whois: set deadline for %s failed: %w", server, err)
+	}
+
+	if _, err := io.WriteString(conn, request+"\r\n"); err != nil {
+		return nil, fmt.Errorf("whois: send to %s failed: %w", server, err)
+	}
+	// Servers that refuse a query, e.g. over a rate limit, may close the
+	// connection early; what they sent is still the answer
+	response, err := io.ReadAll(conn)
+	if err != nil && len(response) == 0 {
+		return nil, fmt.Errorf("whois: read from %s failed: %w", server, err)
+	}
+	return response, nil
+}
+
+// splitWhoisServer splits host[:port], defaulting to port 43
+func splitWhoisServer(server string) (string, string) {
+	host, port, err := net.SplitHostPort(server)
+	if err != nil {
+		return strings.ToLower(server), "43"
+	}
+	return strings.ToLower(host), port
+}
+
 func parseWhoisData(whoisData string) map[string]interface{} {
 	data := make(map[string]interface{})
 	lines := strings.Split(whoisData, "\n")
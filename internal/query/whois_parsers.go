@@ -0,0 +1,602 @@
+package query
+
+import (
+	"net"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// whoisParser extracts the normalized WHOIS field model from a registry's
+// response. Field names follow the ICANN layout understood by the generic
+// parser (domain_name, creation_date, registry_expiry_date, registrar,
+// registrant_name, ...); name_server, name_server_glue and domain_status hold
+// []string.
+type whoisParser struct {
+	name  string
+	parse func(response string) map[string]interface{}
+}
+
+var (
+	nominetParser    = whoisParser{"nominet", parseNominetWhois}
+	denicParser      = whoisParser{"denic", parseDENICWhois}
+	jprsParser       = whoisParser{"jprs", parseJPRSWhois}
+	registroBRParser = whoisParser{"registro.br", parseRegistroBRWhois}
+	afnicParser      = whoisParser{"afnic", parseAFNICWhois}
+	euridParser      = whoisParser{"eurid", parseEURidWhois}
+)
+
+// whoisParsers holds the parsers for registries whose responses don't follow
+// the ICANN "Key: value" layout, keyed by TLD
+var whoisParsers = map[string]whoisParser{
+	"uk": nominetParser,
+	"de": denicParser,
+	"jp": jprsParser,
+	"br": registroBRParser,
+	"fr": afnicParser,
+	"re": afnicParser,
+	"pm": afnicParser,
+	"tf": afnicParser,
+	"wf": afnicParser,
+	"yt": afnicParser,
+	"eu": euridParser,
+}
+
+// parseWhoisResponse parses a WHOIS response with the parser for the queried
+// TLD, falling back to the generic parser when there is none or it finds nothing
+func parseWhoisResponse(queryType QueryType, query string, response string) map[string]interface{} {
+	if queryType == QueryTypeDomain {
+		labels := strings.Split(strings.ToLower(strings.TrimSuffix(query, ".")), ".")
+		if parser, ok := whoisParsers[labels[len(labels)-1]]; ok {
+			if fields := parser.parse(response); len(fields) > 0 {
+				return map[string]interface{}{
+					"parsed_fields": fields,
+					"parser":        parser.name,
+					"raw_response":  response,
+				}
+			}
+		}
+	}
+
+	return parseWhoisData(response)
+}
+
+// whoisFields accumulates normalized fields, skipping empty values
+type whoisFields map[string]interface{}
+
+func (f whoisFields) set(key, value string) {
+	if value = strings.TrimSpace(value); value != "" {
+		f[key] = value
+	}
+}
+
+// setFirst sets a field unless an earlier line already did
+func (f whoisFields) setFirst(key, value string) {
+	if _, ok := f[key]; !ok {
+		f.set(key, value)
+	}
+}
+
+func (f whoisFields) add(key, value string) {
+	if value = strings.TrimSpace(value); value != "" {
+		values, _ := f[key].([]string)
+		f[key] = append(values, value)
+	}
+}
+
+// setDate stores a date as RFC 3339 when it matches one of the registry's
+// layouts, otherwise as written
+func (f whoisFields) setDate(key, value string, loc *time.Location, layouts ...string) {
+	value = strings.TrimSpace(value)
+	for _, layout := range layouts {
+		if date, err := time.ParseInLocation(layout, value, loc); err == nil {
+			f[key] = date.Format(time.RFC3339)
+			return
+		}
+	}
+	f.set(key, value)
+}
+
+// addNameserver records the host of a nameserver line, and any glue
+// addresses as "host address" entries in name_server_glue
+func (f whoisFields) addNameserver(line string) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return
+	}
+	host := strings.ToLower(strings.TrimSuffix(fields[0], "."))
+	f.add("name_server", host)
+
+	// Glue follows the host as "192.0.2.1", "[192.0.2.1]" or "(192.0.2.1, 2001:db8::1)"
+	for _, field := range fields[1:] {
+		for _, addr := range strings.Split(strings.Trim(field, "[](),"), ",") {
+			if net.ParseIP(addr) != nil {
+				f.add("name_server_glue", host+" "+addr)
+			}
+		}
+	}
+}
+
+// parseIndentedBlocks reads the "Heading:" / indented value layout used by
+// Nominet and EURid, returning the value lines of each lower-cased heading
+func parseIndentedBlocks(response string) map[string][]string {
+	blocks := map[string][]string{}
+	heading := ""
+	headingIndent := 0
+
+	for _, line := range strings.Split(response, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+
+		if heading != "" && indent > headingIndent {
+			blocks[heading] = append(blocks[heading], trimmed)
+			continue
+		}
+
+		heading = ""
+		if strings.HasSuffix(trimmed, ":") {
+			heading = strings.ToLower(strings.TrimSuffix(trimmed, ":"))
+			headingIndent = indent
+			if _, ok := blocks[heading]; !ok {
+				blocks[heading] = nil
+			}
+		}
+	}
+
+	return blocks
+}
+
+// splitKeyValue splits a "Key: value" line, lower-casing the key
+func splitKeyValue(line string) (string, string, bool) {
+	key, value, found := strings.Cut(line, ":")
+	if !found {
+		return "", "", false
+	}
+	return strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value), true
+}
+
+// nominetTag matches the registrar tag Nominet appends to registrar names
+var nominetTag = regexp.MustCompile(`\s*\[Tag = ([^\]]+)\]$`)
+
+// parseNominetWhois parses Nominet (.uk) responses, which put each value on
+// an indented line below its heading
+func parseNominetWhois(response string) map[string]interface{} {
+	blocks := parseIndentedBlocks(response)
+	fields := whoisFields{}
+
+	if names := blocks["domain name"]; len(names) > 0 {
+		fields.set("domain_name", names[0])
+	}
+
+	for i, line := range blocks["registrar"] {
+		if key, value, ok := splitKeyValue(line); ok && key == "url" {
+			fields.set("registrar_url", value)
+			continue
+		}
+		if i == 0 {
+			if tag := nominetTag.FindStringSubmatch(line); tag != nil {
+				fields.set("registrar_tag", tag[1])
+			}
+			fields.set("registrar", nominetTag.ReplaceAllString(line, ""))
+		}
+	}
+
+	for _, line := range blocks["relevant dates"] {
+		key, value, ok := splitKeyValue(line)
+		if !ok {
+			continue
+		}
+		switch key {
+		case "registered on":
+			fields.setDate("creation_date", value, time.UTC, "02-Jan-2006")
+		case "expiry date":
+			fields.setDate("registry_expiry_date", value, time.UTC, "02-Jan-2006")
+		case "last updated":
+			fields.setDate("updated_date", value, time.UTC, "02-Jan-2006")
+		}
+	}
+
+	for _, line := range blocks["registration status"] {
+		fields.add("domain_status", strings.TrimSuffix(line, "."))
+	}
+
+	for _, line := range blocks["name servers"] {
+		if !strings.HasPrefix(strings.ToLower(line), "no name servers") {
+			fields.addNameserver(line)
+		}
+	}
+
+	if dnssec := blocks["dnssec"]; len(dnssec) > 0 {
+		if strings.EqualFold(dnssec[0], "signed") {
+			fields.set("dnssec", "signedDelegation")
+		} else {
+			fields.set("dnssec", "unsigned")
+		}
+	}
+
+	if registrant := blocks["registrant"]; len(registrant) > 0 {
+		fields.set("registrant_name", registrant[0])
+	}
+	if address := blocks["registrant's address"]; len(address) > 0 {
+		fields.set("registrant_street", strings.Join(address, ", "))
+	}
+
+	return fields
+}
+
+// parseDENICWhois parses DENIC (.de) responses. DENIC publishes no dates
+// other than the last change, and reports registered domains as "connect".
+func parseDENICWhois(response string) map[string]interface{} {
+	fields := whoisFields{}
+
+	for _, line := range strings.Split(response, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "%") {
+			continue
+		}
+		// Contact sections such as [Tech-C] follow the domain data
+		if strings.HasPrefix(line, "[") {
+			break
+		}
+
+		key, value, ok := splitKeyValue(line)
+		if !ok {
+			continue
+		}
+		switch key {
+		case "domain":
+			fields.set("domain_name", value)
+		case "nserver":
+			fields.addNameserver(value)
+		case "dnskey":
+			fields.set("dnssec", "signedDelegation")
+		case "status":
+			fields.add("domain_status", value)
+		case "changed":
+			fields.setDate("updated_date", value, time.UTC, time.RFC3339)
+		}
+	}
+
+	if _, ok := fields["domain_name"]; ok {
+		if _, signed := fields["dnssec"]; !signed {
+			fields.set("dnssec", "unsigned")
+		}
+	}
+
+	return fields
+}
+
+// jprsLabel matches JPRS "[Label]   value" lines, with the optional "a." item
+// prefix used for .co.jp and other organizational domains
+var jprsLabel = regexp.MustCompile(`^(?:[a-z]\.\s*)?\[([^\]]+)\]\s*(.*)$`)
+
+// jprsStateDate matches the date in a JPRS state such as "Connected (2026/03/31)"
+var jprsStateDate = regexp.MustCompile(`^(.*?)\s*\((\d{4}/\d{2}/\d{2})\)$`)
+
+// parseJPRSWhois parses JPRS (.jp) responses with their bracketed labels, in
+// English or Japanese
+func parseJPRSWhois(response string) map[string]interface{} {
+	fields := whoisFields{}
+	jst := time.FixedZone("JST", 9*60*60)
+	dateLayouts := []string{"2006/01/02 15:04:05", "2006/01/02"}
+
+	for _, line := range strings.Split(response, "\n") {
+		match := jprsLabel.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+		label := strings.ToLower(strings.TrimSpace(match[1]))
+		value := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(match[2]), "(JST)"))
+
+		switch label {
+		case "domain name", "ドメイン名":
+			fields.setFirst("domain_name", value)
+		case "registrant", "登録者名":
+			fields.setFirst("registrant_name", value)
+		case "organization", "組織名":
+			fields.setFirst("registrant_organization", value)
+		case "name server", "ネームサーバ":
+			fields.addNameserver(value)
+		case "signing key", "署名鍵":
+			if value != "" {
+				fields.set("dnssec", "signedDelegation")
+			}
+		case "created on", "registered date", "登録年月日":
+			fields.setDate("creation_date", value, jst, dateLayouts...)
+		case "expires on", "有効期限":
+			fields.setDate("registry_expiry_date", value, jst, dateLayouts...)
+		case "last updated", "last update", "最終更新":
+			fields.setDate("updated_date", value, jst, dateLayouts...)
+		case "status", "state", "状態":
+			// Organizational domains give the expiry with the state
+			if state := jprsStateDate.FindStringSubmatch(value); state != nil {
+				value = state[1]
+				if _, ok := fields["registry_expiry_date"]; !ok {
+					fields.setDate("registry_expiry_date", state[2], jst, dateLayouts...)
+				}
+			}
+			fields.add("domain_status", value)
+		}
+	}
+
+	if _, ok := fields["domain_name"]; ok {
+		if _, signed := fields["dnssec"]; !signed {
+			fields.set("dnssec", "unsigned")
+		}
+	}
+
+	return fields
+}
+
+// rpslBlocks splits a response into blank-line separated blocks of
+// "key: value" attributes, skipping comment lines
+func rpslBlocks(response string) [][][2]string {
+	var blocks [][][2]string
+	var current [][2]string
+
+	for _, line := range strings.Split(response, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			if len(current) > 0 {
+				blocks = append(blocks, current)
+				current = nil
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "%") {
+			continue
+		}
+		if key, value, ok := splitKeyValue(line); ok {
+			current = append(current, [2]string{key, value})
+		}
+	}
+	if len(current) > 0 {
+		blocks = append(blocks, current)
+	}
+
+	return blocks
+}
+
+// blockValue returns the first value of a key in a block
+func blockValue(block [][2]string, key string) string {
+	for _, attr := range block {
+		if attr[0] == key {
+			return attr[1]
+		}
+	}
+	return ""
+}
+
+// rpslContact copies a contact block referenced by handle into role-prefixed fields
+func rpslContact(fields whoisFields, blocks [][][2]string, handleKey, handle, prefix string, nameKeys ...string) {
+	if handle == "" {
+		return
+	}
+	fields.set(prefix+"_id", handle)
+
+	for _, block := range blocks {
+		if !strings.EqualFold(blockValue(block, handleKey), handle) {
+			continue
+		}
+		for _, key := range nameKeys {
+			if name := blockValue(block, key); name != "" {
+				fields.setFirst(prefix+"_name", name)
+			}
+		}
+		fields.set(prefix+"_email", blockValue(block, "e-mail"))
+		fields.set(prefix+"_phone", blockValue(block, "phone"))
+		fields.set(prefix+"_country", blockValue(block, "country"))
+
+		var address []string
+		for _, attr := range block {
+			if attr[0] == "address" {
+				address = append(address, attr[1])
+			}
+		}
+		fields.set(prefix+"_street", strings.Join(address, ", "))
+		return
+	}
+}
+
+// parseRegistroBRWhois parses registro.br (.br) responses, whose domain block
+// is followed by nic-hdl-br contact blocks
+func parseRegistroBRWhois(response string) map[string]interface{} {
+	fields := whoisFields{}
+	blocks := rpslBlocks(response)
+	brt := time.FixedZone("BRT", -3*60*60)
+
+	for _, block := range blocks {
+		if blockValue(block, "domain") == "" {
+			continue
+		}
+		for _, attr := range block {
+			key, value := attr[0], attr[1]
+			// Dates may carry a ticket number, e.g. "20000101 #123456"
+			date := value
+			if parts := strings.Fields(value); len(parts) > 0 {
+				date = parts[0]
+			}
+			switch key {
+			case "domain":
+				fields.set("domain_name", value)
+			case "owner":
+				fields.set("registrant_organization", value)
+			case "nserver":
+				fields.addNameserver(value)
+			case "dsrecord":
+				fields.set("dnssec", "signedDelegation")
+			case "created":
+				fields.setDate("creation_date", date, brt, "20060102")
+			case "changed":
+				fields.setDate("updated_date", date, brt, "20060102")
+			case "expires":
+				fields.setDate("registry_expiry_date", date, brt, "20060102")
+			case "status":
+				fields.add("domain_status", value)
+			}
+		}
+
+		rpslContact(fields, blocks, "nic-hdl-br", blockValue(block, "owner-c"), "registrant", "person")
+		rpslContact(fields, blocks, "nic-hdl-br", blockValue(block, "admin-c"), "admin", "person")
+		rpslContact(fields, blocks, "nic-hdl-br", blockValue(block, "tech-c"), "tech", "person")
+
+		if _, signed := fields["dnssec"]; !signed {
+			fields.set("dnssec", "unsigned")
+		}
+		break
+	}
+
+	return fields
+}
+
+// parseAFNICWhois parses AFNIC (.fr and overseas TLDs) responses, which split
+// the domain, nameserver, registrar and nic-hdl contact data into blocks
+func parseAFNICWhois(response string) map[string]interface{} {
+	fields := whoisFields{}
+	blocks := rpslBlocks(response)
+
+	var domainBlock [][2]string
+	for _, block := range blocks {
+		if blockValue(block, "domain") != "" {
+			domainBlock = block
+			break
+		}
+	}
+	if domainBlock == nil {
+		return fields
+	}
+
+	for _, attr := range domainBlock {
+		key, value := attr[0], attr[1]
+		switch key {
+		case "domain":
+			fields.set("domain_name", value)
+		case "status":
+			fields.add("domain_status", value)
+		case "registrar":
+			fields.set("registrar", value)
+		case "created":
+			fields.setDate("creation_date", value, time.UTC, time.RFC3339)
+		case "last-update":
+			fields.setDate("updated_date", value, time.UTC, time.RFC3339)
+		case "expiry date":
+			fields.setDate("registry_expiry_date", value, time.UTC, time.RFC3339)
+		}
+	}
+
+	// Nameservers and DNSSEC keys are listed in their own blocks
+	for _, block := range blocks {
+		for _, attr := range block {
+			switch {
+			case attr[0] == "nserver":
+				fields.addNameserver(attr[1])
+			case strings.HasPrefix(attr[0], "key1-"), attr[0] == "ds-rdata":
+				fields.set("dnssec", "signedDelegation")
+			}
+		}
+	}
+	if _, signed := fields["dnssec"]; !signed {
+		fields.set("dnssec", "unsigned")
+	}
+
+	// Registrar contact details follow in a block of their own
+	for _, block := range blocks {
+		if blockValue(block, "registrar") != "" && blockValue(block, "domain") == "" && blockValue(block, "nic-hdl") == "" {
+			fields.set("registrar_url", blockValue(block, "website"))
+			fields.set("registrar_abuse_contact_email", blockValue(block, "e-mail"))
+			fields.set("registrar_abuse_contact_phone", blockValue(block, "phone"))
+			break
+		}
+	}
+
+	rpslContact(fields, blocks, "nic-hdl", blockValue(domainBlock, "holder-c"), "registrant", "contact")
+	rpslContact(fields, blocks, "nic-hdl", blockValue(domainBlock, "admin-c"), "admin", "contact")
+	rpslContact(fields, blocks, "nic-hdl", blockValue(domainBlock, "tech-c"), "tech", "contact")
+
+	return fields
+}
+
+// parseEURidWhois parses EURid (.eu) responses. EURid publishes no dates or
+// status for registered domains, so a domain with data is reported as
+// registered.
+func parseEURidWhois(response string) map[string]interface{} {
+	fields := whoisFields{}
+
+	for _, line := range strings.Split(response, "\n") {
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") || strings.HasPrefix(line, "%") {
+			continue
+		}
+		key, value, ok := splitKeyValue(line)
+		if !ok {
+			continue
+		}
+		switch key {
+		case "domain":
+			fields.set("domain_name", value)
+		case "status":
+			fields.add("domain_status", value)
+		}
+	}
+	if _, ok := fields["domain_name"]; !ok {
+		return fields
+	}
+
+	blocks := parseIndentedBlocks(response)
+
+	contactBlocks := []struct {
+		heading string
+		prefix  string
+	}{
+		{"registrant", "registrant"},
+		{"technical", "tech"},
+	}
+	for _, cb := range contactBlocks {
+		for _, line := range blocks[cb.heading] {
+			key, value, ok := splitKeyValue(line)
+			if !ok {
+				continue
+			}
+			switch key {
+			case "name":
+				fields.set(cb.prefix+"_name", value)
+			case "organisation":
+				fields.set(cb.prefix+"_organization", value)
+			case "email":
+				fields.set(cb.prefix+"_email", value)
+			case "phone":
+				fields.set(cb.prefix+"_phone", value)
+			}
+		}
+	}
+
+	for _, line := range blocks["registrar"] {
+		if key, value, ok := splitKeyValue(line); ok {
+			switch key {
+			case "name":
+				fields.set("registrar", value)
+			case "website":
+				fields.set("registrar_url", value)
+			}
+		}
+	}
+
+	for _, line := range blocks["name servers"] {
+		fields.addNameserver(line)
+	}
+
+	if len(blocks["keys"]) > 0 {
+		fields.set("dnssec", "signedDelegation")
+	} else {
+		fields.set("dnssec", "unsigned")
+	}
+
+	if _, ok := fields["domain_status"]; !ok && len(blocks["registrar"]) > 0 {
+		fields.add("domain_status", "registered")
+	}
+
+	return fields
+}
@@ -0,0 +1,215 @@
+package query
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseWhoisResponse_Registries(t *testing.T) {
+	tests := []struct {
+		fixture  string
+		query    string
+		parser   string
+		expected map[string]interface{}
+	}{
+		{
+			fixture: "nominet.txt",
+			query:   "example.co.uk",
+			parser:  "nominet",
+			expected: map[string]interface{}{
+				"domain_name":          "example.co.uk",
+				"registrar":            "Ascio Technologies Inc t/a Ascio Technologies inc",
+				"registrar_tag":        "ASCIO",
+				"registrar_url":        "http://www.ascio.com",
+				"creation_date":        "1996-11-26T00:00:00Z",
+				"registry_expiry_date": "2026-11-26T00:00:00Z",
+				"updated_date":         "2025-10-05T00:00:00Z",
+				"domain_status":        []string{"Registered until expiry date"},
+				"name_server":          []string{"ns1.example.net", "ns2.example.net"},
+				"name_server_glue":     []string{"ns1.example.net 192.0.2.1"},
+				"dnssec":               "signedDelegation",
+			},
+		},
+		{
+			fixture: "denic.txt",
+			query:   "example.de",
+			parser:  "denic",
+			expected: map[string]interface{}{
+				"domain_name":   "example.de",
+				"name_server":   []string{"a.iana-servers.net", "b.iana-servers.net"},
+				"domain_status": []string{"connect"},
+				"updated_date":  "2018-03-12T21:44:25+01:00",
+				"dnssec":        "signedDelegation",
+			},
+		},
+		{
+			fixture: "denic-free.txt",
+			query:   "unregistered-example.de",
+			parser:  "denic",
+			expected: map[string]interface{}{
+				"domain_name":   "unregistered-example.de",
+				"domain_status": []string{"free"},
+			},
+		},
+		{
+			fixture: "jprs.txt",
+			query:   "example.jp",
+			parser:  "jprs",
+			expected: map[string]interface{}{
+				"domain_name":          "EXAMPLE.JP",
+				"registrant_name":      "Japan Registry Services Co., Ltd.",
+				"name_server":          []string{"ns1.example.jp", "ns2.example.jp"},
+				"creation_date":        "2001-02-03T00:00:00+09:00",
+				"registry_expiry_date": "2026-02-28T00:00:00+09:00",
+				"updated_date":         "2025-03-01T01:05:07+09:00",
+				"domain_status":        []string{"Active"},
+				"dnssec":               "unsigned",
+			},
+		},
+		{
+			fixture: "jprs-co.txt",
+			query:   "example.co.jp",
+			parser:  "jprs",
+			expected: map[string]interface{}{
+				"domain_name":             "EXAMPLE.CO.JP",
+				"registrant_organization": "Example Corporation",
+				"name_server":             []string{"ns1.example.co.jp", "ns2.example.co.jp"},
+				"creation_date":           "2001-04-01T00:00:00+09:00",
+				"registry_expiry_date":    "2026-03-31T00:00:00+09:00",
+				"updated_date":            "2025-04-01T01:05:04+09:00",
+				"domain_status":           []string{"Connected"},
+				"dnssec":                  "signedDelegation",
+			},
+		},
+		{
+			fixture: "registro-br.txt",
+			query:   "example.com.br",
+			parser:  "registro.br",
+			expected: map[string]interface{}{
+				"domain_name":             "example.com.br",
+				"registrant_organization": "Exemplo Servicos Ltda",
+				"registrant_id":           "EXL123",
+				"registrant_name":         "Maria Exemplo",
+				"registrant_email":        "hostmaster@example.com.br",
+				"registrant_country":      "BR",
+				"tech_id":                 "EXL124",
+				"tech_name":               "Joao Exemplo",
+				"tech_email":              "tech@example.com.br",
+				"tech_country":            "BR",
+				"name_server":             []string{"a.dns.br", "b.dns.br"},
+				"creation_date":           "2000-01-01T00:00:00-03:00",
+				"updated_date":            "2025-03-15T00:00:00-03:00",
+				"registry_expiry_date":    "2027-01-01T00:00:00-03:00",
+				"domain_status":           []string{"published"},
+				"dnssec":                  "signedDelegation",
+			},
+		},
+		{
+			fixture: "afnic.txt",
+			query:   "example.fr",
+			parser:  "afnic",
+			expected: map[string]interface{}{
+				"domain_name":                   "example.fr",
+				"domain_status":                 []string{"ACTIVE"},
+				"registrar":                     "EXAMPLE REGISTRAR SAS",
+				"registrar_url":                 "https://registrar.example",
+				"registrar_abuse_contact_email": "abuse@registrar.example",
+				"registrar_abuse_contact_phone": "+33.100000000",
+				"registry_expiry_date":          "2027-02-23T10:00:00Z",
+				"creation_date":                 "2004-02-23T10:00:00Z",
+				"updated_date":                  "2026-01-20T09:00:00Z",
+				"name_server":                   []string{"ns1.example.fr", "ns2.example.net"},
+				"name_server_glue":              []string{"ns1.example.fr 192.0.2.1"},
+				"dnssec":                        "signedDelegation",
+				"registrant_id":                 "ANO00-FRNIC",
+				"registrant_name":               "Ano Nymous",
+				"admin_id":                      "ANO00-FRNIC",
+				"admin_name":                    "Ano Nymous",
+				"tech_id":                       "EH123-FRNIC",
+				"tech_name":                     "Example Hosting",
+				"tech_email":                    "tech@example.fr",
+				"tech_phone":                    "+33.400000000",
+				"tech_country":                  "FR",
+				"tech_street":                   "2 avenue de l'Exemple, 69001 LYON",
+			},
+		},
+		{
+			fixture: "eurid.txt",
+			query:   "example.eu",
+			parser:  "eurid",
+			expected: map[string]interface{}{
+				"domain_name":       "example.eu",
+				"tech_organization": "Example Hosting BV",
+				"tech_email":        "tech@example.eu",
+				"registrar":         "Example Registrar NV",
+				"registrar_url":     "https://registrar.example",
+				"name_server":       []string{"ns1.example.eu", "ns2.example.net"},
+				"name_server_glue":  []string{"ns1.example.eu 192.0.2.1"},
+				"dnssec":            "signedDelegation",
+				"domain_status":     []string{"registered"},
+			},
+		},
+		{
+			fixture: "eurid-available.txt",
+			query:   "unregistered-example.eu",
+			parser:  "eurid",
+			expected: map[string]interface{}{
+				"domain_name":   "unregistered-example.eu",
+				"domain_status": []string{"AVAILABLE"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			response, err := os.ReadFile(filepath.Join("testdata", "whois", tt.fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data := parseWhoisResponse(QueryTypeDomain, tt.query, string(response))
+			if data["parser"] != tt.parser {
+				t.Errorf("parser = %v, want %q", data["parser"], tt.parser)
+			}
+			if data["raw_response"] != string(response) {
+				t.Error("raw_response should preserve original data")
+			}
+
+			fields, ok := data["parsed_fields"].(map[string]interface{})
+			if !ok {
+				t.Fatal("Expected parsed_fields to be a map[string]interface{}")
+			}
+			for key, want := range tt.expected {
+				if got := fields[key]; !reflect.DeepEqual(got, want) {
+					t.Errorf("%s = %#v, want %#v", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseWhoisResponse_Fallback(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		response string
+	}{
+		{"no registry parser", "example.com", "Domain Name: EXAMPLE.COM\nRegistrar: Example Registrar\n"},
+		{"registry parser finds nothing", "example.de", "Domain Name: EXAMPLE.DE\nRegistrar: Example Registrar\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := parseWhoisResponse(QueryTypeDomain, tt.query, tt.response)
+			if _, ok := data["parser"]; ok {
+				t.Errorf("Expected generic parser, got %v", data["parser"])
+			}
+			fields, _ := data["parsed_fields"].(map[string]interface{})
+			if fields["registrar"] != "Example Registrar" {
+				t.Errorf("registrar = %v, want %q", fields["registrar"], "Example Registrar")
+			}
+		})
+	}
+}
@@ -1,9 +1,14 @@
 package query
 
 import (
+	"bufio"
+	"bytes"
+	"net"
 	"strings"
 	"testing"
 	"time"
+
+	"golang.org/x/text/encoding/korean"
 )
 
 func TestParseWhoisData(t *testing.T) {
@@ -123,3 +128,276 @@ func TestPerformWhoisQuery_Structure(t *testing.T) {
 		t.Error("Expected Error to be set when Success is false")
 	}
 }
+
+func TestFormatWhoisQuery(t *testing.T) {
+	tests := []struct {
+		name      string
+		host      string
+		query     string
+		templates map[string]string
+		expected  string
+	}{
+		{"DENIC default", "whois.denic.de", "example.de", nil, "-T dn,ace example.de"},
+		{"no template", "whois.verisign-grs.com", "example.com", nil, "example.com"},
+		{"custom template", "whois.example.net", "example.test", map[string]string{"whois.example.net": "domain %s"}, "domain example.test"},
+		{"custom overrides default", "whois.denic.de", "example.de", map[string]string{"whois.denic.de": "%s"}, "example.de"},
+		{"template without placeholder", "whois.example.net", "example.test", map[string]string{"whois.example.net": "-x"}, "example.test"},
+		{"ARIN network", "whois.arin.net", "8.8.8.8", nil, "n + 8.8.8.8"},
+		{"ARIN AS number", "whois.arin.net", "AS15169", nil, "a + AS15169"},
+		{"ARIN custom template", "whois.arin.net", "8.8.8.0/24", map[string]string{"whois.arin.net": "n + > %s"}, "n + > 8.8.8.0/24"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatWhoisQuery(tt.host, tt.query, tt.templates); got != tt.expected {
+				t.Errorf("formatWhoisQuery(%q, %q) = %q, want %q", tt.host, tt.query, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseWhoisReferral(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		expected string
+	}{
+		{"refer line", "% IANA WHOIS server\n\nrefer:        whois.nic.io\n\ndomain:       IO\n", "whois.nic.io"},
+		{"whois line", "domain:       EXAMPLE\nwhois:        whois.example.net\n", "whois.example.net"},
+		{"refer preferred", "whois:        whois.b.example\nrefer:        whois.a.example\n", "whois.a.example"},
+		{"no referral", "% No match\n", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseWhoisReferral(tt.response); got != tt.expected {
+				t.Errorf("parseWhoisReferral() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseReferralServer(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		expected string
+	}{
+		{"registrar", "Domain Name: EXAMPLE.COM\r\n   Registrar WHOIS Server: whois.example-registrar.com\r\n", "whois.example-registrar.com"},
+		{"ARIN to RIPE", "NetRange:       193.0.0.0 - 193.255.255.255\nReferralServer:  whois://whois.ripe.net\n", "whois.ripe.net"},
+		{"with port", "ReferralServer: whois://whois.example.net:4343/\n", "whois.example.net:4343"},
+		{"rwhois", "ReferralServer:  rwhois://rwhois.example.net:4321\n", ""},
+		{"web page", "Registrar WHOIS Server: https://www.example-registrar.com/whois\n", ""},
+		{"no referral", "Domain Name: EXAMPLE.DE\n", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseReferralServer(tt.response); got != tt.expected {
+				t.Errorf("parseReferralServer() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSplitWhoisServer(t *testing.T) {
+	tests := []struct {
+		server string
+		host   string
+		port   string
+	}{
+		{"whois.denic.de", "whois.denic.de", "43"},
+		{"localhost:4343", "localhost", "4343"},
+		{"WHOIS.Example.NET", "whois.example.net", "43"},
+		{"[::1]:4343", "::1", "4343"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.server, func(t *testing.T) {
+			host, port := splitWhoisServer(tt.server)
+			if host != tt.host || port != tt.port {
+				t.Errorf("splitWhoisServer(%q) = %q, %q, want %q, %q", tt.server, host, port, tt.host, tt.port)
+			}
+		})
+	}
+}
+
+func TestResolveWhoisServer(t *testing.T) {
+	opts := WhoisOptions{Servers: map[string]string{
+		"test":          "localhost:4343",
+		"internal.test": "localhost:4344",
+		"de":            "whois.example.de",
+	}}
+
+	tests := []struct {
+		query    string
+		opts     WhoisOptions
+		expected string
+	}{
+		{"example.test", opts, "localhost:4343"},
+		{"example.internal.test", opts, "localhost:4344"},
+		{"example.de", opts, "whois.example.de"},
+		{"example.com", opts, "whois.verisign-grs.com"},
+		{"example.com", WhoisOptions{Server: "localhost:4345", Servers: opts.Servers}, "localhost:4345"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query+"->"+tt.expected, func(t *testing.T) {
+			got, err := resolveWhoisServer(QueryTypeDomain, tt.query, tt.opts)
+			if err != nil {
+				t.Fatalf("resolveWhoisServer(%q) unexpected error: %v", tt.query, err)
+			}
+			if got != tt.expected {
+				t.Errorf("resolveWhoisServer(%q) = %q, want %q", tt.query, got, tt.expected)
+			}
+		})
+	}
+}
+
+// startWhoisStandIn serves a single WHOIS response on a local port, reporting
+// the query line it received
+func startWhoisStandIn(t *testing.T, response []byte) (string, <-chan string) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		received <- strings.TrimSpace(line)
+		conn.Write(response)
+	}()
+
+	return listener.Addr().String(), received
+}
+
+func TestPerformWhoisQueryWithOptions_LocalServer(t *testing.T) {
+	server, received := startWhoisStandIn(t, []byte("Domain Name: EXAMPLE.TEST\r\nRegistrar: Test Registrar\r\n"))
+
+	host, _ := splitWhoisServer(server)
+	result := PerformWhoisQueryWithOptions("example.test", WhoisOptions{
+		Server:    server,
+		Templates: map[string]string{host: "domain %s"},
+	})
+
+	if !result.Success {
+		t.Fatalf("Expected success, got error: %s", result.Error)
+	}
+	if result.Server != server {
+		t.Errorf("Expected Server = %q, got %q", server, result.Server)
+	}
+	if got := <-received; got != "domain example.test" {
+		t.Errorf("Expected templated query, server received %q", got)
+	}
+	if !strings.Contains(result.RawData, "Test Registrar") {
+		t.Errorf("Expected response in RawData, got %q", result.RawData)
+	}
+	if result.Charset != CharsetUTF8 {
+		t.Errorf("Expected Charset = %q, got %q", CharsetUTF8, result.Charset)
+	}
+}
+
+func TestPerformWhoisQueryWithOptions_Referral(t *testing.T) {
+	// The registrar answers in EUC-KR and states its own status
+	registrarText := "Domain Name: EXAMPLE.TEST\r\nDomain Status: clientTransferProhibited\r\nRegistrant Organization: 한국인터넷진흥원\r\n"
+	registrarBytes, err := korean.EUCKR.NewEncoder().Bytes([]byte(registrarText))
+	if err != nil {
+		t.Fatal(err)
+	}
+	registrar, registrarReceived := startWhoisStandIn(t, registrarBytes)
+	registryBytes := []byte("Domain Name: EXAMPLE.TEST\r\nRegistry Domain ID: 123_DOMAIN\r\nDomain Status: ok\r\nRegistrar WHOIS Server: " + registrar + "\r\n")
+	registry, _ := startWhoisStandIn(t, registryBytes)
+
+	result := PerformWhoisQueryWithOptions("example.test", WhoisOptions{
+		Server:   registry,
+		Charsets: map[string]string{registrar: "euc-kr"},
+	})
+	if !result.Success {
+		t.Fatalf("Expected success, got error: %s", result.Error)
+	}
+	if result.Server != registrar {
+		t.Errorf("Expected the registrar that answered as Server, got %q", result.Server)
+	}
+	if got := <-registrarReceived; got != "example.test" {
+		t.Errorf("Expected the bare query at the registrar, got %q", got)
+	}
+	if !strings.Contains(result.RawData, "Registrar WHOIS Server") || !strings.Contains(result.RawData, "한국인터넷진흥원") {
+		t.Errorf("Expected both responses, each decoded, in RawData, got %q", result.RawData)
+	}
+	if !bytes.Equal(result.OriginalData, append(registryBytes, registrarBytes...)) {
+		t.Errorf("Expected the bytes received from both servers in OriginalData")
+	}
+
+	fields := result.Data.(map[string]interface{})["parsed_fields"].(map[string]interface{})
+	expected := map[string]string{
+		"domain_status":           "clientTransferProhibited", // the registrar's, not mixed with the registry's
+		"registrant_organization": "한국인터넷진흥원",
+		"registry_domain_id":      "123_DOMAIN", // only the registry states it
+	}
+	for key, want := range expected {
+		if fields[key] != want {
+			t.Errorf("parsed_fields[%q] = %v, want %q", key, fields[key], want)
+		}
+	}
+}
+
+func TestPerformWhoisQueryWithOptions_Charset(t *testing.T) {
+	text := "Domain Name: example.kr\nRegistrant: 한국인터넷진흥원\n"
+	original, err := korean.EUCKR.NewEncoder().Bytes([]byte(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		charsets map[string]string
+	}{
+		{"detected", nil},
+		{"configured", map[string]string{"127.0.0.1": "euc-kr"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := startWhoisStandIn(t, original)
+
+			result := PerformWhoisQueryWithOptions("example.kr", WhoisOptions{Server: server, Charsets: tt.charsets})
+			if !result.Success {
+				t.Fatalf("Expected success, got error: %s", result.Error)
+			}
+			if !strings.EqualFold(result.Charset, CharsetEUCKR) {
+				t.Errorf("Expected Charset = %q, got %q", CharsetEUCKR, result.Charset)
+			}
+			if !strings.Contains(result.RawData, "한국인터넷진흥원") {
+				t.Errorf("Expected transcoded RawData, got %q", result.RawData)
+			}
+			if !bytes.Equal(result.OriginalData, original) {
+				t.Errorf("Expected OriginalData to hold the EUC-KR bytes as sent, got %q", result.OriginalData)
+			}
+		})
+	}
+}
+
+func TestPerformWhoisQueryWithOptions_UndecodableCharset(t *testing.T) {
+	original := []byte("Domain Name: example.test\r\nRegistrant: Example\r\n")
+	server, _ := startWhoisStandIn(t, original)
+
+	result := PerformWhoisQueryWithOptions("example.test", WhoisOptions{Server: server, Charsets: map[string]string{"127.0.0.1": "klingon"}})
+	if !result.Success {
+		t.Fatalf("Expected the response to be kept, got error: %s", result.Error)
+	}
+	if result.RawData != string(original) || result.Charset != "" {
+		t.Errorf("Expected the undecoded response, got %q in %q", result.RawData, result.Charset)
+	}
+	if len(result.Warnings) != 1 {
+		t.Errorf("Expected a warning about the charset, got %v", result.Warnings)
+	}
+}
@@ -0,0 +1,151 @@
+package routing
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/netip"
+	"os"
+	"path/filepath"
+)
+
+// indexMagic starts a cached routing table index; change it when the layout changes
+const indexMagic = "regard routing index 1\n"
+
+var errStaleIndex = errors.New("routing table index does not match the dump")
+
+// LoadCached loads a routing table dump as Load does, keeping a compact index
+// of it in cacheDir so later runs skip decompressing and parsing the dump. The
+// index is rebuilt whenever the dump's size or modification time changes.
+func LoadCached(path, cacheDir string) (*Table, error) {
+	if cacheDir == "" {
+		return Load(path)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening routing table: %w", err)
+	}
+
+	indexPath := indexPathFor(path, cacheDir)
+	if table, err := loadIndex(indexPath, info); err == nil {
+		table.Source = filepath.Base(path)
+		return table, nil
+	}
+
+	table, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	// The index only saves time on the next run, so failing to write it is not an error
+	_ = saveIndex(indexPath, info, table)
+	return table, nil
+}
+
+// indexPathFor names the index of a dump after a hash of its absolute path
+func indexPathFor(path, cacheDir string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	sum := sha256.Sum256([]byte(path))
+	return filepath.Join(cacheDir, "routing", hex.EncodeToString(sum[:8])+".idx")
+}
+
+// indexStamp identifies the dump an index was built from
+func indexStamp(info os.FileInfo) []byte {
+	stamp := binary.BigEndian.AppendUint64(nil, uint64(info.Size()))
+	return binary.BigEndian.AppendUint64(stamp, uint64(info.ModTime().UnixNano()))
+}
+
+// saveIndex writes the table's routes as the magic, the dump's stamp and the
+// route count, then per route the address length in bytes, the prefix length,
+// the significant prefix bytes, the origin count and the origins
+func saveIndex(indexPath string, info os.FileInfo, table *Table) error {
+	data := append([]byte(indexMagic), indexStamp(info)...)
+	data = binary.BigEndian.AppendUint32(data, uint32(len(table.routes)))
+	for prefix, origins := range table.routes {
+		addr := prefix.Addr().AsSlice()
+		data = append(data, byte(len(addr)), byte(prefix.Bits()))
+		data = append(data, addr[:(prefix.Bits()+7)/8]...)
+		data = binary.BigEndian.AppendUint16(data, uint16(len(origins)))
+		for _, origin := range origins {
+			data = binary.BigEndian.AppendUint32(data, origin)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(indexPath), 0o755); err != nil {
+		return err
+	}
+	// Write then rename, so a concurrent run never reads half an index
+	temp := fmt.Sprintf("%s.%d.tmp", indexPath, os.Getpid())
+	if err := os.WriteFile(temp, data, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(temp, indexPath); err != nil {
+		_ = os.Remove(temp)
+		return err
+	}
+	return nil
+}
+
+// loadIndex reads an index written by saveIndex, failing unless it was built
+// from the dump described by info
+func loadIndex(indexPath string, info os.FileInfo) (*Table, error) {
+	data, err := os.ReadFile(indexPath)
+	if err != nil {
+		return nil, err
+	}
+	header := append([]byte(indexMagic), indexStamp(info)...)
+	if len(data) < len(header)+4 || string(data[:len(header)]) != string(header) {
+		return nil, errStaleIndex
+	}
+	data = data[len(header):]
+	count := binary.BigEndian.Uint32(data)
+	data = data[4:]
+	// Each route takes at least four bytes; a larger count means a damaged index
+	if int(count) > len(data)/4 {
+		return nil, errStaleIndex
+	}
+
+	table := &Table{routes: make(map[netip.Prefix][]uint32, count)}
+	backing := make([]uint32, 0, count)
+	for i := uint32(0); i < count; i++ {
+		if len(data) < 2 {
+			return nil, errStaleIndex
+		}
+		addrLength, bits := int(data[0]), int(data[1])
+		prefixBytes := (bits + 7) / 8
+		if (addrLength != 4 && addrLength != 16) || bits > addrLength*8 || len(data) < 2+prefixBytes+2 {
+			return nil, errStaleIndex
+		}
+		var raw [16]byte
+		copy(raw[:], data[2:2+prefixBytes])
+		data = data[2+prefixBytes:]
+
+		addr := netip.AddrFrom16(raw)
+		if addrLength == 4 {
+			addr = netip.AddrFrom4([4]byte(raw[:4]))
+		}
+
+		originCount := int(binary.BigEndian.Uint16(data))
+		data = data[2:]
+		if len(data) < 4*originCount {
+			return nil, errStaleIndex
+		}
+		// Origins share one backing array rather than an allocation per route
+		start := len(backing)
+		for j := 0; j < originCount; j++ {
+			backing = append(backing, binary.BigEndian.Uint32(data[4*j:]))
+		}
+		origins := backing[start:len(backing):len(backing)]
+		data = data[4*originCount:]
+		table.routes[netip.PrefixFrom(addr, bits)] = origins
+	}
+	if len(data) != 0 {
+		return nil, errStaleIndex
+	}
+
+	table.indexLengths()
+	return table, nil
+}
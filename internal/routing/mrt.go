@@ -0,0 +1,180 @@
+package routing
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+)
+
+// MRT record layout (RFC 6396) and the TABLE_DUMP_V2 subtypes read here
+const (
+	mrtHeaderLength = 12
+	mrtTableDumpV2  = 13
+
+	subtypePeerIndexTable        = 1
+	subtypeRIBIPv4Unicast        = 2
+	subtypeRIBIPv6Unicast        = 4
+	subtypeRIBIPv4UnicastAddPath = 8 // RFC 8050
+	subtypeRIBIPv6UnicastAddPath = 10
+
+	attrASPath      = 2
+	attrFlagExtLen  = 0x10
+	segmentASSet    = 1
+	segmentSequence = 2
+)
+
+// readMRT reads the unicast RIB entries of a TABLE_DUMP_V2 dump, taking each
+// entry's origin from its AS_PATH
+func readMRT(r io.Reader, table *Table) error {
+	header := make([]byte, mrtHeaderLength)
+	var body []byte
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("reading MRT header: %w", err)
+		}
+		recordType := binary.BigEndian.Uint16(header[4:6])
+		subtype := binary.BigEndian.Uint16(header[6:8])
+		length := binary.BigEndian.Uint32(header[8:12])
+
+		if cap(body) < int(length) {
+			body = make([]byte, length)
+		}
+		body = body[:length]
+		if _, err := io.ReadFull(r, body); err != nil {
+			return fmt.Errorf("reading MRT record: %w", err)
+		}
+		if recordType != mrtTableDumpV2 {
+			continue
+		}
+
+		var err error
+		switch subtype {
+		case subtypeRIBIPv4Unicast:
+			err = readRIB(body, 32, false, table)
+		case subtypeRIBIPv6Unicast:
+			err = readRIB(body, 128, false, table)
+		case subtypeRIBIPv4UnicastAddPath:
+			err = readRIB(body, 32, true, table)
+		case subtypeRIBIPv6UnicastAddPath:
+			err = readRIB(body, 128, true, table)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+var errTruncated = errors.New("truncated MRT RIB entry")
+
+// readRIB reads a RIB_IPV4_UNICAST or RIB_IPV6_UNICAST record: the prefix,
+// then one entry per peer that announced it
+func readRIB(body []byte, addrBits int, addPath bool, table *Table) error {
+	// Sequence number (4), prefix length (1), prefix, entry count (2)
+	if len(body) < 5 {
+		return errTruncated
+	}
+	bits := int(body[4])
+	if bits > addrBits {
+		return fmt.Errorf("invalid MRT prefix length %d", bits)
+	}
+	prefixBytes := (bits + 7) / 8
+	offset := 5
+	if len(body) < offset+prefixBytes+2 {
+		return errTruncated
+	}
+
+	var addr netip.Addr
+	if addrBits == 32 {
+		var raw [4]byte
+		copy(raw[:], body[offset:offset+prefixBytes])
+		addr = netip.AddrFrom4(raw)
+	} else {
+		var raw [16]byte
+		copy(raw[:], body[offset:offset+prefixBytes])
+		addr = netip.AddrFrom16(raw)
+	}
+	prefix := netip.PrefixFrom(addr, bits)
+	offset += prefixBytes
+
+	entryCount := int(binary.BigEndian.Uint16(body[offset:]))
+	offset += 2
+
+	var origins []uint32
+	for i := 0; i < entryCount; i++ {
+		// Peer index (2), originated time (4), path identifier (4, add-path only), attribute length (2)
+		entryHeader := 8
+		if addPath {
+			entryHeader += 4
+		}
+		if len(body) < offset+entryHeader {
+			return errTruncated
+		}
+		attrLength := int(binary.BigEndian.Uint16(body[offset+entryHeader-2:]))
+		offset += entryHeader
+		if len(body) < offset+attrLength {
+			return errTruncated
+		}
+		for _, asn := range pathOrigins(body[offset : offset+attrLength]) {
+			if !containsASN(origins, asn) {
+				origins = append(origins, asn)
+			}
+		}
+		offset += attrLength
+	}
+
+	if len(origins) > 0 {
+		table.add(prefix, origins)
+	}
+	return nil
+}
+
+// pathOrigins finds the AS_PATH among BGP path attributes and returns its
+// origin: the last AS of a final AS_SEQUENCE, or every AS of a final AS_SET.
+// TABLE_DUMP_V2 always encodes AS numbers in four bytes.
+func pathOrigins(attrs []byte) []uint32 {
+	for len(attrs) >= 3 {
+		flags, attrType := attrs[0], attrs[1]
+		headerLength, length := 3, int(attrs[2])
+		if flags&attrFlagExtLen != 0 {
+			if len(attrs) < 4 {
+				return nil
+			}
+			headerLength, length = 4, int(binary.BigEndian.Uint16(attrs[2:4]))
+		}
+		if len(attrs) < headerLength+length {
+			return nil
+		}
+		value := attrs[headerLength : headerLength+length]
+		attrs = attrs[headerLength+length:]
+		if attrType != attrASPath {
+			continue
+		}
+
+		var origins []uint32
+		for len(value) >= 2 {
+			segmentType, count := value[0], int(value[1])
+			if len(value) < 2+4*count {
+				return nil
+			}
+			asns := make([]uint32, count)
+			for i := range asns {
+				asns[i] = binary.BigEndian.Uint32(value[2+4*i:])
+			}
+			value = value[2+4*count:]
+
+			switch {
+			case segmentType == segmentSequence && count > 0:
+				origins = asns[count-1:]
+			case segmentType == segmentASSet:
+				origins = asns
+			}
+		}
+		return origins
+	}
+	return nil
+}
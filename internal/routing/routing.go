@@ -0,0 +1,240 @@
+// Package routing loads local routing table dumps, CAIDA pfx2as files and MRT
+// RIB dumps (RFC 6396), and answers longest-prefix-match and origin queries
+package routing
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Route is a prefix as announced in the routing table and the ASes originating it
+type Route struct {
+	Prefix  netip.Prefix `json:"prefix"`
+	Origins []uint32     `json:"origins"` // More than one for multi-origin (MOAS) prefixes
+	Source  string       `json:"source"`  // The table dump the route came from
+}
+
+// Table is a routing table held for longest-prefix matching
+type Table struct {
+	Source string // File name of the dump
+
+	routes  map[netip.Prefix][]uint32
+	lengths [2][]int // Prefix lengths present for IPv4 and IPv6, longest first
+
+	originsOnce sync.Once
+	byOrigin    map[uint32][]netip.Prefix
+}
+
+// Load reads a routing table dump. pfx2as text and MRT TABLE_DUMP_V2 files are
+// recognised by their content, optionally gzip or bzip2 compressed.
+func Load(path string) (*Table, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening routing table: %w", err)
+	}
+	defer file.Close()
+
+	table, err := Read(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading routing table %s: %w", path, err)
+	}
+	table.Source = filepath.Base(path)
+	return table, nil
+}
+
+// Read reads a routing table dump from r, as Load does for a file
+func Read(r io.Reader) (*Table, error) {
+	reader, err := decompress(bufio.NewReaderSize(r, 1<<16))
+	if err != nil {
+		return nil, err
+	}
+
+	table := &Table{routes: make(map[netip.Prefix][]uint32)}
+	header, err := reader.Peek(mrtHeaderLength)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if isMRT(header) {
+		err = readMRT(reader, table)
+	} else {
+		err = readPfx2as(reader, table)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	table.indexLengths()
+	return table, nil
+}
+
+// decompress unwraps gzip and bzip2 input, recognised by their magic bytes
+func decompress(r *bufio.Reader) (*bufio.Reader, error) {
+	magic, _ := r.Peek(3)
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return bufio.NewReaderSize(gz, 1<<16), nil
+	case bytes.Equal(magic, []byte("BZh")):
+		return bufio.NewReaderSize(bzip2.NewReader(r), 1<<16), nil
+	}
+	return r, nil
+}
+
+// readPfx2as reads CAIDA prefix-to-AS lines ("192.0.2.0<TAB>24<TAB>64500"),
+// or "prefix/length origin" lines. Origins are joined with "_" for multi-origin
+// prefixes and "," for AS sets.
+func readPfx2as(r io.Reader, table *Table) error {
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		prefixText, originText := "", ""
+		switch {
+		case len(fields) >= 3 && !strings.Contains(fields[0], "/"):
+			prefixText, originText = fields[0]+"/"+fields[1], fields[2]
+		case len(fields) >= 2:
+			prefixText, originText = fields[0], fields[1]
+		default:
+			return fmt.Errorf("line %d: expected a prefix and an origin AS", lineNumber)
+		}
+
+		prefix, err := netip.ParsePrefix(prefixText)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		var origins []uint32
+		for _, field := range strings.FieldsFunc(originText, func(r rune) bool { return r == '_' || r == ',' }) {
+			asn, err := strconv.ParseUint(strings.TrimPrefix(strings.ToUpper(field), "AS"), 10, 32)
+			if err != nil {
+				return fmt.Errorf("line %d: invalid origin AS %q", lineNumber, field)
+			}
+			origins = append(origins, uint32(asn))
+		}
+		table.add(prefix, origins)
+	}
+	return scanner.Err()
+}
+
+// add records the origins of a prefix, merging with those already seen
+func (t *Table) add(prefix netip.Prefix, origins []uint32) {
+	prefix = prefix.Masked()
+	merged := t.routes[prefix]
+	for _, asn := range origins {
+		if !containsASN(merged, asn) {
+			merged = append(merged, asn)
+		}
+	}
+	t.routes[prefix] = merged
+}
+
+func containsASN(asns []uint32, asn uint32) bool {
+	for _, existing := range asns {
+		if existing == asn {
+			return true
+		}
+	}
+	return false
+}
+
+// indexLengths records which prefix lengths occur, so lookups only probe those
+func (t *Table) indexLengths() {
+	seen := [2]map[int]bool{{}, {}}
+	for prefix, origins := range t.routes {
+		sort.Slice(origins, func(i, j int) bool { return origins[i] < origins[j] })
+		family := familyIndex(prefix.Addr())
+		if !seen[family][prefix.Bits()] {
+			seen[family][prefix.Bits()] = true
+			t.lengths[family] = append(t.lengths[family], prefix.Bits())
+		}
+	}
+	for _, lengths := range t.lengths {
+		sort.Sort(sort.Reverse(sort.IntSlice(lengths)))
+	}
+}
+
+func familyIndex(addr netip.Addr) int {
+	if addr.Is4() {
+		return 0
+	}
+	return 1
+}
+
+// Len returns the number of prefixes in the table
+func (t *Table) Len() int {
+	return len(t.routes)
+}
+
+// Lookup returns the most specific announced prefix containing the address
+func (t *Table) Lookup(addr netip.Addr) (Route, bool) {
+	addr = addr.WithZone("")
+	return t.LookupPrefix(netip.PrefixFrom(addr, addr.BitLen()))
+}
+
+// LookupPrefix returns the most specific announced prefix covering the whole
+// of the given prefix. IPv4-mapped IPv6 prefixes are looked up as IPv4.
+func (t *Table) LookupPrefix(prefix netip.Prefix) (Route, bool) {
+	if prefix.Addr().Is4In6() && prefix.Bits() >= 96 {
+		prefix = netip.PrefixFrom(prefix.Addr().Unmap(), prefix.Bits()-96)
+	}
+	for _, bits := range t.lengths[familyIndex(prefix.Addr())] {
+		if bits > prefix.Bits() {
+			continue
+		}
+		candidate := netip.PrefixFrom(prefix.Addr(), bits).Masked()
+		if origins, ok := t.routes[candidate]; ok {
+			return Route{Prefix: candidate, Origins: origins, Source: t.Source}, true
+		}
+	}
+	return Route{}, false
+}
+
+// Originated returns the prefixes an AS originates, IPv4 first and in address order
+func (t *Table) Originated(asn uint32) []netip.Prefix {
+	t.originsOnce.Do(func() {
+		t.byOrigin = make(map[uint32][]netip.Prefix)
+		for prefix, origins := range t.routes {
+			for _, origin := range origins {
+				t.byOrigin[origin] = append(t.byOrigin[origin], prefix)
+			}
+		}
+		for _, prefixes := range t.byOrigin {
+			sort.Slice(prefixes, func(i, j int) bool {
+				a, b := prefixes[i], prefixes[j]
+				if a.Addr().BitLen() != b.Addr().BitLen() {
+					return a.Addr().BitLen() < b.Addr().BitLen()
+				}
+				if c := a.Addr().Compare(b.Addr()); c != 0 {
+					return c < 0
+				}
+				return a.Bits() < b.Bits()
+			})
+		}
+	})
+	return t.byOrigin[asn]
+}
+
+// isMRT reports whether a header looks like an MRT TABLE_DUMP_V2 record
+func isMRT(header []byte) bool {
+	return len(header) >= mrtHeaderLength && binary.BigEndian.Uint16(header[4:6]) == mrtTableDumpV2
+}
@@ -0,0 +1,266 @@
+package routing
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const samplePfx2as = `1.0.0.0	24	13335
+8.8.8.0	24	15169
+8.0.0.0	9	3356
+192.0.2.0	24	64500_64501
+198.51.100.0	24	64502,64503
+2001:4860::	32	15169
+203.0.113.0/24 AS64510
+`
+
+func TestRead_Pfx2as(t *testing.T) {
+	table, err := Read(strings.NewReader(samplePfx2as))
+	if err != nil {
+		t.Fatalf("Read() unexpected error: %v", err)
+	}
+	if table.Len() != 7 {
+		t.Errorf("Len() = %d, want 7", table.Len())
+	}
+
+	tests := []struct {
+		addr          string
+		expectPrefix  string
+		expectOrigins []uint32
+	}{
+		{"8.8.8.8", "8.8.8.0/24", []uint32{15169}},
+		{"8.8.4.4", "8.0.0.0/9", []uint32{3356}},
+		{"192.0.2.1", "192.0.2.0/24", []uint32{64500, 64501}},
+		{"198.51.100.1", "198.51.100.0/24", []uint32{64502, 64503}},
+		{"2001:4860:4860::8888", "2001:4860::/32", []uint32{15169}},
+		{"::ffff:8.8.8.8", "8.8.8.0/24", []uint32{15169}},
+		{"203.0.113.9", "203.0.113.0/24", []uint32{64510}},
+		{"9.9.9.9", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			route, ok := table.Lookup(netip.MustParseAddr(tt.addr))
+			if ok != (tt.expectPrefix != "") {
+				t.Fatalf("Lookup(%s) found = %v, want %v", tt.addr, ok, tt.expectPrefix != "")
+			}
+			if !ok {
+				return
+			}
+			if route.Prefix.String() != tt.expectPrefix || !reflect.DeepEqual(route.Origins, tt.expectOrigins) {
+				t.Errorf("Lookup(%s) = %s %v, want %s %v", tt.addr, route.Prefix, route.Origins, tt.expectPrefix, tt.expectOrigins)
+			}
+		})
+	}
+
+	if route, ok := table.LookupPrefix(netip.MustParsePrefix("8.8.0.0/16")); !ok || route.Prefix.String() != "8.0.0.0/9" {
+		t.Errorf("LookupPrefix(8.8.0.0/16) = %s, %v; want 8.0.0.0/9", route.Prefix, ok)
+	}
+
+	var originated []string
+	for _, prefix := range table.Originated(15169) {
+		originated = append(originated, prefix.String())
+	}
+	if !reflect.DeepEqual(originated, []string{"8.8.8.0/24", "2001:4860::/32"}) {
+		t.Errorf("Originated(15169) = %v", originated)
+	}
+}
+
+func TestRead_Pfx2asInvalid(t *testing.T) {
+	if _, err := Read(strings.NewReader("8.8.8.0\t24\tnot-an-asn\n")); err == nil {
+		t.Error("Expected error for an invalid origin AS")
+	}
+}
+
+// mrtRecord builds an MRT record
+func mrtRecord(recordType, subtype uint16, body []byte) []byte {
+	header := make([]byte, mrtHeaderLength)
+	binary.BigEndian.PutUint32(header[0:], 1760400000)
+	binary.BigEndian.PutUint16(header[4:], recordType)
+	binary.BigEndian.PutUint16(header[6:], subtype)
+	binary.BigEndian.PutUint32(header[8:], uint32(len(body)))
+	return append(header, body...)
+}
+
+// ribRecord builds a RIB_IPV4_UNICAST or RIB_IPV6_UNICAST body with one entry
+// per AS path, each path a list of segments
+func ribRecord(prefix netip.Prefix, paths ...[][]uint32) []byte {
+	body := []byte{0, 0, 0, 1, byte(prefix.Bits())}
+	body = append(body, prefix.Addr().AsSlice()[:(prefix.Bits()+7)/8]...)
+	body = binary.BigEndian.AppendUint16(body, uint16(len(paths)))
+
+	for i, segments := range paths {
+		// ORIGIN attribute, then AS_PATH
+		attrs := []byte{0x40, 1, 1, 0}
+		var path []byte
+		for j, segment := range segments {
+			segmentType := byte(segmentSequence)
+			if j > 0 && len(segment) > 1 {
+				segmentType = segmentASSet
+			}
+			path = append(path, segmentType, byte(len(segment)))
+			for _, asn := range segment {
+				path = binary.BigEndian.AppendUint32(path, asn)
+			}
+		}
+		attrs = append(attrs, 0x40|attrFlagExtLen, attrASPath)
+		attrs = binary.BigEndian.AppendUint16(attrs, uint16(len(path)))
+		attrs = append(attrs, path...)
+
+		body = binary.BigEndian.AppendUint16(body, uint16(i))
+		body = binary.BigEndian.AppendUint32(body, 1760400000)
+		body = binary.BigEndian.AppendUint16(body, uint16(len(attrs)))
+		body = append(body, attrs...)
+	}
+	return body
+}
+
+func TestRead_MRT(t *testing.T) {
+	var dump bytes.Buffer
+	dump.Write(mrtRecord(mrtTableDumpV2, subtypePeerIndexTable, []byte{192, 0, 2, 1, 0, 0, 0, 0}))
+	dump.Write(mrtRecord(mrtTableDumpV2, subtypeRIBIPv4Unicast, ribRecord(netip.MustParsePrefix("8.8.8.0/24"),
+		[][]uint32{{3356, 15169}}, [][]uint32{{174, 15169}})))
+	dump.Write(mrtRecord(mrtTableDumpV2, subtypeRIBIPv4Unicast, ribRecord(netip.MustParsePrefix("192.0.2.0/23"),
+		[][]uint32{{3356, 64500}}, [][]uint32{{174, 64501}})))
+	dump.Write(mrtRecord(mrtTableDumpV2, subtypeRIBIPv4Unicast, ribRecord(netip.MustParsePrefix("198.51.100.0/24"),
+		[][]uint32{{3356}, {64502, 64503}})))
+	dump.Write(mrtRecord(mrtTableDumpV2, subtypeRIBIPv6Unicast, ribRecord(netip.MustParsePrefix("2001:4860::/32"),
+		[][]uint32{{6939, 15169}})))
+	// BGP4MP records in the same file are skipped
+	dump.Write(mrtRecord(16, 4, []byte{1, 2, 3}))
+
+	// Compressed the way RIPE RIS publishes its dumps
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	gz.Write(dump.Bytes())
+	gz.Close()
+
+	table, err := Read(&compressed)
+	if err != nil {
+		t.Fatalf("Read() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		addr          string
+		expectPrefix  string
+		expectOrigins []uint32
+	}{
+		{"8.8.8.8", "8.8.8.0/24", []uint32{15169}},
+		{"192.0.3.1", "192.0.2.0/23", []uint32{64500, 64501}},
+		{"198.51.100.1", "198.51.100.0/24", []uint32{64502, 64503}},
+		{"2001:4860::1", "2001:4860::/32", []uint32{15169}},
+	}
+	for _, tt := range tests {
+		route, ok := table.Lookup(netip.MustParseAddr(tt.addr))
+		if !ok || route.Prefix.String() != tt.expectPrefix || !reflect.DeepEqual(route.Origins, tt.expectOrigins) {
+			t.Errorf("Lookup(%s) = %s %v, %v; want %s %v", tt.addr, route.Prefix, route.Origins, ok, tt.expectPrefix, tt.expectOrigins)
+		}
+	}
+
+	truncated := mrtRecord(mrtTableDumpV2, subtypeRIBIPv4Unicast, []byte{0, 0, 0, 1, 24, 8})
+	if _, err := Read(bytes.NewReader(truncated)); err == nil {
+		t.Error("Expected error for a truncated RIB entry")
+	}
+}
+
+func TestLoadCached(t *testing.T) {
+	dir := t.TempDir()
+	dump := filepath.Join(dir, "routeviews-rv2-20251014-1200.pfx2as")
+	if err := os.WriteFile(dump, []byte(samplePfx2as), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cacheDir := filepath.Join(dir, "cache")
+
+	first, err := LoadCached(dump, cacheDir)
+	if err != nil {
+		t.Fatalf("LoadCached() unexpected error: %v", err)
+	}
+	indexes, _ := filepath.Glob(filepath.Join(cacheDir, "routing", "*.idx"))
+	if len(indexes) != 1 {
+		t.Fatalf("Expected one index in the cache directory, got %v", indexes)
+	}
+
+	// The second load reads the index rather than the dump
+	cached, err := LoadCached(dump, cacheDir)
+	if err != nil {
+		t.Fatalf("LoadCached() from index unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(cached.routes, first.routes) || !reflect.DeepEqual(cached.lengths, first.lengths) {
+		t.Errorf("Index routes = %v, want %v", cached.routes, first.routes)
+	}
+	if cached.Source != filepath.Base(dump) {
+		t.Errorf("Source = %q, want %q", cached.Source, filepath.Base(dump))
+	}
+
+	// A changed dump invalidates the index
+	if err := os.WriteFile(dump, []byte("9.9.9.0\t24\t19281\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(dump, later, later)
+	updated, err := LoadCached(dump, cacheDir)
+	if err != nil {
+		t.Fatalf("LoadCached() after change unexpected error: %v", err)
+	}
+	if updated.Len() != 1 {
+		t.Errorf("Len() after the dump changed = %d, want 1", updated.Len())
+	}
+
+	// A corrupt index is rebuilt from the dump
+	os.WriteFile(indexes[0], []byte(indexMagic+"garbage"), 0o644)
+	if rebuilt, err := LoadCached(dump, cacheDir); err != nil || rebuilt.Len() != 1 {
+		t.Errorf("LoadCached() with a corrupt index = %v, %v", rebuilt, err)
+	}
+}
+
+// realisticPfx2as builds a gzipped pfx2as dump the size of a full routing
+// table: about a million IPv4 and 200,000 IPv6 prefixes
+func realisticPfx2as(b *testing.B) []byte {
+	b.Helper()
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	for i := 0; i < 1_000_000; i++ {
+		fmt.Fprintf(gz, "%d.%d.%d.0\t24\t%d\n", 1+i>>16, (i>>8)&0xff, i&0xff, 64512+i%1000)
+	}
+	for i := 0; i < 200_000; i++ {
+		fmt.Fprintf(gz, "2001:%x:%x::\t48\t%d\n", i>>16, i&0xffff, 64512+i%1000)
+	}
+	gz.Close()
+	return compressed.Bytes()
+}
+
+func BenchmarkRead(b *testing.B) {
+	dump := realisticPfx2as(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Read(bytes.NewReader(dump)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkLoadCached(b *testing.B) {
+	dir := b.TempDir()
+	path := filepath.Join(dir, "routeviews-rv2-20251014-1200.pfx2as.gz")
+	if err := os.WriteFile(path, realisticPfx2as(b), 0o644); err != nil {
+		b.Fatal(err)
+	}
+	if _, err := LoadCached(path, dir); err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := LoadCached(path, dir); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+// Package rpki performs route origin validation (RFC 6811) against a local
+// export of validated ROA payloads, as written by Routinator or rpki-client
+package rpki
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// State is the outcome of route origin validation
+type State string
+
+// Validation states (RFC 6811)
+const (
+	Valid    State = "valid"
+	Invalid  State = "invalid"
+	NotFound State = "not-found"
+)
+
+// Reasons a route is invalid
+const (
+	ReasonOrigin    = "wrong origin AS"
+	ReasonMaxLength = "more specific than maxLength"
+)
+
+// VRP is a validated ROA payload: an AS authorised to originate a prefix, or
+// more specifics of it up to MaxLength
+type VRP struct {
+	Prefix      netip.Prefix `json:"prefix"`
+	MaxLength   int          `json:"max_length"`
+	ASN         uint32       `json:"asn"`
+	TrustAnchor string       `json:"ta,omitempty"`
+}
+
+// String formats a VRP as "192.0.2.0/24-24 AS64500"
+func (v VRP) String() string {
+	return fmt.Sprintf("%s-%d AS%d", v.Prefix, v.MaxLength, v.ASN)
+}
+
+// Validation is the validity of one route, a prefix and its origin AS
+type Validation struct {
+	Prefix netip.Prefix `json:"prefix"`
+	Origin uint32       `json:"origin"`
+	State  State        `json:"state"`
+	Reason string       `json:"reason,omitempty"` // Why an invalid route is invalid
+	VRPs   []VRP        `json:"vrps,omitempty"`   // The matching VRPs, or the covering ones for invalid routes
+}
+
+// Set is a VRP export indexed for covering-prefix lookups
+type Set struct {
+	Source string // File name of the export
+
+	vrps    map[netip.Prefix][]VRP
+	lengths [2][]int // Prefix lengths present for IPv4 and IPv6, longest first
+	count   int
+}
+
+// Load reads a VRP export file
+func Load(path string) (*Set, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening VRP export: %w", err)
+	}
+	defer file.Close()
+
+	set, err := Read(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading VRP export %s: %w", path, err)
+	}
+	set.Source = filepath.Base(path)
+	return set, nil
+}
+
+// exportEntry is one VRP in the JSON exports. Routinator writes the AS as
+// "AS64500" and rpki-client as a number; Routinator's jsonext format names
+// the trust anchor under "source".
+type exportEntry struct {
+	ASN       json.RawMessage `json:"asn"`
+	Prefix    string          `json:"prefix"`
+	MaxLength *int            `json:"maxLength"`
+	TA        string          `json:"ta"`
+	Source    []struct {
+		TAL string `json:"tal"`
+	} `json:"source"`
+}
+
+// Read reads a VRP export from r, as Load does for a file
+func Read(r io.Reader) (*Set, error) {
+	var export struct {
+		ROAs []exportEntry `json:"roas"`
+	}
+	if err := json.NewDecoder(bufio.NewReaderSize(r, 1<<16)).Decode(&export); err != nil {
+		return nil, err
+	}
+
+	set := &Set{vrps: make(map[netip.Prefix][]VRP)}
+	for i, entry := range export.ROAs {
+		vrp, err := entry.vrp()
+		if err != nil {
+			return nil, fmt.Errorf("roa %d: %w", i, err)
+		}
+		set.vrps[vrp.Prefix] = append(set.vrps[vrp.Prefix], vrp)
+		set.count++
+	}
+
+	seen := [2]map[int]bool{{}, {}}
+	for prefix := range set.vrps {
+		family := familyIndex(prefix.Addr())
+		if !seen[family][prefix.Bits()] {
+			seen[family][prefix.Bits()] = true
+			set.lengths[family] = append(set.lengths[family], prefix.Bits())
+		}
+	}
+	for _, lengths := range set.lengths {
+		sort.Sort(sort.Reverse(sort.IntSlice(lengths)))
+	}
+	return set, nil
+}
+
+func (e exportEntry) vrp() (VRP, error) {
+	prefix, err := netip.ParsePrefix(e.Prefix)
+	if err != nil {
+		return VRP{}, err
+	}
+	vrp := VRP{Prefix: prefix.Masked(), MaxLength: prefix.Bits(), TrustAnchor: e.TA}
+	if e.MaxLength != nil {
+		vrp.MaxLength = *e.MaxLength
+	}
+	if vrp.MaxLength < prefix.Bits() || vrp.MaxLength > prefix.Addr().BitLen() {
+		return VRP{}, fmt.Errorf("invalid maxLength %d for %s", vrp.MaxLength, prefix)
+	}
+	if vrp.TrustAnchor == "" && len(e.Source) > 0 {
+		vrp.TrustAnchor = e.Source[0].TAL
+	}
+
+	asText := strings.Trim(string(e.ASN), `"`)
+	asn, err := strconv.ParseUint(strings.TrimPrefix(strings.ToUpper(asText), "AS"), 10, 32)
+	if err != nil {
+		return VRP{}, fmt.Errorf("invalid asn %s", e.ASN)
+	}
+	vrp.ASN = uint32(asn)
+	return vrp, nil
+}
+
+func familyIndex(addr netip.Addr) int {
+	if addr.Is4() {
+		return 0
+	}
+	return 1
+}
+
+// Len returns the number of VRPs in the set
+func (s *Set) Len() int {
+	return s.count
+}
+
+// Covering returns the VRPs whose prefix covers the given prefix, most specific first
+func (s *Set) Covering(prefix netip.Prefix) []VRP {
+	prefix = prefix.Masked()
+	var covering []VRP
+	for _, bits := range s.lengths[familyIndex(prefix.Addr())] {
+		if bits > prefix.Bits() {
+			continue
+		}
+		covering = append(covering, s.vrps[netip.PrefixFrom(prefix.Addr(), bits).Masked()]...)
+	}
+	return covering
+}
+
+// Validate classifies a route as RFC 6811 does: valid when a covering VRP
+// authorises the origin at this length, invalid when VRPs cover the prefix
+// but none does, and not-found when no VRP covers it. A VRP for AS0 never
+// matches a route.
+func (s *Set) Validate(prefix netip.Prefix, origin uint32) Validation {
+	validation := Validation{Prefix: prefix.Masked(), Origin: origin, State: NotFound}
+
+	covering := s.Covering(prefix)
+	if len(covering) == 0 {
+		return validation
+	}
+
+	originMatched := false
+	for _, vrp := range covering {
+		if vrp.ASN != origin || origin == 0 {
+			continue
+		}
+		originMatched = true
+		if prefix.Bits() <= vrp.MaxLength {
+			validation.VRPs = append(validation.VRPs, vrp)
+		}
+	}
+	if len(validation.VRPs) > 0 {
+		validation.State = Valid
+		return validation
+	}
+
+	validation.State = Invalid
+	validation.Reason = ReasonOrigin
+	if originMatched {
+		validation.Reason = ReasonMaxLength
+	}
+	validation.VRPs = covering
+	return validation
+}
@@ -0,0 +1,77 @@
+package rpki
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+)
+
+// Routinator writes "AS64500" strings, rpki-client plain numbers
+const sampleExport = `{
+  "metadata": {"generated": 1760400000},
+  "roas": [
+    {"asn": "AS13335", "prefix": "1.0.0.0/24", "maxLength": 24, "ta": "apnic"},
+    {"asn": 15169, "prefix": "8.8.8.0/24", "maxLength": 24, "ta": "arin"},
+    {"asn": "AS64500", "prefix": "192.0.2.0/23", "maxLength": 23, "ta": "ripe"},
+    {"asn": "AS64501", "prefix": "192.0.2.0/23", "maxLength": 24, "ta": "ripe"},
+    {"asn": "AS0", "prefix": "198.51.100.0/24", "maxLength": 32, "ta": "ripe"},
+    {"asn": "AS15169", "prefix": "2001:4860::/32", "maxLength": 48, "source": [{"type": "roa", "tal": "arin"}]}
+  ]
+}`
+
+func TestValidate(t *testing.T) {
+	set, err := Read(strings.NewReader(sampleExport))
+	if err != nil {
+		t.Fatalf("Read() unexpected error: %v", err)
+	}
+	if set.Len() != 6 {
+		t.Errorf("Len() = %d, want 6", set.Len())
+	}
+
+	tests := []struct {
+		prefix       string
+		origin       uint32
+		expectState  State
+		expectReason string
+		expectVRPs   int
+	}{
+		{"8.8.8.0/24", 15169, Valid, "", 1},
+		{"8.8.8.0/24", 64496, Invalid, ReasonOrigin, 1},
+		{"8.8.8.128/25", 15169, Invalid, ReasonMaxLength, 1},
+		{"192.0.2.0/24", 64501, Valid, "", 1},
+		{"192.0.2.0/24", 64500, Invalid, ReasonMaxLength, 2},
+		{"192.0.2.0/23", 64500, Valid, "", 1},
+		{"198.51.100.0/24", 0, Invalid, ReasonOrigin, 1},
+		{"198.51.100.0/24", 64502, Invalid, ReasonOrigin, 1},
+		{"2001:4860:4860::/48", 15169, Valid, "", 1},
+		{"9.9.9.0/24", 19281, NotFound, "", 0},
+		{"8.8.0.0/16", 15169, NotFound, "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			validation := set.Validate(netip.MustParsePrefix(tt.prefix), tt.origin)
+			if validation.State != tt.expectState || validation.Reason != tt.expectReason || len(validation.VRPs) != tt.expectVRPs {
+				t.Errorf("Validate(%s, AS%d) = %s %q with %d VRPs, want %s %q with %d", tt.prefix, tt.origin,
+					validation.State, validation.Reason, len(validation.VRPs), tt.expectState, tt.expectReason, tt.expectVRPs)
+			}
+		})
+	}
+
+	if vrp := set.Covering(netip.MustParsePrefix("2001:4860::/32"))[0]; vrp.TrustAnchor != "arin" || vrp.String() != "2001:4860::/32-48 AS15169" {
+		t.Errorf("Covering(2001:4860::/32) = %s from %q", vrp, vrp.TrustAnchor)
+	}
+}
+
+func TestRead_Invalid(t *testing.T) {
+	for _, export := range []string{
+		`{"roas": [{"asn": "AS64500", "prefix": "192.0.2.0/24", "maxLength": 16}]}`,
+		`{"roas": [{"asn": "ASX", "prefix": "192.0.2.0/24", "maxLength": 24}]}`,
+		`{"roas": [{"asn": 64500, "prefix": "192.0.2.0", "maxLength": 24}]}`,
+		`not json`,
+	} {
+		if _, err := Read(strings.NewReader(export)); err == nil {
+			t.Errorf("Read(%s) expected an error", export)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+// Package rpsl parses the RPSL objects (RFC 2622) that RIR WHOIS servers and
+// IRR databases return, and resolves the references between them
+package rpsl
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Attribute is one "name: value" line of an object, with continuation lines joined
+type Attribute struct {
+	Name  string
+	Value string
+}
+
+// Object is an RPSL object. Its class is the name of its first attribute.
+type Object struct {
+	Attributes []Attribute
+}
+
+// Response is a WHOIS response split into objects
+type Response struct {
+	Objects  []Object
+	Comments []string // "%" and "#" lines, without the marker
+}
+
+// handleAttributes name the attributes other objects use to refer to an object
+// besides its class key: RIPE-style nic-hdl and ARIN's OrgId and POC handles
+var handleAttributes = []string{"nic-hdl", "nic-hdl-br", "orgid", "orgabusehandle", "orgtechhandle", "orgnochandle", "ashandle", "nethandle"}
+
+// arinReference matches ARIN's "Google LLC (GOGL)" style references
+var arinReference = regexp.MustCompile(`\(([^()\s]+)\)\s*$`)
+
+// abuseComment matches the abuse contact note RIPE, APNIC and AFRINIC add as a comment
+var abuseComment = regexp.MustCompile(`(?i)^abuse contact for '([^']*)' is '([^']+)'`)
+
+// asSetName matches as-set names (RFC 2622 section 5.1), including hierarchical
+// names made of AS numbers and set names such as "AS15169:AS-GOOGLE"
+var asSetName = regexp.MustCompile(`(?i)^((AS\d+|AS-[A-Z0-9_-]+):)*AS-[A-Z0-9_-]+(:(AS\d+|AS-[A-Z0-9_-]+))*$`)
+
+// IsASSetName reports whether a word names an as-set rather than an AS number
+func IsASSetName(word string) bool {
+	return asSetName.MatchString(word)
+}
+
+// Parse splits a response into objects. Attribute names are lowercased so
+// ARIN's "OrgName" and RIPE's "org-name" styles can be looked up alike.
+// Lines starting with whitespace or "+" continue the previous value.
+func Parse(text string) *Response {
+	response := &Response{}
+	var current *Object
+
+	flush := func() {
+		if current != nil && len(current.Attributes) > 0 {
+			response.Objects = append(response.Objects, *current)
+		}
+		current = nil
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			flush()
+			continue
+		case strings.HasPrefix(trimmed, "%") || strings.HasPrefix(trimmed, "#"):
+			response.Comments = append(response.Comments, strings.TrimSpace(strings.TrimLeft(trimmed, "%#")))
+			continue
+		}
+
+		// Continuation of the previous attribute
+		if current != nil && len(current.Attributes) > 0 && (line[0] == ' ' || line[0] == '\t' || line[0] == '+') {
+			last := &current.Attributes[len(current.Attributes)-1]
+			value := strings.TrimSpace(strings.TrimPrefix(trimmed, "+"))
+			if last.Value == "" {
+				last.Value = value
+			} else if value != "" {
+				last.Value += " " + value
+			}
+			continue
+		}
+
+		name, value, ok := strings.Cut(trimmed, ":")
+		if !ok || strings.ContainsAny(name, " \t") {
+			continue
+		}
+		if current == nil {
+			current = &Object{}
+		}
+		current.Attributes = append(current.Attributes, Attribute{
+			Name:  strings.ToLower(name),
+			Value: strings.TrimSpace(value),
+		})
+	}
+	flush()
+
+	return response
+}
+
+// Class returns the object's class, e.g. "aut-num" or "netrange"
+func (o Object) Class() string {
+	if len(o.Attributes) == 0 {
+		return ""
+	}
+	return o.Attributes[0].Name
+}
+
+// Key returns the value of the object's first attribute
+func (o Object) Key() string {
+	if len(o.Attributes) == 0 {
+		return ""
+	}
+	return o.Attributes[0].Value
+}
+
+// Get returns the first non-empty value of the named attributes, in order
+func (o Object) Get(names ...string) string {
+	for _, name := range names {
+		for _, attr := range o.Attributes {
+			if attr.Name == name && attr.Value != "" {
+				return attr.Value
+			}
+		}
+	}
+	return ""
+}
+
+// GetAll returns every value of an attribute, including repeats
+func (o Object) GetAll(name string) []string {
+	var values []string
+	for _, attr := range o.Attributes {
+		if attr.Name == name && attr.Value != "" {
+			values = append(values, attr.Value)
+		}
+	}
+	return values
+}
+
+// Has reports whether the object has the attribute
+func (o Object) Has(name string) bool {
+	for _, attr := range o.Attributes {
+		if attr.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Find returns the objects of any of the given classes, in response order
+func (r *Response) Find(classes ...string) []Object {
+	var objects []Object
+	for _, object := range r.Objects {
+		for _, class := range classes {
+			if object.Class() == class {
+				objects = append(objects, object)
+				break
+			}
+		}
+	}
+	return objects
+}
+
+// Lookup returns the object a handle refers to: an object whose key or
+// nic-hdl style handle matches, ignoring case
+func (r *Response) Lookup(handle string) (Object, bool) {
+	handle = strings.TrimSpace(handle)
+	if m := arinReference.FindStringSubmatch(handle); m != nil {
+		handle = m[1]
+	}
+	if handle == "" {
+		return Object{}, false
+	}
+
+	for _, object := range r.Objects {
+		if strings.EqualFold(object.Key(), handle) {
+			return object, true
+		}
+		for _, attr := range handleAttributes {
+			if strings.EqualFold(object.Get(attr), handle) {
+				return object, true
+			}
+		}
+	}
+	return Object{}, false
+}
+
+// Resolve returns the objects referenced by an attribute of an object, such
+// as the role objects behind "abuse-c" or the organisation behind "org"
+func (r *Response) Resolve(object Object, name string) []Object {
+	var objects []Object
+	for _, value := range object.GetAll(name) {
+		if referenced, ok := r.Lookup(value); ok {
+			objects = append(objects, referenced)
+		}
+	}
+	return objects
+}
+
+// AbuseComment returns the address from the "Abuse contact for ... is ..."
+// comment about an object. A response with a single such comment is assumed
+// to be about the object queried.
+func (r *Response) AbuseComment(object Object) string {
+	var only []string
+	for _, comment := range r.Comments {
+		m := abuseComment.FindStringSubmatch(comment)
+		if m == nil || !strings.Contains(m[2], "@") {
+			continue
+		}
+		if strings.EqualFold(m[1], object.Key()) {
+			return m[2]
+		}
+		only = append(only, m[2])
+	}
+	if len(only) == 1 {
+		return only[0]
+	}
+	return ""
+}
+
+// Following returns the objects after the given one, in response order. ARIN
+// lists an organization's POC records after it without referencing them.
+func (r *Response) Following(object Object) []Object {
+	if len(object.Attributes) == 0 {
+		return nil
+	}
+	for i := range r.Objects {
+		if len(r.Objects[i].Attributes) > 0 && &r.Objects[i].Attributes[0] == &object.Attributes[0] {
+			return r.Objects[i+1:]
+		}
+	}
+	return nil
+}
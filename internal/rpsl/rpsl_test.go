@@ -0,0 +1,142 @@
+package rpsl
+
+import (
+	"reflect"
+	"testing"
+)
+
+const sample = `% This is the RIPE Database query service.
+% Abuse contact for 'AS3333' is 'abuse@ripe.net'
+
+aut-num:        AS3333
+as-name:        RIPE-NCC-AS
+org:            ORG-RIEN1-RIPE
+import:         from AS1 accept ANY
+import:         from AS2
++               accept AS2
+remarks:        first line
+                second line
+abuse-c:        OPS4-RIPE
+
+organisation:   ORG-RIEN1-RIPE
+org-name:       Reseaux IP Europeens Network Coordination Centre (RIPE NCC)
+
+role:           RIPE NCC Operations
+nic-hdl:        OPS4-RIPE
+abuse-mailbox:  abuse@ripe.net
+`
+
+func TestParse(t *testing.T) {
+	response := Parse(sample)
+
+	if len(response.Objects) != 3 {
+		t.Fatalf("len(Objects) = %d, want 3", len(response.Objects))
+	}
+	autnum := response.Objects[0]
+	if autnum.Class() != "aut-num" || autnum.Key() != "AS3333" {
+		t.Errorf("Objects[0] = %s %s, want aut-num AS3333", autnum.Class(), autnum.Key())
+	}
+
+	expectedImports := []string{"from AS1 accept ANY", "from AS2 accept AS2"}
+	if imports := autnum.GetAll("import"); !reflect.DeepEqual(imports, expectedImports) {
+		t.Errorf("GetAll(import) = %q, want %q", imports, expectedImports)
+	}
+	if remarks := autnum.Get("remarks"); remarks != "first line second line" {
+		t.Errorf("Get(remarks) = %q, want continuation lines joined", remarks)
+	}
+	if got := autnum.Get("descr", "as-name"); got != "RIPE-NCC-AS" {
+		t.Errorf("Get(descr, as-name) = %q, want RIPE-NCC-AS", got)
+	}
+	if autnum.Has("org-name") {
+		t.Error("aut-num picked up an attribute from the organisation object")
+	}
+
+	if email := response.AbuseComment(autnum); email != "abuse@ripe.net" {
+		t.Errorf("AbuseComment() = %q, want abuse@ripe.net", email)
+	}
+}
+
+func TestResponse_Resolve(t *testing.T) {
+	response := Parse(sample)
+	autnum := response.Find("aut-num")[0]
+
+	orgs := response.Resolve(autnum, "org")
+	if len(orgs) != 1 || orgs[0].Get("org-name") != "Reseaux IP Europeens Network Coordination Centre (RIPE NCC)" {
+		t.Errorf("Resolve(org) = %+v", orgs)
+	}
+	roles := response.Resolve(autnum, "abuse-c")
+	if len(roles) != 1 || roles[0].Get("abuse-mailbox") != "abuse@ripe.net" {
+		t.Errorf("Resolve(abuse-c) = %+v", roles)
+	}
+	if len(response.Resolve(autnum, "mnt-by")) != 0 {
+		t.Error("Resolve(mnt-by) found an object that isn't in the response")
+	}
+
+	if following := response.Following(autnum); len(following) != 2 || following[0].Class() != "organisation" {
+		t.Errorf("Following(aut-num) = %+v", following)
+	}
+}
+
+func TestResponse_LookupARIN(t *testing.T) {
+	response := Parse(`
+NetRange:       192.0.2.0 - 192.0.2.255
+Organization:   Example Org (EXAMPLE-1)
+
+OrgName:        Example Org
+OrgId:          EXAMPLE-1
+`)
+
+	network := response.Find("netrange")[0]
+	if network.Get("netrange") != "192.0.2.0 - 192.0.2.255" {
+		t.Errorf("attribute names not lowercased: %+v", network)
+	}
+	org, ok := response.Lookup(network.Get("organization"))
+	if !ok || org.Get("orgname") != "Example Org" {
+		t.Errorf("Lookup(%q) = %+v, %v", network.Get("organization"), org, ok)
+	}
+}
+
+func TestResponse_AbuseComment(t *testing.T) {
+	response := Parse(`% Abuse contact for '192.0.0.0 - 192.0.255.255' is 'abuse@lir.example'
+
+inetnum:        192.0.0.0 - 192.0.255.255
+
+% Abuse contact for '192.0.2.0 - 192.0.2.255' is 'abuse@customer.example'
+
+inetnum:        192.0.2.0 - 192.0.2.255
+`)
+
+	networks := response.Find("inetnum")
+	if email := response.AbuseComment(networks[0]); email != "abuse@lir.example" {
+		t.Errorf("AbuseComment(%s) = %q, want abuse@lir.example", networks[0].Key(), email)
+	}
+	if email := response.AbuseComment(networks[1]); email != "abuse@customer.example" {
+		t.Errorf("AbuseComment(%s) = %q, want abuse@customer.example", networks[1].Key(), email)
+	}
+	if email := response.AbuseComment(Object{}); email != "" {
+		t.Errorf("AbuseComment() with several comments = %q, want none", email)
+	}
+}
+
+func TestIsASSetName(t *testing.T) {
+	tests := []struct {
+		word     string
+		expected bool
+	}{
+		{"AS-GOOGLE", true},
+		{"as-example", true},
+		{"AS15169:AS-GOOGLE", true},
+		{"AS-FOO:AS-BAR:AS64500", true},
+		{"AS15169", false},
+		{"ANY", false},
+		{"AS15169:AS64500", false},
+		{"AS-", false},
+		{"RS-EXAMPLE", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsASSetName(tt.word); got != tt.expected {
+			t.Errorf("IsASSetName(%q) = %v, want %v", tt.word, got, tt.expected)
+		}
+	}
+}